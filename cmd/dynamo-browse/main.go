@@ -8,14 +8,13 @@ import (
 	"net"
 	"os"
 
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lmika/dynamo-browse/internal/common/ui/commandctrl"
 	"github.com/lmika/dynamo-browse/internal/common/ui/logging"
 	"github.com/lmika/dynamo-browse/internal/common/ui/osstyle"
 	"github.com/lmika/dynamo-browse/internal/common/workspaces"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/controllers"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/queryexpr"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/providers/dynamo"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/providers/inputhistorystore"
@@ -39,20 +38,21 @@ import (
 func main() {
 	var flagTable = flag.String("t", "", "dynamodb table name")
 	var flagLocal = flag.String("local", "", "local endpoint")
+	var flagProfile = flag.String("profile", "", "AWS profile to use")
+	var flagRegion = flag.String("region", "", "AWS region to use")
 	var flagDebug = flag.String("debug", "", "file to log debug messages")
 	var flagRO = flag.Bool("ro", false, "enable readonly mode")
 	var flagDefaultLimit = flag.Int("default-limit", 0, "default limit for queries and scans")
 	var flagWorkspace = flag.String("w", "", "workspace file")
 	var flagQuery = flag.String("q", "", "run query")
+	var flagFormat = flag.String("format", "csv", "output format of -q: csv, jsonl or ddbjson")
+	var flagCommands = flag.String("c", "", "run commands, separated by semicolons, without starting the UI")
+	var flagScript = flag.String("script", "", "run script without starting the UI")
+	var flagYes = flag.Bool("yes", false, "answer yes to confirmation prompts when running -c or -script")
 	flag.Parse()
 
 	ctx := context.Background()
 
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		cli.Fatalf("cannot load AWS config: %v", err)
-	}
-
 	closeFn := logging.EnableLogging(*flagDebug)
 	defer closeFn()
 
@@ -63,7 +63,7 @@ func main() {
 	}
 	defer ws.Close()
 
-	var dynamoClient *dynamodb.Client
+	conn := models.Connection{Profile: *flagProfile, Region: *flagRegion}
 	if *flagLocal != "" {
 		host, port, err := net.SplitHostPort(*flagLocal)
 		if err != nil {
@@ -75,16 +75,17 @@ func main() {
 		if port == "" {
 			port = "8000"
 		}
-		dynamoClient = dynamodb.NewFromConfig(cfg,
-			dynamodb.WithEndpointResolver(dynamodb.EndpointResolverFromURL(fmt.Sprintf("http://%v:%v", host, port))))
-	} else {
-		dynamoClient = dynamodb.NewFromConfig(cfg)
+		conn.Endpoint = fmt.Sprintf("http://%v:%v", host, port)
+	}
+
+	dynamoProvider, err := dynamo.NewProviderForConnection(ctx, conn)
+	if err != nil {
+		cli.Fatalf("cannot load AWS config: %v", err)
 	}
 
 	eventBus := bus.New()
 
 	uiStyles := styles.DefaultStyles
-	dynamoProvider := dynamo.NewProvider(dynamoClient)
 	resultSetSnapshotStore := workspacestore.NewResultSetSnapshotStore(ws)
 	settingStore := settingstore.New(ws)
 	inputHistoryStore := inputhistorystore.NewInputHistoryStore(ws)
@@ -108,8 +109,13 @@ func main() {
 	jobsService := jobs.NewService(eventBus)
 	inputHistoryService := inputhistory.New(inputHistoryStore)
 
+	isBatch := *flagCommands != "" || *flagScript != ""
+	if isBatch && *flagTable == "" {
+		cli.Fatalf("-t will need to be set for -c or -script")
+	}
+
 	state := controllers.NewState()
-	jobsController := controllers.NewJobsController(jobsService, eventBus, false)
+	jobsController := controllers.NewJobsController(jobsService, eventBus, isBatch)
 	tableReadController := controllers.NewTableReadController(
 		state,
 		tableService,
@@ -127,7 +133,7 @@ func main() {
 	exportController := controllers.NewExportController(state, tableService, jobsController, columnsController, pasteboardProvider)
 	settingsController := controllers.NewSettingsController(settingStore, eventBus)
 	keyBindings := keybindings.Default()
-	scriptController := controllers.NewScriptController(scriptManagerService, tableReadController, jobsController, settingsController, eventBus)
+	scriptController := controllers.NewScriptController(scriptManagerService, tableReadController, tableWriteController, columnsController, jobsController, settingsController, eventBus)
 
 	if *flagQuery != "" {
 		if *flagTable == "" {
@@ -136,6 +142,11 @@ func main() {
 
 		ctx := context.Background()
 
+		format, err := controllers.ParseExportFormat(*flagFormat)
+		if err != nil {
+			cli.Fatalf("format: %v", err)
+		}
+
 		query, err := queryexpr.Parse(*flagQuery)
 		if err != nil {
 			cli.Fatalf("query: %v", err)
@@ -150,7 +161,7 @@ func main() {
 		if err != nil {
 			cli.Fatalf("cannot execute query: %v", err)
 		}
-		if err := exportController.ExportToWriter(os.Stdout, rs); err != nil {
+		if err := exportController.ExportToWriter(os.Stdout, rs, format); err != nil {
 			cli.Fatalf("cannot export results of query: %v", err)
 		}
 		return
@@ -179,6 +190,28 @@ func main() {
 		keyBindings,
 	)
 
+	if isBatch {
+		batchRunner := ui.NewBatchRunner(model, os.Stderr, *flagYes)
+		jobsController.SetMessageSender(batchRunner.SendMsg)
+		scriptController.Init()
+		scriptController.SetMessageSender(batchRunner.SendMsg)
+
+		if err := batchRunner.Init(); err != nil {
+			cli.Fatalf("cannot open table: %v", err)
+		}
+		if *flagCommands != "" {
+			if err := batchRunner.ExecuteCommands(commandctrl.SplitCommands(*flagCommands)); err != nil {
+				cli.Fatalf("error: %v", err)
+			}
+		}
+		if *flagScript != "" {
+			if err := batchRunner.RunScript(*flagScript); err != nil {
+				cli.Fatalf("error: %v", err)
+			}
+		}
+		return
+	}
+
 	// Pre-determine if layout has dark background.  This prevents calls for creating a list to hang.
 	osstyle.DetectCurrentScheme()
 
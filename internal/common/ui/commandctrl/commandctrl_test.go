@@ -28,3 +28,21 @@ type mockIterProvider struct {
 func (m mockIterProvider) Iter(ctx context.Context, category string) services.HistoryProvider {
 	return nil
 }
+
+func TestSplitCommands(t *testing.T) {
+	scenarios := []struct {
+		line     string
+		expected []string
+	}{
+		{line: "", expected: nil},
+		{line: "put", expected: []string{"put"}},
+		{line: "mark all; put", expected: []string{"mark all", "put"}},
+		{line: ` mark all -where "a = 'b;c'" ;; set-attr -S status 'x;y' `, expected: []string{`mark all -where "a = 'b;c'"`, `set-attr -S status 'x;y'`}},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.line, func(t *testing.T) {
+			assert.Equal(t, scenario.expected, commandctrl.SplitCommands(scenario.line))
+		})
+	}
+}
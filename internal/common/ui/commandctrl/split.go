@@ -0,0 +1,38 @@
+package commandctrl
+
+import "strings"
+
+// SplitCommands splits a line of commands separated by semicolons.  Semicolons appearing within single or double
+// quotes are not treated as separators.  Empty commands are dropped.
+func SplitCommands(line string) []string {
+	var (
+		commands  []string
+		current   strings.Builder
+		quoteChar rune
+	)
+
+	addCommand := func() {
+		if cmd := strings.TrimSpace(current.String()); cmd != "" {
+			commands = append(commands, cmd)
+		}
+		current.Reset()
+	}
+
+	for _, r := range line {
+		switch {
+		case quoteChar != 0:
+			if r == quoteChar {
+				quoteChar = 0
+			}
+		case r == '"' || r == '\'':
+			quoteChar = r
+		case r == ';':
+			addCommand()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	addCommand()
+
+	return commands
+}
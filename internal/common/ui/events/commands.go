@@ -32,14 +32,18 @@ func PromptForInput(prompt string, history services.HistoryProvider, onDone func
 }
 
 func Confirm(prompt string, onResult func(yes bool) tea.Msg) tea.Msg {
-	return PromptForInput(prompt, nil, func(value string) tea.Msg {
-		return onResult(value == "y")
-	})
+	return PromptForInputMsg{
+		Prompt: prompt,
+		OnDone: func(value string) tea.Msg {
+			return onResult(value == "y")
+		},
+		IsConfirmation: true,
+	}
 }
 
 func ConfirmYes(prompt string, onYes func() tea.Msg) tea.Msg {
-	return PromptForInput(prompt, nil, func(value string) tea.Msg {
-		if value == "y" {
+	return Confirm(prompt, func(yes bool) tea.Msg {
+		if yes {
 			return onYes()
 		}
 		return nil
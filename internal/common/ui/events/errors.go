@@ -26,4 +26,7 @@ type PromptForInputMsg struct {
 	OnDone        func(value string) tea.Msg
 	OnCancel      func() tea.Msg
 	OnTabComplete func(value string) (string, bool)
+
+	// IsConfirmation indicates that the prompt is a yes/no question, with "y" being the answer for yes
+	IsConfirmation bool
 }
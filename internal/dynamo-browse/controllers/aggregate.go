@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lmika/dynamo-browse/internal/common/ui/events"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/aggregate"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/queryexpr"
+)
+
+// Aggregate applies the aggregation to all the items of the current table matching the where expression.  If
+// the where expression is empty, the query of the current result set is used.  The aggregation is displayed as
+// a new result set.
+func (c *TableReadController) Aggregate(agg aggregate.Aggregation, where string) tea.Msg {
+	resultSet := c.state.ResultSet()
+	if resultSet == nil {
+		return events.StatusMsg("Result-set is nil")
+	}
+
+	query := resultSet.Query
+	if where != "" {
+		q, err := queryexpr.Parse(where)
+		if err != nil {
+			return events.Error(err)
+		}
+		query = q
+	}
+
+	return c.doIfNoneDirty(func() tea.Msg {
+		return NewJob(c.jobController, "Aggregating…", func(ctx context.Context) (*models.ResultSet, error) {
+			return c.tableService.Aggregate(ctx, resultSet.TableInfo, query, agg)
+		}).OnDone(func(aggResultSet *models.ResultSet) tea.Msg {
+			// The aggregate result set cannot be restored, so it's not pushed to the backstack
+			return c.setResultSetAndFilter(aggResultSet, "", false, resultSetUpdateQuery)
+		}).Submit()
+	})
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
+	"github.com/lmika/dynamo-browse/internal/common/ui/events"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/queryexpr"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/tables"
+	"github.com/pkg/errors"
+)
+
+// BulkDelete deletes all the items of the current table matching the where expression, without loading them into
+// the result set.  The matching items are counted and the delete confirmed before any items are deleted.
+func (twc *TableWriteController) BulkDelete(where string) tea.Msg {
+	return twc.runBulkOperation(where, "Deleting items…", "delete", "deleted", twc.tableService.BulkDelete)
+}
+
+// BulkUpdate sets attributes of all the items of the current table matching the where expression, without loading
+// them into the result set.  The set is a comma separated list of "attr = expr" pairs, with expr being either a
+// value, an attribute, or the sum or difference of the two.  The matching items are counted and the update
+// confirmed before any items are updated.
+func (twc *TableWriteController) BulkUpdate(where string, set string) tea.Msg {
+	remaps, err := parseRemaps(set)
+	if err != nil {
+		return events.Error(err)
+	} else if len(remaps) == 0 {
+		return events.Error(errors.New("expected attributes to set"))
+	}
+
+	sets := sliceutils.Map(remaps, func(r attrRemap) tables.AttributeSet {
+		return tables.AttributeSet{Name: r.attr, Value: r.expr}
+	})
+	return twc.runBulkOperation(where, "Updating items…", "update", "updated", func(ctx context.Context, tableInfo *models.TableInfo, query models.Queryable) (int, error) {
+		return twc.tableService.BulkUpdate(ctx, tableInfo, query, sets)
+	})
+}
+
+func (twc *TableWriteController) runBulkOperation(
+	where string,
+	jobDescription string,
+	verb, pastVerb string,
+	op func(ctx context.Context, tableInfo *models.TableInfo, query models.Queryable) (int, error),
+) tea.Msg {
+	if err := twc.assertReadWrite(); err != nil {
+		return events.Error(err)
+	}
+
+	resultSet := twc.state.ResultSet()
+	if resultSet == nil {
+		return events.Error(errors.New("no result set"))
+	} else if strings.TrimSpace(where) == "" {
+		return events.Error(errors.New("expected where expression"))
+	}
+	tableInfo := resultSet.TableInfo
+
+	query, err := queryexpr.Parse(where)
+	if err != nil {
+		return events.Error(err)
+	}
+
+	return NewJob(twc.jobController, "Counting items…", func(ctx context.Context) (int64, error) {
+		count, err := twc.tableService.Count(ctx, tableInfo, query)
+		return count, errors.Wrapf(err, "cannot %v items", verb)
+	}).OnDone(func(count int64) tea.Msg {
+		if count == 0 {
+			return events.StatusMsg("no items match")
+		}
+
+		return events.Confirm(applyToN(verb+" ", int(count), "item", "items", " of "+tableInfo.Name+"? "), func(yes bool) tea.Msg {
+			if !yes {
+				return abortOperation()
+			}
+
+			return NewJob(twc.jobController, jobDescription, func(ctx context.Context) (int, error) {
+				return op(ctx, tableInfo, query)
+			}).OnEither(func(n int, err error) tea.Msg {
+				switch {
+				case errors.Is(err, context.Canceled):
+					return events.StatusMsg(applyToN(verb+" cancelled: ", n, "item", "items", " "+pastVerb))
+				case err != nil:
+					return events.Error(errors.Wrap(err, applyToN("", n, "item", "items", " "+pastVerb+" before error")))
+				}
+				return events.StatusMsg(applyToN("", n, "item", "items", " "+pastVerb))
+			}).Submit()
+		})
+	}).Submit()
+}
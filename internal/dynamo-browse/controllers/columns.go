@@ -14,6 +14,9 @@ import (
 type ColumnsController struct {
 	tr *TableReadController
 
+	// scriptColumns returns the columns defined by scripts for a table
+	scriptColumns func(tableInfo *models.TableInfo) []columns.Column
+
 	// State
 	colModel  *columns.Columns
 	resultSet *models.ResultSet
@@ -60,7 +63,7 @@ func (cc *ColumnsController) ShiftColumnRight(idx int) tea.Msg {
 }
 
 func (cc *ColumnsController) SetColumnsToResultSet() tea.Msg {
-	cc.colModel = columns.NewColumnsFromResultSet(cc.resultSet)
+	cc.colModel = cc.newColumns(cc.resultSet)
 	return ColumnsUpdated{}
 }
 
@@ -68,12 +71,28 @@ func (cc *ColumnsController) onNewResultSet(rs *models.ResultSet, op resultSetUp
 	cc.resultSet = rs
 
 	if cc.colModel == nil || (op == resultSetUpdateInit || op == resultSetUpdateQuery) {
-		cc.colModel = columns.NewColumnsFromResultSet(rs)
+		cc.colModel = cc.newColumns(rs)
 	} else {
 		cc.colModel.AddMissingColumns(rs)
 	}
 }
 
+// newColumns returns the columns of the result set, followed by any columns defined by scripts.
+func (cc *ColumnsController) newColumns(rs *models.ResultSet) *columns.Columns {
+	colModel := columns.NewColumnsFromResultSet(rs)
+	if cc.scriptColumns == nil {
+		return colModel
+	}
+
+	if scriptCols := cc.scriptColumns(rs.TableInfo); len(scriptCols) > 0 {
+		colModel.Columns = append(colModel.Columns, scriptCols...)
+
+		// Keep the script columns when adding missing columns
+		colModel.WasRearranged = true
+	}
+	return colModel
+}
+
 func (cc *ColumnsController) AddColumn(afterIndex int) tea.Msg {
 	return events.PromptForInput("column expr: ", nil, func(value string) tea.Msg {
 		colExpr, err := queryexpr.Parse(value)
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lmika/dynamo-browse/internal/common/ui/events"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/itemrender"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/queryexpr"
+	"github.com/pkg/errors"
+)
+
+// CopyItemsTo copies the marked items, or the selected item if none are marked, to another table.  The remap
+// is a comma separated list of "attr=expr" pairs, which sets attr of each copied item to the value of expr
+// evaluated against the original item.  This can be used to rewrite the key attributes of the copied items.
+func (twc *TableWriteController) CopyItemsTo(idx int, tableName string, remap string) tea.Msg {
+	if err := twc.assertReadWrite(); err != nil {
+		return events.Error(err)
+	}
+
+	remaps, err := parseRemaps(remap)
+	if err != nil {
+		return events.Error(err)
+	}
+
+	resultSet := twc.state.ResultSet()
+	if resultSet == nil {
+		return events.Error(errors.New("no result set"))
+	} else if idx < 0 || idx >= len(resultSet.Items()) {
+		return events.StatusMsg("no item selected")
+	}
+
+	var itemsToCopy []models.Item
+	if err := applyToMarkedItems(resultSet, idx, func(idx int, item models.Item) error {
+		copiedItem, err := remapItem(item, remaps)
+		if err != nil {
+			return errors.Wrapf(err, "item %v", describeItemKey(resultSet.TableInfo, item))
+		}
+		itemsToCopy = append(itemsToCopy, copiedItem)
+		return nil
+	}); err != nil {
+		return events.Error(err)
+	}
+
+	return NewJob(twc.jobController, "Fetching table info…", func(ctx context.Context) (*models.TableInfo, error) {
+		return twc.tableService.Describe(ctx, tableName)
+	}).OnDone(func(targetTableInfo *models.TableInfo) tea.Msg {
+		itemDiffs := make([]ItemDiff, len(itemsToCopy))
+		for i, item := range itemsToCopy {
+			if pk, sk := item.PKSK(targetTableInfo); pk == nil || (targetTableInfo.Keys.SortKey != "" && sk == nil) {
+				return events.Error(errors.Errorf("item %d is missing key attributes of table '%v'", i+1, tableName))
+			}
+			if err := checkKeyTypes(item, targetTableInfo, ExportFormatJSONLines); err != nil {
+				return events.Error(errors.Wrapf(err, "item %d", i+1))
+			}
+
+			itemDiffs[i] = ItemDiff{
+				Description: describeItemKey(targetTableInfo, item),
+				Diff:        itemrender.Diff(nil, item),
+			}
+		}
+
+		return ShowDiffOverlay{
+			Title:    applyToN("copy ", len(itemsToCopy), "item", "items", " to "+tableName+"? "),
+			Items:    itemDiffs,
+			OnCancel: abortOperation,
+			OnConfirm: func() tea.Msg {
+				return NewJob(twc.jobController, "Copying items…", func(ctx context.Context) (struct{}, error) {
+					return struct{}{}, twc.tableService.PutItems(ctx, targetTableInfo, itemsToCopy)
+				}).OnDone(func(_ struct{}) tea.Msg {
+					return events.StatusMsg(applyToN("", len(itemsToCopy), "item", "items", " copied to "+tableName))
+				}).Submit()
+			},
+		}
+	}).Submit()
+}
+
+type attrRemap struct {
+	attr string
+	expr *queryexpr.QueryExpr
+}
+
+// parseRemaps parses a comma separated list of "attr=expr" pairs.  Commas within strings or brackets of the
+// expression do not separate pairs.
+func parseRemaps(remap string) ([]attrRemap, error) {
+	if strings.TrimSpace(remap) == "" {
+		return nil, nil
+	}
+
+	var (
+		remaps   []attrRemap
+		depth    int
+		inString bool
+		start    int
+	)
+	addRemap := func(pair string) error {
+		attr, exprStr, hasEq := strings.Cut(pair, "=")
+		attr = strings.TrimSpace(attr)
+		if !hasEq || attr == "" || strings.TrimSpace(exprStr) == "" {
+			return errors.Errorf("expected attr=expr: '%v'", strings.TrimSpace(pair))
+		}
+
+		expr, err := queryexpr.Parse(exprStr)
+		if err != nil {
+			return err
+		}
+		remaps = append(remaps, attrRemap{attr: attr, expr: expr})
+		return nil
+	}
+
+	for i := 0; i < len(remap); i++ {
+		switch c := remap[i]; {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			if err := addRemap(remap[start:i]); err != nil {
+				return nil, err
+			}
+			start = i + 1
+		}
+	}
+	if err := addRemap(remap[start:]); err != nil {
+		return nil, err
+	}
+	return remaps, nil
+}
+
+// remapItem returns a copy of the item with the remaps applied.  All expressions are evaluated against the
+// original item, so that attributes can be swapped.
+func remapItem(item models.Item, remaps []attrRemap) (models.Item, error) {
+	copiedItem := item.Clone()
+	for _, r := range remaps {
+		val, err := r.expr.EvalItem(item)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot remap '%v'", r.attr)
+		} else if val == nil {
+			return nil, errors.Errorf("cannot remap '%v': '%v' has no value", r.attr, r.expr.String())
+		}
+		copiedItem[r.attr] = val
+	}
+	return copiedItem, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/itemrender"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/relitems"
 )
 
@@ -20,6 +21,10 @@ type SettingsUpdated struct {
 type ColumnsUpdated struct {
 }
 
+// ScriptValuesUpdated indicates that column or attribute values computed by scripts are available to display.
+type ScriptValuesUpdated struct {
+}
+
 type SetSelectedColumnInColSelector int
 
 type MoveLeftmostDisplayedColumnInTableViewBy int
@@ -29,6 +34,7 @@ type NewResultSet struct {
 	currentFilter string
 	filteredCount int
 	statusMessage string
+	connection    models.Connection
 }
 
 func (rs NewResultSet) ModeMessage() string {
@@ -49,6 +55,11 @@ func (rs NewResultSet) ModeMessage() string {
 func (rs NewResultSet) RightModeMessage() string {
 	var sb strings.Builder
 
+	if !rs.connection.IsZero() {
+		sb.WriteString(rs.connection.String())
+		sb.WriteString(" • ")
+	}
+
 	itemCountStr := applyToN("", len(rs.ResultSet.Items()), "item", "items", "")
 	if rs.currentFilter != "" {
 		sb.WriteString(fmt.Sprintf("%d of %v", rs.filteredCount, itemCountStr))
@@ -93,7 +104,31 @@ type ShowColumnOverlay struct{}
 type HideColumnOverlay struct{}
 
 type ShowRelatedItemsOverlay struct {
+	Title      string
 	Items      []relitems.RelatedItem
 	OnSelected func(item relitems.RelatedItem) tea.Msg
+	OnCancel   func() tea.Msg
 }
 type HideRelatedItemsOverlay struct{}
+
+// ItemDiff holds the differences between an item as it was read from the table and the item as it will be written.
+type ItemDiff struct {
+	Description string
+	Diff        []itemrender.DiffItem
+}
+
+// ShowDiffOverlay shows the changes that will be made to the table and asks the user to confirm them.
+type ShowDiffOverlay struct {
+	Title     string
+	Items     []ItemDiff
+	OnConfirm func() tea.Msg
+	OnCancel  func() tea.Msg
+}
+type HideDiffOverlay struct{}
+
+// ShowTextOverlay shows a block of read-only text in an overlay.
+type ShowTextOverlay struct {
+	Title string
+	Text  string
+}
+type HideTextOverlay struct{}
@@ -3,7 +3,6 @@ package controllers
 import (
 	"bytes"
 	"context"
-	"encoding/csv"
 	"fmt"
 	"io"
 	"os"
@@ -11,7 +10,6 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lmika/dynamo-browse/internal/common/ui/events"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
-	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/columns"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/jobs"
@@ -36,7 +34,7 @@ func NewExportController(
 	return &ExportController{state, tableService, jobsController, columns, pasteboardProvider}
 }
 
-func (c *ExportController) ExportCSV(filename string, opts ExportOptions) tea.Msg {
+func (c *ExportController) Export(filename string, opts ExportOptions) tea.Msg {
 	resultSet := c.state.ResultSet()
 	if resultSet == nil {
 		return events.Error(errors.New("no result set"))
@@ -49,27 +47,15 @@ func (c *ExportController) ExportCSV(filename string, opts ExportOptions) tea.Ms
 		}
 		defer f.Close()
 
-		cw := csv.NewWriter(f)
-		defer cw.Flush()
-
-		columns := c.columns.Columns().VisibleColumns()
-
-		colNames := make([]string, len(columns))
-		for i, c := range columns {
-			colNames[i] = c.Name
-		}
-		if err := cw.Write(colNames); err != nil {
+		iw := newItemWriter(f, opts.Format, c.columns.Columns().VisibleColumns())
+		if err := iw.writeHeader(); err != nil {
 			return 0, errors.Wrapf(err, "cannot export to '%v'", filename)
 		}
 
 		totalRows := 0
-		row := make([]string, len(columns))
 		for {
 			for _, item := range resultSet.Items() {
-				for i, col := range columns {
-					row[i], _ = attrutils.AttributeToString(col.Evaluator.EvaluateForItem(item))
-				}
-				if err := cw.Write(row); err != nil {
+				if err := iw.writeItem(item); err != nil {
 					return 0, errors.Wrapf(err, "cannot export to '%v'", filename)
 				}
 			}
@@ -86,6 +72,9 @@ func (c *ExportController) ExportCSV(filename string, opts ExportOptions) tea.Ms
 			}
 		}
 
+		if err := iw.flush(); err != nil {
+			return 0, errors.Wrapf(err, "cannot export to '%v'", filename)
+		}
 		return totalRows, nil
 	}).OnDone(func(rows int) tea.Msg {
 		return events.StatusMsg(applyToN("Exported ", rows, "item", "items", " to "+filename))
@@ -100,8 +89,8 @@ func (c *ExportController) ExportCSVToClipboard() tea.Msg {
 		return errors.New("no result set")
 	}
 
-	if err := c.exportCSV(&bts, c.columns.Columns().VisibleColumns(), resultSet); err != nil {
-		return events.Error(err)
+	if err := c.exportItems(&bts, ExportFormatCSV, c.columns.Columns().VisibleColumns(), resultSet); err != nil {
+		return events.Error(errors.Wrap(err, "cannot export to clipboard"))
 	}
 
 	if err := c.pasteboardProvider.WriteText(bts.Bytes()); err != nil {
@@ -111,36 +100,69 @@ func (c *ExportController) ExportCSVToClipboard() tea.Msg {
 }
 
 // TODO: this really needs to be a service!
-func (c *ExportController) ExportToWriter(w io.Writer, resultSet *models.ResultSet) error {
-	return c.exportCSV(w, columns.NewColumnsFromResultSet(resultSet).Columns, resultSet)
+func (c *ExportController) ExportToWriter(w io.Writer, resultSet *models.ResultSet, format ExportFormat) error {
+	return c.exportItems(w, format, columns.NewColumnsFromResultSet(resultSet).Columns, resultSet)
 }
 
-func (c *ExportController) exportCSV(w io.Writer, cols []columns.Column, resultSet *models.ResultSet) error {
-	cw := csv.NewWriter(w)
-	defer cw.Flush()
-
-	colNames := make([]string, len(cols))
-	for i, c := range cols {
-		colNames[i] = c.Name
-	}
-	if err := cw.Write(colNames); err != nil {
-		return errors.Wrap(err, "cannot export to clipboard")
+func (c *ExportController) exportItems(w io.Writer, format ExportFormat, cols []columns.Column, resultSet *models.ResultSet) error {
+	iw := newItemWriter(w, format, cols)
+	if err := iw.writeHeader(); err != nil {
+		return err
 	}
 
-	row := make([]string, len(cols))
 	for _, item := range resultSet.Items() {
-		for i, col := range cols {
-			row[i], _ = attrutils.AttributeToString(col.Evaluator.EvaluateForItem(item))
-		}
-		if err := cw.Write(row); err != nil {
-			return errors.Wrap(err, "cannot export to clipboard")
+		if err := iw.writeItem(item); err != nil {
+			return err
 		}
 	}
 
-	return nil
+	return iw.flush()
 }
 
 type ExportOptions struct {
 	// AllResults returns all results from the table
 	AllResults bool
+
+	// Format is the format of the exported items
+	Format ExportFormat
+}
+
+type ImportOptions struct {
+	// Format is the format of the file being imported
+	Format ExportFormat
+}
+
+type ExportFormat int
+
+const (
+	// ExportFormatCSV writes the visible columns as CSV
+	ExportFormatCSV ExportFormat = iota
+
+	// ExportFormatJSONLines writes each item as a plain JSON document, one per line
+	ExportFormatJSONLines
+
+	// ExportFormatDynamoJSON writes each item as typed DynamoDB-JSON, one per line
+	ExportFormatDynamoJSON
+)
+
+func (f ExportFormat) String() string {
+	switch f {
+	case ExportFormatJSONLines:
+		return "jsonl"
+	case ExportFormatDynamoJSON:
+		return "ddbjson"
+	}
+	return "csv"
+}
+
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch s {
+	case "csv":
+		return ExportFormatCSV, nil
+	case "jsonl":
+		return ExportFormatJSONLines, nil
+	case "ddbjson":
+		return ExportFormatDynamoJSON, nil
+	}
+	return 0, errors.Errorf("unrecognised export format: %v", s)
 }
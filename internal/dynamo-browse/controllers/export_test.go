@@ -17,7 +17,7 @@ func TestExportController_ExportCSV(t *testing.T) {
 		tempFile := tempFile(t)
 
 		invokeCommand(t, srv.readController.Init())
-		invokeCommand(t, srv.exportController.ExportCSV(tempFile, controllers.ExportOptions{}))
+		invokeCommand(t, srv.exportController.Export(tempFile, controllers.ExportOptions{}))
 
 		bts, err := os.ReadFile(tempFile)
 		assert.NoError(t, err)
@@ -47,7 +47,7 @@ func TestExportController_ExportCSV(t *testing.T) {
 					})...)
 
 					invokeCommand(t, srv.readController.Init())
-					invokeCommand(t, srv.exportController.ExportCSV(tempFile, controllers.ExportOptions{
+					invokeCommand(t, srv.exportController.Export(tempFile, controllers.ExportOptions{
 						AllResults: true,
 					}))
 
@@ -70,7 +70,7 @@ func TestExportController_ExportCSV(t *testing.T) {
 
 					invokeCommand(t, srv.readController.Init())
 					invokeCommandWithPrompt(t, srv.readController.PromptForQuery(), "num<=15")
-					invokeCommand(t, srv.exportController.ExportCSV(tempFile, controllers.ExportOptions{
+					invokeCommand(t, srv.exportController.Export(tempFile, controllers.ExportOptions{
 						AllResults: true,
 					}))
 
@@ -89,7 +89,7 @@ func TestExportController_ExportCSV(t *testing.T) {
 		tempFile := tempFile(t)
 
 		invokeCommandExpectingError(t, srv.readController.Init())
-		invokeCommandExpectingError(t, srv.exportController.ExportCSV(tempFile, controllers.ExportOptions{}))
+		invokeCommandExpectingError(t, srv.exportController.Export(tempFile, controllers.ExportOptions{}))
 	})
 
 	t.Run("should honour new columns in CSV file", func(t *testing.T) {
@@ -104,7 +104,7 @@ func TestExportController_ExportCSV(t *testing.T) {
 		invokeCommandWithPrompt(t, srv.columnsController.AddColumn(1), "address.street")
 		invokeCommand(t, srv.columnsController.ShiftColumnLeft(1))
 
-		invokeCommand(t, srv.exportController.ExportCSV(tempFile, controllers.ExportOptions{}))
+		invokeCommand(t, srv.exportController.Export(tempFile, controllers.ExportOptions{}))
 
 		bts, err := os.ReadFile(tempFile)
 		assert.NoError(t, err)
@@ -127,7 +127,7 @@ func TestExportController_ExportCSV(t *testing.T) {
 		invokeCommand(t, srv.columnsController.ToggleVisible(1))
 		invokeCommand(t, srv.columnsController.ToggleVisible(2))
 
-		invokeCommand(t, srv.exportController.ExportCSV(tempFile, controllers.ExportOptions{}))
+		invokeCommand(t, srv.exportController.Export(tempFile, controllers.ExportOptions{}))
 
 		bts, err := os.ReadFile(tempFile)
 		assert.NoError(t, err)
@@ -142,3 +142,66 @@ func TestExportController_ExportCSV(t *testing.T) {
 
 	// Hidden items?
 }
+
+func TestExportController_Export(t *testing.T) {
+	t.Run("should export result set as JSON lines", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "bravo-table"})
+
+		tempFile := tempFile(t)
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommand(t, srv.exportController.Export(tempFile, controllers.ExportOptions{
+			Format: controllers.ExportFormatJSONLines,
+		}))
+
+		bts, err := os.ReadFile(tempFile)
+		assert.NoError(t, err)
+
+		assert.Equal(t, strings.Join([]string{
+			`{"alpha":"This is another some value","beta":1231,"pk":"abc","sk":"222"}` + "\n",
+			`{"beta":2468,"gamma":"foobar","pk":"bbb","sk":"131"}` + "\n",
+			`{"alpha":"This is some value","pk":"foo","sk":"bar"}` + "\n",
+		}, ""), string(bts))
+	})
+
+	t.Run("should export result set as DynamoDB JSON", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "bravo-table"})
+
+		tempFile := tempFile(t)
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommand(t, srv.exportController.Export(tempFile, controllers.ExportOptions{
+			Format: controllers.ExportFormatDynamoJSON,
+		}))
+
+		bts, err := os.ReadFile(tempFile)
+		assert.NoError(t, err)
+
+		assert.Equal(t, strings.Join([]string{
+			`{"alpha":{"S":"This is another some value"},"beta":{"N":"1231"},"pk":{"S":"abc"},"sk":{"S":"222"}}` + "\n",
+			`{"beta":{"N":"2468"},"gamma":{"S":"foobar"},"pk":{"S":"bbb"},"sk":{"S":"131"}}` + "\n",
+			`{"alpha":{"S":"This is some value"},"pk":{"S":"foo"},"sk":{"S":"bar"}}` + "\n",
+		}, ""), string(bts))
+	})
+
+	t.Run("should export all pages as JSON lines", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "count-to-30", defaultLimit: 5})
+
+		tempFile := tempFile(t)
+
+		expected := sliceutils.Generate(1, 30, func(i int) string {
+			return fmt.Sprintf(`{"num":%d,"pk":"NUM","sk":"NUM#%02d"}`+"\n", i, i)
+		})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommand(t, srv.exportController.Export(tempFile, controllers.ExportOptions{
+			AllResults: true,
+			Format:     controllers.ExportFormatJSONLines,
+		}))
+
+		bts, err := os.ReadFile(tempFile)
+		assert.NoError(t, err)
+
+		assert.Equal(t, strings.Join(expected, ""), string(bts))
+	})
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrcodec"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/columns"
+)
+
+type itemWriter interface {
+	writeHeader() error
+	writeItem(item models.Item) error
+	flush() error
+}
+
+func newItemWriter(w io.Writer, format ExportFormat, cols []columns.Column) itemWriter {
+	switch format {
+	case ExportFormatJSONLines:
+		return &jsonItemWriter{enc: json.NewEncoder(w), toJSON: attrcodec.MapToPlainJSON}
+	case ExportFormatDynamoJSON:
+		return &jsonItemWriter{enc: json.NewEncoder(w), toJSON: attrcodec.MapToDynamoJSON}
+	}
+	return &csvItemWriter{cw: csv.NewWriter(w), cols: cols, row: make([]string, len(cols))}
+}
+
+// csvItemWriter writes the values of the columns as CSV.
+type csvItemWriter struct {
+	cw   *csv.Writer
+	cols []columns.Column
+	row  []string
+}
+
+func (iw *csvItemWriter) writeHeader() error {
+	colNames := make([]string, len(iw.cols))
+	for i, c := range iw.cols {
+		colNames[i] = c.Name
+	}
+	return iw.cw.Write(colNames)
+}
+
+func (iw *csvItemWriter) writeItem(item models.Item) error {
+	for i, col := range iw.cols {
+		iw.row[i], _ = attrutils.AttributeToString(models.EvaluateForItemSync(col.Evaluator, item))
+	}
+	return iw.cw.Write(iw.row)
+}
+
+func (iw *csvItemWriter) flush() error {
+	iw.cw.Flush()
+	return iw.cw.Error()
+}
+
+// jsonItemWriter writes each item as a single line of JSON.  Unlike CSV, all the attributes of the item are
+// written regardless of the visible columns.
+type jsonItemWriter struct {
+	enc    *json.Encoder
+	toJSON func(ms map[string]types.AttributeValue) (map[string]any, error)
+}
+
+func (iw *jsonItemWriter) writeHeader() error {
+	return nil
+}
+
+func (iw *jsonItemWriter) writeItem(item models.Item) error {
+	doc, err := iw.toJSON(item)
+	if err != nil {
+		return err
+	}
+	return iw.enc.Encode(doc)
+}
+
+func (iw *jsonItemWriter) flush() error {
+	return nil
+}
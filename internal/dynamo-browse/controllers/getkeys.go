@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lmika/dynamo-browse/internal/common/ui/events"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/pkg/errors"
+)
+
+// GetKeys replaces the result set with the items of the current table whose keys are listed in a file.  The keys
+// are read in the same formats as imported items, with any attributes other than the key attributes ignored.  If
+// filename is "-", the keys are read from the pasteboard.
+func (c *TableReadController) GetKeys(filename string) tea.Msg {
+	resultSet := c.state.ResultSet()
+	if resultSet == nil {
+		return events.StatusMsg("Result-set is nil")
+	}
+	tableInfo := resultSet.TableInfo
+
+	var (
+		r      io.Reader
+		format = FormatFromFilename(filename)
+	)
+	if filename == "-" {
+		text, ok := c.pasteboardProvider.ReadText()
+		if !ok {
+			return events.Error(errors.New("no keys in pasteboard"))
+		}
+		if strings.HasPrefix(strings.TrimSpace(text), "{") {
+			format = ExportFormatJSONLines
+		}
+		r = strings.NewReader(text)
+	} else {
+		f, err := os.Open(filename)
+		if err != nil {
+			return events.Error(errors.Wrapf(err, "cannot read keys from '%v'", filename))
+		}
+		defer f.Close()
+		r = f
+	}
+
+	keys, err := readKeys(r, format, tableInfo)
+	if err != nil {
+		return events.Error(errors.Wrapf(err, "cannot read keys from '%v'", filename))
+	} else if len(keys) == 0 {
+		return events.StatusMsg("No keys to get")
+	}
+
+	return c.doIfNoneDirty(func() tea.Msg {
+		return NewJob(c.jobController, fmt.Sprintf("Getting %d items…", len(keys)), func(ctx context.Context) (*models.ResultSet, error) {
+			newResultSet, err := c.tableService.BatchGet(ctx, tableInfo, keys)
+			return c.filterResultSet(newResultSet, c.state.Filter(), err)
+		}).OnEither(c.handleResultSetFromJobResult(c.state.Filter(), false, false, resultSetUpdateQuery)).Submit()
+	})
+}
+
+// readKeys reads the key attributes of each item read from r.
+func readKeys(r io.Reader, format ExportFormat, tableInfo *models.TableInfo) ([]map[string]types.AttributeValue, error) {
+	var (
+		keys = make([]map[string]types.AttributeValue, 0)
+		ir   = newItemReader(r, format)
+	)
+	for {
+		item, err := ir.next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "key %d", len(keys)+1)
+		}
+
+		if pk, sk := item.PKSK(tableInfo); pk == nil || (tableInfo.Keys.SortKey != "" && sk == nil) {
+			return nil, errors.Errorf("key %d is missing key attributes", len(keys)+1)
+		}
+		if err := checkKeyTypes(item, tableInfo, format); err != nil {
+			return nil, errors.Wrapf(err, "key %d", len(keys)+1)
+		}
+		keys = append(keys, item.KeyValue(tableInfo))
+	}
+	return keys, nil
+}
@@ -7,16 +7,23 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/aggregate"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/relitems"
 )
 
 type TableReadService interface {
+	Connection() models.Connection
+	Connect(ctx context.Context, conn models.Connection) error
 	ListTables(background context.Context) ([]string, error)
+	ListTablesOfConnection(ctx context.Context, conn models.Connection) ([]string, error)
 	Describe(ctx context.Context, table string) (*models.TableInfo, error)
+	DescribeInFull(ctx context.Context, table string) (*models.TableInfo, error)
 	Scan(ctx context.Context, tableInfo *models.TableInfo) (*models.ResultSet, error)
-	Filter(resultSet *models.ResultSet, filter string) *models.ResultSet
+	Filter(resultSet *models.ResultSet, filter string) (*models.ResultSet, error)
 	ScanOrQuery(ctx context.Context, tableInfo *models.TableInfo, query models.Queryable, exclusiveStartKey map[string]types.AttributeValue) (*models.ResultSet, error)
 	NextPage(ctx context.Context, resultSet *models.ResultSet) (*models.ResultSet, error)
+	BatchGet(ctx context.Context, tableInfo *models.TableInfo, keys []map[string]types.AttributeValue) (*models.ResultSet, error)
+	Aggregate(ctx context.Context, tableInfo *models.TableInfo, query models.Queryable, agg aggregate.Aggregation) (*models.ResultSet, error)
 }
 
 type SettingsProvider interface {
@@ -24,9 +31,13 @@ type SettingsProvider interface {
 	SetReadOnly(ro bool) error
 	DefaultLimit() (limit int)
 	SetDefaultLimit(limit int) error
+	ScanSegments() int
+	SetScanSegments(segments int) error
 	ScriptLookupFS() ([]fs.FS, error)
 	SetScriptLookupPaths(value string) error
 	ScriptLookupPaths() string
+	PutMode() models.PutMode
+	SetPutMode(putMode models.PutMode) error
 }
 
 type CustomKeyBindingSource interface {
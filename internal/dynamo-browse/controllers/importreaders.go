@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrcodec"
+	"github.com/pkg/errors"
+)
+
+// FormatFromFilename guesses the format of a file from its extension, defaulting to CSV.
+func FormatFromFilename(filename string) ExportFormat {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".jsonl", ".json":
+		return ExportFormatJSONLines
+	case ".ddbjson":
+		return ExportFormatDynamoJSON
+	}
+	return ExportFormatCSV
+}
+
+type itemReader interface {
+	// next returns the next item, or io.EOF if there are no more items
+	next() (models.Item, error)
+}
+
+func newItemReader(r io.Reader, format ExportFormat) itemReader {
+	switch format {
+	case ExportFormatJSONLines:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		return &jsonItemReader{dec: dec, fromJSON: attrcodec.MapFromPlainJSON}
+	case ExportFormatDynamoJSON:
+		return &jsonItemReader{dec: json.NewDecoder(r), fromJSON: attrcodec.MapFromDynamoJSON}
+	}
+	return &csvItemReader{cr: csv.NewReader(r)}
+}
+
+// csvItemReader reads items from a CSV file.  The first row is taken to be the attribute names.  All values
+// are read as strings, with empty cells omitted from the item.
+type csvItemReader struct {
+	cr     *csv.Reader
+	header []string
+}
+
+func (ir *csvItemReader) next() (models.Item, error) {
+	if ir.header == nil {
+		header, err := ir.cr.Read()
+		if err != nil {
+			return nil, err
+		}
+		ir.header = header
+	}
+
+	row, err := ir.cr.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	item := models.Item{}
+	for i, v := range row {
+		if v == "" || i >= len(ir.header) {
+			continue
+		}
+		item[ir.header[i]] = &types.AttributeValueMemberS{Value: v}
+	}
+	return item, nil
+}
+
+// jsonItemReader reads items from a stream of JSON documents, one document per item.
+type jsonItemReader struct {
+	dec      *json.Decoder
+	fromJSON func(m map[string]any) (map[string]types.AttributeValue, error)
+}
+
+func (ir *jsonItemReader) next() (models.Item, error) {
+	var doc map[string]any
+	if err := ir.dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	item, err := ir.fromJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// checkKeyTypes verifies that the key attributes of an imported item are of the type expected by the table.  Since
+// CSV files carry no type information, string key values from a CSV file are converted to numbers if the table
+// expects a numeric key.
+func checkKeyTypes(item models.Item, tableInfo *models.TableInfo, format ExportFormat) error {
+	for _, key := range []string{tableInfo.Keys.PartitionKey, tableInfo.Keys.SortKey} {
+		av, hasKey := item[key]
+		if key == "" || !hasKey {
+			continue
+		}
+
+		expectedType, hasType := tableInfo.AttributeTypes[key]
+		if !hasType {
+			continue
+		}
+
+		if s, isS := av.(*types.AttributeValueMemberS); isS && format == ExportFormatCSV && expectedType == "N" {
+			if _, err := strconv.ParseFloat(s.Value, 64); err != nil {
+				return errors.Errorf("key attribute '%v' has value '%v' but the table expects a number", key, s.Value)
+			}
+			av = &types.AttributeValueMemberN{Value: s.Value}
+			item[key] = av
+		}
+
+		if actualType := keyAttributeType(av); actualType != expectedType {
+			return errors.Errorf("key attribute '%v' is of type %v but the table expects %v", key, actualType, expectedType)
+		}
+	}
+	return nil
+}
+
+func keyAttributeType(av types.AttributeValue) string {
+	switch av.(type) {
+	case *types.AttributeValueMemberS:
+		return "S"
+	case *types.AttributeValueMemberN:
+		return "N"
+	case *types.AttributeValueMemberB:
+		return "B"
+	}
+	return "non-scalar"
+}
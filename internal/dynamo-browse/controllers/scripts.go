@@ -4,12 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lmika/dynamo-browse/internal/common/ui/commandctrl"
 	"github.com/lmika/dynamo-browse/internal/common/ui/events"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/aggregate"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/columns"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/itemrender"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/queryexpr"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/relitems"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/scriptmanager"
@@ -18,27 +27,50 @@ import (
 )
 
 type ScriptController struct {
-	scriptManager       *scriptmanager.Service
-	tableReadController *TableReadController
-	jobController       *JobsController
-	settingsController  *SettingsController
-	eventBus            *bus.Bus
-	sendMsg             func(msg tea.Msg)
+	scriptManager        *scriptmanager.Service
+	tableReadController  *TableReadController
+	tableWriteController *TableWriteController
+	columnsController    *ColumnsController
+	jobController        *JobsController
+	settingsController   *SettingsController
+	eventBus             *bus.Bus
+	sendMsg              func(msg tea.Msg)
+
+	// runningScripts tracks the scripts started by commands which have not yet finished
+	runningScripts sync.WaitGroup
+
+	// lastTableName is the name of the table of the last result set, used to detect when a new table is selected
+	lastTableName string
+
+	// selectionMutex guards pendingSelection and notifyingSelection.  Only the latest item selected while the
+	// item selected hooks are running is notified, so that moving the cursor does not queue up the hooks.
+	selectionMutex     sync.Mutex
+	pendingSelection   *itemSelection
+	notifyingSelection bool
+}
+
+type itemSelection struct {
+	resultSet *models.ResultSet
+	itemIndex int
 }
 
 func NewScriptController(
 	scriptManager *scriptmanager.Service,
 	tableReadController *TableReadController,
+	tableWriteController *TableWriteController,
+	columnsController *ColumnsController,
 	jobController *JobsController,
 	settingsController *SettingsController,
 	eventBus *bus.Bus,
 ) *ScriptController {
 	sc := &ScriptController{
-		scriptManager:       scriptManager,
-		tableReadController: tableReadController,
-		jobController:       jobController,
-		settingsController:  settingsController,
-		eventBus:            eventBus,
+		scriptManager:        scriptManager,
+		tableReadController:  tableReadController,
+		tableWriteController: tableWriteController,
+		columnsController:    columnsController,
+		jobController:        jobController,
+		settingsController:   settingsController,
+		eventBus:             eventBus,
 	}
 
 	sessionImpl := &sessionImpl{sc: sc, lastSelectedItemIndex: -1}
@@ -49,6 +81,16 @@ func NewScriptController(
 
 	sessionImpl.subscribeToEvents(eventBus)
 
+	// Setup event hooks
+	tableWriteController.beforePut = scriptManager.BeforePut
+	eventBus.On(newResultSetEvent, sc.onNewResultSet)
+	eventBus.On("ui.new-item-selected", sc.onNewItemSelected)
+
+	// Setup script columns and renderers
+	columnsController.scriptColumns = func(tableInfo *models.TableInfo) []columns.Column {
+		return scriptManager.Columns(tableInfo, sc.sendScriptValuesUpdated)
+	}
+
 	// Setup event handling when settings have changed
 	eventBus.On(BusEventSettingsUpdated, func(name, value string) {
 		if !strings.HasPrefix(name, "script.") {
@@ -78,21 +120,99 @@ func (sc *ScriptController) LoadScript(filename string) tea.Msg {
 	if err != nil {
 		return events.Error(err)
 	}
+	sc.tableReadController.itemRendererService.SetAttributeRenderer(sc.scriptManager.AttributeRenderer(sc.sendScriptValuesUpdated))
 
 	return events.StatusMsg(fmt.Sprintf("Script '%v' loaded", plugin.Name()))
 }
 
 func (sc *ScriptController) RunScript(filename string) tea.Msg {
 	ctx := context.Background()
-	if err := sc.scriptManager.StartAdHocScript(ctx, filename, sc.waitAndPrintScriptError()); err != nil {
+	errChan := sc.waitAndPrintScriptError()
+	if err := sc.scriptManager.StartAdHocScript(ctx, filename, errChan); err != nil {
+		close(errChan)
 		return events.Error(err)
 	}
 	return nil
 }
 
+// RunScriptInBackground runs the script as a background job, which does not block other scripts from running.
+func (sc *ScriptController) RunScriptInBackground(filename string) tea.Msg {
+	ctx := context.Background()
+	errChan := sc.waitAndPrintScriptError()
+	if err := sc.scriptManager.StartBackgroundScript(ctx, filename, errChan); err != nil {
+		close(errChan)
+		return events.Error(err)
+	}
+	return events.StatusMsg(fmt.Sprintf("Script '%v' running in background", filepath.Base(filename)))
+}
+
+// ListBackgroundJobs shows the background jobs which are running, or waiting to run.
+func (sc *ScriptController) ListBackgroundJobs() tea.Msg {
+	jobs := sc.scriptManager.BackgroundJobs()
+	if len(jobs) == 0 {
+		return events.StatusMsg("no background jobs")
+	}
+
+	var sb strings.Builder
+
+	tw := tabwriter.NewWriter(&sb, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, "id\tname\tstate\truns\tnext run")
+	for _, job := range jobs {
+		var nextRun string
+		if job.State == scriptmanager.BackgroundJobWaiting {
+			nextRun = job.NextRun.Format(time.TimeOnly)
+		}
+		fmt.Fprintf(tw, "%d\t%v\t%v\t%d\t%v\n", job.ID, job.Name, job.State, job.Runs, nextRun)
+	}
+	tw.Flush()
+
+	return ShowTextOverlay{Title: "Background Jobs", Text: sb.String()}
+}
+
+// CancelBackgroundJob cancels the background job with the given ID.
+func (sc *ScriptController) CancelBackgroundJob(idStr string) tea.Msg {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return events.Error(errors.Errorf("invalid job ID: %v", idStr))
+	}
+
+	if err := sc.scriptManager.CancelBackgroundJob(id); err != nil {
+		return events.Error(err)
+	}
+	return events.StatusMsg(fmt.Sprintf("Background job %d cancelled", id))
+}
+
+// RunScriptAndWait runs the script and blocks until it has finished, returning any error raised by the script.
+// Messages from the script, such as prompts, will still be sent to the message sender.
+func (sc *ScriptController) RunScriptAndWait(filename string) error {
+	errChan := make(chan error)
+	if err := sc.scriptManager.StartAdHocScript(context.Background(), filename, errChan); err != nil {
+		return err
+	}
+	return <-errChan
+}
+
+// ScriptsDone returns a channel which is closed once all the scripts started by commands have finished.
+func (sc *ScriptController) ScriptsDone() <-chan struct{} {
+	doneChan := make(chan struct{})
+	go func() {
+		sc.runningScripts.Wait()
+		close(doneChan)
+	}()
+	return doneChan
+}
+
+func (sc *ScriptController) sendScriptValuesUpdated() {
+	if sc.sendMsg != nil {
+		sc.sendMsg(ScriptValuesUpdated{})
+	}
+}
+
 func (sc *ScriptController) waitAndPrintScriptError() chan error {
 	errChan := make(chan error)
+	sc.runningScripts.Add(1)
 	go func() {
+		defer sc.runningScripts.Done()
 		if err := <-errChan; err != nil {
 			sc.sendMsg(events.Error(err))
 		}
@@ -111,6 +231,7 @@ func (sc *ScriptController) LookupCommand(name string) commandctrl.Command {
 		ctx := context.Background()
 
 		if err := cmd.Invoke(ctx, args, errChan); err != nil {
+			close(errChan)
 			return events.Error(err)
 		}
 		return nil
@@ -141,6 +262,71 @@ func (u uiImpl) Prompt(ctx context.Context, msg string) chan string {
 	return resultChan
 }
 
+func (u uiImpl) Confirm(ctx context.Context, msg string) chan bool {
+	resultChan := make(chan bool)
+	u.sc.sendMsg(events.PromptForInputMsg{
+		Prompt: msg,
+		OnDone: func(value string) tea.Msg {
+			resultChan <- value == "y"
+			return nil
+		},
+		OnCancel: func() tea.Msg {
+			close(resultChan)
+			return nil
+		},
+		IsConfirmation: true,
+	})
+	return resultChan
+}
+
+func (u uiImpl) Select(ctx context.Context, title string, items []string) chan int {
+	resultChan := make(chan int)
+
+	relItems := make([]relitems.RelatedItem, len(items))
+	for i, item := range items {
+		relItems[i] = relitems.RelatedItem{
+			Name: item,
+			OnSelect: func() error {
+				resultChan <- i
+				return nil
+			},
+		}
+	}
+
+	u.sc.sendMsg(ShowRelatedItemsOverlay{
+		Title: title,
+		Items: relItems,
+		OnSelected: func(item relitems.RelatedItem) tea.Msg {
+			if err := item.OnSelect(); err != nil {
+				return events.Error(err)
+			}
+			return nil
+		},
+		OnCancel: func() tea.Msg {
+			close(resultChan)
+			return nil
+		},
+	})
+	return resultChan
+}
+
+func (u uiImpl) ShowTable(ctx context.Context, columns []string, rows [][]string) {
+	var sb strings.Builder
+
+	tw := tabwriter.NewWriter(&sb, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+
+	u.sc.sendMsg(ShowTextOverlay{Text: sb.String()})
+}
+
+func (u uiImpl) ShowText(ctx context.Context, title string, body string) {
+	u.sc.sendMsg(ShowTextOverlay{Title: title, Text: body})
+}
+
 type sessionImpl struct {
 	sc                    *ScriptController
 	lastSelectedItemIndex int
@@ -187,39 +373,282 @@ func (s *sessionImpl) Query(ctx context.Context, query string, opts scriptmanage
 }
 
 func (s *ScriptController) doQuery(ctx context.Context, expr *queryexpr.QueryExpr, opts scriptmanager.QueryOptions) (*models.ResultSet, error) {
-	// Get the table info
-	var (
-		tableInfo *models.TableInfo
-		err       error
-	)
+	tableInfo, err := s.tableInfoForQuery(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
 
-	tableName := opts.TableName
-	currentResultSet := s.tableReadController.state.ResultSet()
+	newResultSet, err := s.tableReadController.tableService.ScanOrQuery(ctx, tableInfo, expr, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newResultSet, nil
+}
 
-	if tableName != "" {
-		// Table specified.  If it's the same as the current table, then use the existing table info
-		if currentResultSet != nil && currentResultSet.TableInfo.Name == tableName {
-			tableInfo = currentResultSet.TableInfo
-		}
+func (s *sessionImpl) Aggregate(ctx context.Context, agg aggregate.Aggregation, query string, opts scriptmanager.QueryOptions) (*models.ResultSet, error) {
+	tableInfo, err := s.sc.tableInfoForQuery(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
 
-		// Otherwise, describe the table
-		tableInfo, err = s.tableReadController.tableService.Describe(ctx, tableName)
+	// Aggregate over the whole table if no query is given
+	var expr models.Queryable
+	if query != "" {
+		q, err := queryexpr.Parse(query)
 		if err != nil {
-			return nil, errors.Wrapf(err, "cannot describe table '%v'", tableName)
+			return nil, err
+		}
+
+		if opts.NamePlaceholders != nil {
+			q = q.WithNameParams(opts.NamePlaceholders)
+		}
+		if opts.ValuePlaceholders != nil {
+			q = q.WithValueParams(opts.ValuePlaceholders)
+		}
+		if opts.IndexName != "" {
+			q = q.WithIndex(opts.IndexName)
+		}
+		expr = q
+	}
+
+	return s.sc.tableReadController.tableService.Aggregate(ctx, tableInfo, expr, agg)
+}
+
+func (s *sessionImpl) BatchGet(ctx context.Context, keys []models.Item, opts scriptmanager.QueryOptions) (*models.ResultSet, error) {
+	tableInfo, err := s.sc.tableInfoForQuery(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	keyValues := make([]map[string]types.AttributeValue, len(keys))
+	for i, key := range keys {
+		if pk, sk := key.PKSK(tableInfo); pk == nil || (tableInfo.Keys.SortKey != "" && sk == nil) {
+			return nil, errors.Errorf("key %d is missing key attributes", i)
+		}
+		keyValues[i] = key.KeyValue(tableInfo)
+	}
+
+	return s.sc.tableReadController.tableService.BatchGet(ctx, tableInfo, keyValues)
+}
+
+func (s *sessionImpl) PutItems(ctx context.Context, resultSet *models.ResultSet, indices []int) (int, error) {
+	if err := s.sc.tableWriteController.assertReadWrite(); err != nil {
+		return 0, err
+	}
+
+	var itemsToPut []models.ItemIndex
+	if indices == nil {
+		for i, itm := range resultSet.Items() {
+			if resultSet.IsDirty(i) {
+				itemsToPut = append(itemsToPut, models.ItemIndex{Item: itm, Index: i})
+			}
 		}
 	} else {
+		for _, i := range indices {
+			itemsToPut = append(itemsToPut, models.ItemIndex{Item: resultSet.Items()[i], Index: i})
+		}
+	}
+	if len(itemsToPut) == 0 {
+		return 0, nil
+	} else if resultSet.Projected {
+		return 0, models.ErrProjectedItems
+	}
+
+	// The before put hooks are run before the changes are shown, so that the changes made by the hooks are confirmed
+	if err := s.sc.tableWriteController.runBeforePut(ctx, resultSet, itemsToPut); err != nil {
+		return 0, err
+	}
+
+	itemDiffs := make([]ItemDiff, len(itemsToPut))
+	for i, item := range itemsToPut {
+		description := describeItemKey(resultSet.TableInfo, item.Item)
+		if resultSet.IsNew(item.Index) {
+			description += " (new)"
+		}
+		itemDiffs[i] = ItemDiff{
+			Description: description,
+			Diff:        itemrender.Diff(resultSet.OriginalItem(item.Index), item.Item),
+		}
+	}
+	if err := s.confirmChanges(ctx, applyToN("put ", len(itemsToPut), "item", "items", "? "), itemDiffs); err != nil {
+		return 0, err
+	}
+
+	err := s.sc.tableWriteController.tableService.PutSelectedItems(ctx, resultSet, itemsToPut)
+	if resultSet == s.ResultSet(ctx) {
+		// Refresh the dirty state of the displayed items
+		s.sc.sendMsg(ResultSetUpdated{})
+	}
+
+	var conflictErr models.PutConflictError
+	if errors.As(err, &conflictErr) {
+		return len(itemsToPut) - conflictErr.Count, err
+	} else if err != nil {
+		return 0, err
+	}
+	return len(itemsToPut), nil
+}
+
+func (s *sessionImpl) DeleteItems(ctx context.Context, keys []models.Item, opts scriptmanager.QueryOptions) error {
+	if err := s.sc.tableWriteController.assertReadWrite(); err != nil {
+		return err
+	}
+
+	tableInfo, err := s.sc.tableInfoForQuery(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	itemDiffs := make([]ItemDiff, len(keys))
+	for i, key := range keys {
+		if pk, sk := key.PKSK(tableInfo); pk == nil || (tableInfo.Keys.SortKey != "" && sk == nil) {
+			return errors.Errorf("key %d is missing key attributes", i)
+		}
+		itemDiffs[i] = ItemDiff{
+			Description: describeItemKey(tableInfo, key),
+			Diff:        itemrender.Diff(key, nil),
+		}
+	}
+	if err := s.confirmChanges(ctx, applyToN("delete ", len(keys), "item", "items", "? "), itemDiffs); err != nil {
+		return err
+	}
+
+	if err := s.sc.tableWriteController.tableService.Delete(ctx, tableInfo, keys); err != nil {
+		return err
+	}
+
+	// Rescan the displayed items if they were read from the table, so that the deleted items are removed
+	if rs := s.ResultSet(ctx); rs != nil && rs.TableInfo.Name == tableInfo.Name {
+		s.sc.sendMsg(s.sc.tableReadController.doScan(rs, rs.Query, false, resultSetUpdateTouch))
+	}
+	return nil
+}
+
+// confirmChanges shows the changes a script will make to the table and waits for the user to confirm them.
+func (s *sessionImpl) confirmChanges(ctx context.Context, title string, itemDiffs []ItemDiff) error {
+	resultChan := make(chan bool, 1)
+	s.sc.sendMsg(ShowDiffOverlay{
+		Title: title,
+		Items: itemDiffs,
+		OnConfirm: func() tea.Msg {
+			resultChan <- true
+			return nil
+		},
+		OnCancel: func() tea.Msg {
+			resultChan <- false
+			return nil
+		},
+	})
+
+	select {
+	case confirmed := <-resultChan:
+		if !confirmed {
+			return errors.New("operation aborted")
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *sessionImpl) UpdateItem(ctx context.Context, key models.Item, update scriptmanager.ItemUpdate, opts scriptmanager.QueryOptions) error {
+	tableInfo, err := s.sc.tableInfoForQuery(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if pk, sk := key.PKSK(tableInfo); pk == nil || (tableInfo.Keys.SortKey != "" && sk == nil) {
+		return errors.New("key is missing key attributes")
+	}
+
+	return s.sc.tableWriteController.tableService.UpdateItem(ctx, tableInfo, key.KeyValue(tableInfo), update.Set, update.Remove)
+}
+
+// tableInfoForQuery returns the info of the table named in the query options, or the current table if no table
+// is named.
+func (s *ScriptController) tableInfoForQuery(ctx context.Context, opts scriptmanager.QueryOptions) (*models.TableInfo, error) {
+	tableName := opts.TableName
+	currentResultSet := s.tableReadController.state.ResultSet()
+
+	if tableName == "" {
 		// Table not specified.  Use the existing table, if any
 		if currentResultSet == nil {
 			return nil, errors.New("no table currently selected")
 		}
-		tableInfo = currentResultSet.TableInfo
+		return currentResultSet.TableInfo, nil
 	}
 
-	newResultSet, err := s.tableReadController.tableService.ScanOrQuery(ctx, tableInfo, expr, nil)
+	// Table specified.  If it's the same as the current table, then use the existing table info
+	if currentResultSet != nil && currentResultSet.TableInfo.Name == tableName {
+		return currentResultSet.TableInfo, nil
+	}
+
+	// Otherwise, describe the table
+	tableInfo, err := s.tableReadController.tableService.Describe(ctx, tableName)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "cannot describe table '%v'", tableName)
+	}
+	return tableInfo, nil
+}
+
+func (sc *ScriptController) onNewResultSet(rs *models.ResultSet, op resultSetUpdateOp) {
+	if rs == nil {
+		return
+	}
+
+	tableSelected := rs.TableInfo.Name != sc.lastTableName
+	sc.lastTableName = rs.TableInfo.Name
+
+	go func() {
+		ctx := context.Background()
+		if tableSelected {
+			sc.reportEventHookError(sc.scriptManager.NotifyTableSelected(ctx, rs.TableInfo))
+		}
+
+		// Result sets set by scripts do not run the hooks, so that a hook which sets the result set does not loop
+		if op != resultSetUpdateScript {
+			sc.reportEventHookError(sc.scriptManager.NotifyResultSet(ctx, rs))
+		}
+	}()
+}
+
+func (sc *ScriptController) onNewItemSelected(rs *models.ResultSet, itemIndex int) {
+	if rs == nil || itemIndex < 0 {
+		return
+	}
+
+	sc.selectionMutex.Lock()
+	defer sc.selectionMutex.Unlock()
+
+	sc.pendingSelection = &itemSelection{resultSet: rs, itemIndex: itemIndex}
+	if !sc.notifyingSelection {
+		sc.notifyingSelection = true
+		go sc.notifyItemSelections()
+	}
+}
+
+// notifyItemSelections runs the item selected hooks until there are no more pending selections.
+func (sc *ScriptController) notifyItemSelections() {
+	for {
+		sc.selectionMutex.Lock()
+		selection := sc.pendingSelection
+		sc.pendingSelection = nil
+		if selection == nil {
+			sc.notifyingSelection = false
+		}
+		sc.selectionMutex.Unlock()
+
+		if selection == nil {
+			return
+		}
+		sc.reportEventHookError(sc.scriptManager.NotifyItemSelected(context.Background(), selection.resultSet, selection.itemIndex))
+	}
+}
+
+func (sc *ScriptController) reportEventHookError(err error) {
+	if err != nil {
+		sc.sendMsg(events.Error(err))
 	}
-	return newResultSet, nil
 }
 
 func (sc *ScriptController) CustomKeyCommand(key string) tea.Cmd {
@@ -233,6 +662,7 @@ func (sc *ScriptController) CustomKeyCommand(key string) tea.Cmd {
 		ctx := context.Background()
 
 		if err := cmd.Invoke(ctx, nil, errChan); err != nil {
+			close(errChan)
 			return events.Error(err)
 		}
 		return nil
@@ -263,6 +693,7 @@ func (c *ScriptController) LookupRelatedItems(idx int) (res tea.Msg) {
 	}
 
 	return ShowRelatedItemsOverlay{
+		Title: "Related Items",
 		Items: relItems,
 		OnSelected: func(item relitems.RelatedItem) tea.Msg {
 			if item.OnSelect != nil {
@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/lmika/dynamo-browse/internal/common/ui/events"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/controllers"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -132,6 +133,197 @@ func TestScriptController_RunScript(t *testing.T) {
 			assert.True(t, srv.state.ResultSet().IsDirty(0))
 		})
 	})
+
+	t.Run("ui.select", func(t *testing.T) {
+		t.Run("should return the item selected from the overlay", func(t *testing.T) {
+			srv := newService(t, serviceConfig{
+				scriptFS: testScriptFile(t, "test.tm", `
+					ui.print(ui.select("Pick one", ["alpha", "bravo"]))
+				`),
+			})
+
+			msg := srv.scriptController.RunScript("test.tm")
+			assert.Nil(t, msg)
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+
+			overlay, isOverlay := srv.msgSender.drain()[0].(controllers.ShowRelatedItemsOverlay)
+			assert.True(t, isOverlay)
+			assert.Equal(t, "Pick one", overlay.Title)
+			assert.Len(t, overlay.Items, 2)
+
+			assert.Nil(t, overlay.OnSelected(overlay.Items[1]))
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+			assert.Equal(t, events.StatusMsg("bravo"), srv.msgSender.msgs[0])
+		})
+	})
+
+	t.Run("ui.show_table", func(t *testing.T) {
+		t.Run("should show the table in a text overlay", func(t *testing.T) {
+			srv := newService(t, serviceConfig{
+				scriptFS: testScriptFile(t, "test.tm", `
+					ui.show_table(["name", "count"], [["apples", 3], ["pears", 12]])
+				`),
+			})
+
+			msg := srv.scriptController.RunScript("test.tm")
+			assert.Nil(t, msg)
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+
+			assert.Equal(t, controllers.ShowTextOverlay{
+				Text: "name    count\napples  3\npears   12\n",
+			}, srv.msgSender.msgs[0])
+		})
+	})
+
+	t.Run("session.put_items", func(t *testing.T) {
+		t.Run("should put modified items of the current result set", func(t *testing.T) {
+			srv := newService(t, serviceConfig{
+				tableName: "alpha-table",
+				scriptFS: testScriptFile(t, "test.tm", `
+					rs := session.result_set()
+					rs[1].set_attr("alpha", "Updated value")
+					session.put_items(rs)
+				`),
+			})
+
+			invokeCommand(t, srv.readController.Init())
+			msg := srv.scriptController.RunScript("test.tm")
+			assert.Nil(t, msg)
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+			invokeCommandWithDiffConfirmation(t, srv.msgSender.drain()[0], true)
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+
+			assert.IsType(t, controllers.ResultSetUpdated{}, srv.msgSender.msgs[0])
+			assert.False(t, srv.state.ResultSet().IsDirty(1))
+
+			invokeCommand(t, srv.readController.Rescan())
+			assert.Equal(t, "Updated value", srv.state.ResultSet().Items()[1]["alpha"].(*types.AttributeValueMemberS).Value)
+		})
+
+		t.Run("should not put items if the changes are not confirmed", func(t *testing.T) {
+			srv := newService(t, serviceConfig{
+				tableName: "alpha-table",
+				scriptFS: testScriptFile(t, "test.tm", `
+					rs := session.result_set()
+					rs[1].set_attr("alpha", "Updated value")
+					session.put_items(rs)
+				`),
+			})
+
+			invokeCommand(t, srv.readController.Init())
+			msg := srv.scriptController.RunScript("test.tm")
+			assert.Nil(t, msg)
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+			invokeCommandWithDiffConfirmation(t, srv.msgSender.drain()[0], false)
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+
+			_, isErr := srv.msgSender.msgs[0].(events.ErrorMsg)
+			assert.True(t, isErr)
+			assert.True(t, srv.state.ResultSet().IsDirty(1))
+
+			invokeCommand(t, srv.readController.Rescan())
+			assert.NotEqual(t, "Updated value", srv.state.ResultSet().Items()[1]["alpha"].(*types.AttributeValueMemberS).Value)
+		})
+
+		t.Run("should return error if in read-only mode", func(t *testing.T) {
+			srv := newService(t, serviceConfig{
+				tableName:  "alpha-table",
+				isReadOnly: true,
+				scriptFS: testScriptFile(t, "test.tm", `
+					rs := session.result_set()
+					rs[1].set_attr("alpha", "Updated value")
+					session.put_items(rs)
+				`),
+			})
+
+			invokeCommand(t, srv.readController.Init())
+			msg := srv.scriptController.RunScript("test.tm")
+			assert.Nil(t, msg)
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+
+			_, isErr := srv.msgSender.msgs[0].(events.ErrorMsg)
+			assert.True(t, isErr)
+		})
+	})
+
+	t.Run("session.update_item", func(t *testing.T) {
+		t.Run("should set and remove attributes of an item", func(t *testing.T) {
+			srv := newService(t, serviceConfig{
+				tableName: "alpha-table",
+				scriptFS: testScriptFile(t, "test.tm", `
+					session.update_item({pk: "bbb", sk: "131"}, {set: {gamma: "updated"}, remove: ["beta"]})
+					ui.print("done")
+				`),
+			})
+
+			invokeCommand(t, srv.readController.Init())
+			msg := srv.scriptController.RunScript("test.tm")
+			assert.Nil(t, msg)
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+			assert.Equal(t, events.StatusMsg("done"), srv.msgSender.msgs[0])
+
+			invokeCommand(t, srv.readController.Rescan())
+			item := srv.state.ResultSet().Items()[2]
+			assert.Equal(t, "updated", item["gamma"].(*types.AttributeValueMemberS).Value)
+			assert.Nil(t, item["beta"])
+		})
+	})
+
+	t.Run("session.delete_items", func(t *testing.T) {
+		t.Run("should delete items from the table", func(t *testing.T) {
+			srv := newService(t, serviceConfig{
+				tableName: "alpha-table",
+				scriptFS: testScriptFile(t, "test.tm", `
+					rs := session.result_set()
+					ui.print(session.delete_items([rs[0], rs[1]]))
+				`),
+			})
+
+			invokeCommand(t, srv.readController.Init())
+			msg := srv.scriptController.RunScript("test.tm")
+			assert.Nil(t, msg)
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+			invokeCommandWithDiffConfirmation(t, srv.msgSender.drain()[0], true)
+
+			// The displayed items are rescanned once the items are deleted
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+			assert.Len(t, srv.state.ResultSet().Items(), 1)
+		})
+
+		t.Run("should not delete items if the changes are not confirmed", func(t *testing.T) {
+			srv := newService(t, serviceConfig{
+				tableName: "alpha-table",
+				scriptFS: testScriptFile(t, "test.tm", `
+					rs := session.result_set()
+					session.delete_items([rs[0], rs[1]])
+				`),
+			})
+
+			invokeCommand(t, srv.readController.Init())
+			msg := srv.scriptController.RunScript("test.tm")
+			assert.Nil(t, msg)
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+			invokeCommandWithDiffConfirmation(t, srv.msgSender.drain()[0], false)
+
+			srv.msgSender.waitForAtLeastOneMessages(t, 5*time.Second)
+			_, isErr := srv.msgSender.msgs[0].(events.ErrorMsg)
+			assert.True(t, isErr)
+
+			invokeCommand(t, srv.readController.Rescan())
+			assert.Len(t, srv.state.ResultSet().Items(), 3)
+		})
+	})
 }
 
 func TestScriptController_LookupCommand(t *testing.T) {
@@ -190,3 +382,49 @@ func TestScriptController_LookupCommand(t *testing.T) {
 	})
 
 }
+
+func TestScriptController_EventHooks(t *testing.T) {
+	t.Run("should veto put if before_put hook returns false", func(t *testing.T) {
+		srv := newService(t, serviceConfig{
+			tableName: "alpha-table",
+			scriptFS: testScriptFile(t, "test.tm", `
+				ext.on("before_put", func(items) {
+					return false
+				})
+			`),
+		})
+
+		invokeCommand(t, srv.scriptController.LoadScript("test.tm"))
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(0, models.StringItemType, "alpha"), "a new value")
+
+		invokeCommandExpectingError(t, srv.writeController.PutItems())
+
+		assert.True(t, srv.state.ResultSet().IsDirty(0))
+	})
+
+	t.Run("should put items modified by before_put hook", func(t *testing.T) {
+		srv := newService(t, serviceConfig{
+			tableName: "alpha-table",
+			scriptFS: testScriptFile(t, "test.tm", `
+				ext.on("before_put", func(items) {
+					for _, item := range items {
+						item.set_attr("audited", true)
+					}
+				})
+			`),
+		})
+
+		invokeCommand(t, srv.scriptController.LoadScript("test.tm"))
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(0, models.StringItemType, "alpha"), "a new value")
+		msg := srv.writeController.PutItems()
+
+		// The changes made by the hook are shown before the put is confirmed
+		assert.Equal(t, &types.AttributeValueMemberBOOL{Value: true}, srv.state.ResultSet().Items()[0]["audited"])
+		invokeCommandWithDiffConfirmation(t, msg, true)
+
+		invokeCommand(t, srv.readController.Rescan())
+		assert.Equal(t, &types.AttributeValueMemberBOOL{Value: true}, srv.state.ResultSet().Items()[0]["audited"])
+	})
+}
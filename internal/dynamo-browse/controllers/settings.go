@@ -4,6 +4,7 @@ import (
 	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lmika/dynamo-browse/internal/common/ui/events"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	bus "github.com/lmika/events"
 	"github.com/pkg/errors"
 	"log"
@@ -12,6 +13,8 @@ import (
 
 const (
 	BusEventSettingsUpdated = "settings.updated"
+
+	maxScanSegments = 100
 )
 
 type SettingsController struct {
@@ -75,6 +78,42 @@ func (sc *SettingsController) SetSetting(name string, value string) tea.Msg {
 			Message: events.StatusMsg(fmt.Sprintf("Default query limit now %v", newLimit)),
 			Next:    SettingsUpdated{},
 		}
+	case "scan-segments":
+		if value == "" {
+			return events.StatusMsg(fmt.Sprintf("scan-segments = %v", sc.settings.ScanSegments()))
+		}
+
+		newSegments, err := strconv.Atoi(value)
+		if err != nil {
+			return events.Error(errors.Wrapf(err, "bad value: %v", value))
+		} else if newSegments < 1 || newSegments > maxScanSegments {
+			return events.Error(errors.Errorf("scan-segments must be between 1 and %v", maxScanSegments))
+		}
+
+		if err := sc.settings.SetScanSegments(newSegments); err != nil {
+			return events.Error(err)
+		}
+		return events.WrappedStatusMsg{
+			Message: events.StatusMsg(fmt.Sprintf("Scans now use %v segments", newSegments)),
+			Next:    SettingsUpdated{},
+		}
+	case "put-mode":
+		if value == "" {
+			return events.StatusMsg(fmt.Sprintf("put-mode = %v", sc.settings.PutMode()))
+		}
+
+		newPutMode, err := models.ParsePutMode(value)
+		if err != nil {
+			return events.Error(err)
+		}
+
+		if err := sc.settings.SetPutMode(newPutMode); err != nil {
+			return events.Error(err)
+		}
+		return events.WrappedStatusMsg{
+			Message: events.StatusMsg(fmt.Sprintf("Put mode now %v", newPutMode)),
+			Next:    SettingsUpdated{},
+		}
 	case "script.lookup-path":
 		if value == "" {
 			return events.StatusMsg(fmt.Sprintf("script.lookup-path = '%v'", sc.settings.ScriptLookupPaths()))
@@ -3,6 +3,7 @@ package controllers_test
 import (
 	"github.com/lmika/dynamo-browse/internal/common/ui/events"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/controllers"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -45,4 +46,30 @@ func TestSettingsController_SetSetting(t *testing.T) {
 		msg := invokeCommand(t, srv.settingsController.SetSetting("default-limit", ""))
 		assert.Equal(t, "default-limit = 20", string(msg.(events.StatusMsg)))
 	})
+	t.Run("set scan segments", func(t *testing.T) {
+		srv := newService(t, serviceConfig{})
+
+		assert.Equal(t, 1, srv.settingProvider.ScanSegments())
+		invokeCommand(t, srv.settingsController.SetSetting("scan-segments", "8"))
+
+		assert.Equal(t, 8, srv.settingProvider.ScanSegments())
+
+		msg := invokeCommand(t, srv.settingsController.SetSetting("scan-segments", ""))
+		assert.Equal(t, "scan-segments = 8", string(msg.(events.StatusMsg)))
+
+		invokeCommandExpectingError(t, srv.settingsController.SetSetting("scan-segments", "0"))
+	})
+	t.Run("set put mode", func(t *testing.T) {
+		srv := newService(t, serviceConfig{})
+
+		assert.Equal(t, models.PutModeBatch, srv.settingProvider.PutMode())
+		invokeCommand(t, srv.settingsController.SetSetting("put-mode", "transaction"))
+
+		assert.Equal(t, models.PutModeTransaction, srv.settingProvider.PutMode())
+
+		msg := invokeCommand(t, srv.settingsController.SetSetting("put-mode", ""))
+		assert.Equal(t, "put-mode = transaction", string(msg.(events.StatusMsg)))
+
+		invokeCommandExpectingError(t, srv.settingsController.SetSetting("put-mode", "bogus"))
+	})
 }
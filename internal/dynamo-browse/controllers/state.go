@@ -6,9 +6,10 @@ import (
 )
 
 type State struct {
-	mutex     *sync.Mutex
-	resultSet *models.ResultSet
-	filter    string
+	mutex      *sync.Mutex
+	resultSet  *models.ResultSet
+	filter     string
+	connection models.Connection
 }
 
 func NewState() *State {
@@ -29,6 +30,18 @@ func (s *State) Filter() string {
 	return s.filter
 }
 
+func (s *State) Connection() models.Connection {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.connection
+}
+
+func (s *State) setConnection(connection models.Connection) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.connection = connection
+}
+
 func (s *State) withResultSet(rs func(*models.ResultSet)) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -64,5 +77,5 @@ func (s *State) buildNewResultSetMessage(statusMessage string) NewResultSet {
 		}
 	}
 
-	return NewResultSet{s.resultSet, s.filter, filteredCount, statusMessage}
+	return NewResultSet{s.resultSet, s.filter, filteredCount, statusMessage, s.connection}
 }
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lmika/dynamo-browse/internal/common/ui/events"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+)
+
+// DescribeTable fetches the full description of the current table and shows it in an overlay.
+func (c *TableReadController) DescribeTable() tea.Msg {
+	resultSet := c.state.ResultSet()
+	if resultSet == nil {
+		return events.StatusMsg("Result-set is nil")
+	}
+
+	return NewJob(c.jobController, "Describing table…", func(ctx context.Context) (*models.TableInfo, error) {
+		return c.tableService.DescribeInFull(ctx, resultSet.TableInfo.Name)
+	}).OnDone(func(tableInfo *models.TableInfo) tea.Msg {
+		var sb strings.Builder
+		writeTableDescription(&sb, tableInfo)
+
+		return ShowTextOverlay{
+			Title: "Table: " + tableInfo.Name,
+			Text:  sb.String(),
+		}
+	}).Submit()
+}
+
+func writeTableDescription(w io.Writer, ti *models.TableInfo) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Name\t%v\n", ti.Name)
+	fmt.Fprintf(tw, "ARN\t%v\n", ti.ARN)
+	fmt.Fprintf(tw, "Status\t%v\n", ti.Status)
+	if !ti.Created.IsZero() {
+		fmt.Fprintf(tw, "Created\t%v\n", ti.Created.Local().Format(time.RFC1123))
+	}
+	writeKeyDescription(tw, "", ti.Keys)
+
+	if ti.BillingMode == "PAY_PER_REQUEST" {
+		fmt.Fprintf(tw, "Billing mode\t%v\n", ti.BillingMode)
+	} else {
+		fmt.Fprintf(tw, "Billing mode\t%v (%d RCU, %d WCU)\n", ti.BillingMode, ti.ReadCapacity, ti.WriteCapacity)
+	}
+	fmt.Fprintf(tw, "Item count\t%d (approx.)\n", ti.ItemCount)
+	fmt.Fprintf(tw, "Size\t%v (approx.)\n", formatByteSize(ti.SizeBytes))
+
+	if ti.TTL.AttributeName != "" {
+		fmt.Fprintf(tw, "TTL\t%v (%v)\n", describeStatus(ti.TTL.Status), ti.TTL.AttributeName)
+	} else {
+		fmt.Fprintf(tw, "TTL\t%v\n", describeStatus(ti.TTL.Status))
+	}
+	if ti.Stream.Enabled {
+		fmt.Fprintf(tw, "Stream\tENABLED (%v)\n", ti.Stream.ViewType)
+	} else {
+		fmt.Fprintf(tw, "Stream\tDISABLED\n")
+	}
+	fmt.Fprintf(tw, "Point-in-time recovery\t%v\n", describeStatus(ti.PITRStatus))
+
+	for _, gsi := range ti.GSIs {
+		fmt.Fprintf(tw, "\t\n")
+		fmt.Fprintf(tw, "Global index\t%v\n", gsi.Name)
+		fmt.Fprintf(tw, "  Status\t%v\n", gsi.Status)
+		writeKeyDescription(tw, "  ", gsi.Keys)
+		writeProjectionDescription(tw, gsi.Projection)
+		fmt.Fprintf(tw, "  Item count\t%d (approx.)\n", gsi.ItemCount)
+		fmt.Fprintf(tw, "  Size\t%v (approx.)\n", formatByteSize(gsi.SizeBytes))
+	}
+
+	for _, lsi := range ti.LSIs {
+		fmt.Fprintf(tw, "\t\n")
+		fmt.Fprintf(tw, "Local index\t%v\n", lsi.Name)
+		writeKeyDescription(tw, "  ", lsi.Keys)
+		writeProjectionDescription(tw, lsi.Projection)
+		fmt.Fprintf(tw, "  Item count\t%d (approx.)\n", lsi.ItemCount)
+		fmt.Fprintf(tw, "  Size\t%v (approx.)\n", formatByteSize(lsi.SizeBytes))
+	}
+
+	tw.Flush()
+}
+
+func writeKeyDescription(w io.Writer, indent string, keys models.KeyAttribute) {
+	fmt.Fprintf(w, "%vPartition key\t%v\n", indent, keys.PartitionKey)
+	if keys.SortKey != "" {
+		fmt.Fprintf(w, "%vSort key\t%v\n", indent, keys.SortKey)
+	}
+}
+
+func writeProjectionDescription(w io.Writer, projection models.TableProjection) {
+	if len(projection.NonKeyAttributes) > 0 {
+		fmt.Fprintf(w, "  Projection\t%v (%v)\n", projection.Type, strings.Join(projection.NonKeyAttributes, ", "))
+	} else {
+		fmt.Fprintf(w, "  Projection\t%v\n", projection.Type)
+	}
+}
+
+func describeStatus(status string) string {
+	if status == "" {
+		return "UNKNOWN"
+	}
+	return status
+}
+
+func formatByteSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrcodec"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/partiql"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/queryexpr"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/serialisable"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services"
@@ -36,6 +37,7 @@ const (
 	resultSetUpdateNextPage
 	resultSetUpdateScript
 	resultSetUpdateResort
+	resultSetUpdateImport
 )
 
 type MarkOp int
@@ -97,6 +99,8 @@ func NewTableReadController(
 
 // Init does an initial scan of the table.  If no table is specified, it prompts for a table, then does a scan.
 func (c *TableReadController) Init() tea.Msg {
+	c.state.setConnection(c.tableService.Connection())
+
 	// Restore previous view
 	if c.loadFromLastView {
 		if vs, err := c.workspaceService.ViewRestore(); err == nil && vs != nil {
@@ -135,19 +139,77 @@ func (c *TableReadController) ListTables(quitIfNoTable bool) tea.Msg {
 	}).Submit()
 }
 
-func (c *TableReadController) ScanTable(name string) tea.Msg {
+// Connection returns the details of the current connection.
+func (c *TableReadController) Connection() models.Connection {
+	return c.state.Connection()
+}
+
+// Connect prompts for a table from a new connection.  The new connection is only switched to once a table has been
+// selected and scanned, so that the result set of the current connection is never written to the new one.
+func (c *TableReadController) Connect(conn models.Connection) tea.Msg {
+	return c.doIfNoneDirty(func() tea.Msg {
+		return NewJob(c.jobController, "Connecting to "+conn.String()+"…", func(ctx context.Context) ([]string, error) {
+			return c.tableService.ListTablesOfConnection(ctx, conn)
+		}).OnDone(func(tables []string) tea.Msg {
+			return PromptForTableMsg{
+				Tables: tables,
+				OnSelected: func(tableName string) tea.Msg {
+					if tableName == "" {
+						return events.StatusMsg("No table selected, still connected to " + c.state.Connection().String())
+					}
+					return c.connectAndScanTable(conn, tableName)
+				},
+			}
+		}).Submit()
+	})
+}
+
+// connectAndScanTable switches to the connection and scans the table.  If the table cannot be scanned, the previous
+// connection is restored.
+func (c *TableReadController) connectAndScanTable(conn models.Connection, tableName string) tea.Msg {
+	prevConn := c.state.Connection()
+
 	return NewJob(c.jobController, "Scanning…", func(ctx context.Context) (*models.ResultSet, error) {
-		tableInfo, err := c.tableService.Describe(ctx, name)
+		if err := c.tableService.Connect(ctx, conn); err != nil {
+			return nil, err
+		}
+
+		tableInfo, err := c.tableService.Describe(ctx, tableName)
 		if err != nil {
-			return nil, errors.Wrapf(err, "cannot describe %v", c.tableName)
+			c.restoreConnection(prevConn)
+			return nil, errors.Wrapf(err, "cannot describe %v", tableName)
 		}
 
 		resultSet, err := c.tableService.Scan(ctx, tableInfo)
+		if resultSet == nil {
+			c.restoreConnection(prevConn)
+			return nil, err
+		}
+
+		return c.filterResultSet(resultSet, c.state.Filter(), err)
+	}).OnEither(func(resultSet *models.ResultSet, err error) tea.Msg {
 		if resultSet != nil {
-			resultSet = c.tableService.Filter(resultSet, c.state.Filter())
+			c.state.setConnection(c.tableService.Connection())
+		}
+		return c.handleResultSetFromJobResult(c.state.Filter(), true, false, resultSetUpdateInit)(resultSet, err)
+	}).Submit()
+}
+
+func (c *TableReadController) restoreConnection(conn models.Connection) {
+	if err := c.tableService.Connect(context.Background(), conn); err != nil {
+		log.Printf("cannot restore connection to %v: %v", conn, err)
+	}
+}
+
+func (c *TableReadController) ScanTable(name string) tea.Msg {
+	return NewJob(c.jobController, "Scanning…", func(ctx context.Context) (*models.ResultSet, error) {
+		tableInfo, err := c.tableService.Describe(ctx, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot describe %v", c.tableName)
 		}
 
-		return resultSet, err
+		resultSet, err := c.tableService.Scan(ctx, tableInfo)
+		return c.filterResultSet(resultSet, c.state.Filter(), err)
 	}).OnEither(c.handleResultSetFromJobResult(c.state.Filter(), true, false, resultSetUpdateInit)).Submit()
 }
 
@@ -168,7 +230,15 @@ func (c *TableReadController) PromptForQuery() tea.Msg {
 				return events.StatusMsg("Result-set is nil")
 			}
 
-			var q *queryexpr.QueryExpr
+			if partiql.IsStatement(value) {
+				stmt, err := partiql.Parse(value)
+				if err != nil {
+					return events.Error(err)
+				}
+				return c.runStatement(resultSet.TableInfo, stmt)
+			}
+
+			var q models.Queryable
 			if value != "" {
 				var err error
 				q, err = queryexpr.Parse(value)
@@ -182,9 +252,25 @@ func (c *TableReadController) PromptForQuery() tea.Msg {
 	}
 }
 
+// runStatement runs a PartiQL statement.  If the statement selects from a table other than the current one, the
+// table is described first.  Statements which do not name the table they select from are not run.
+func (c *TableReadController) runStatement(tableInfo *models.TableInfo, stmt *partiql.Statement) tea.Msg {
+	tableName := stmt.TableName()
+	if tableName == "" {
+		return events.Error(errors.Errorf("cannot determine the table to select from: '%v'", stmt.Statement()))
+	} else if tableName != tableInfo.Name {
+		return NewJob(c.jobController, "Fetching table info…", func(ctx context.Context) (*models.TableInfo, error) {
+			return c.tableService.Describe(ctx, tableName)
+		}).OnDone(func(stmtTableInfo *models.TableInfo) tea.Msg {
+			return c.runQuery(stmtTableInfo, stmt, "", true, nil)
+		}).Submit()
+	}
+	return c.runQuery(tableInfo, stmt, "", true, nil)
+}
+
 func (c *TableReadController) runQuery(
 	tableInfo *models.TableInfo,
-	query *queryexpr.QueryExpr,
+	query models.Queryable,
 	newFilter string,
 	pushSnapshot bool,
 	exclusiveStartKey map[string]types.AttributeValue,
@@ -192,47 +278,37 @@ func (c *TableReadController) runQuery(
 	if query == nil {
 		return NewJob(c.jobController, "Scanning…", func(ctx context.Context) (*models.ResultSet, error) {
 			newResultSet, err := c.tableService.ScanOrQuery(context.Background(), tableInfo, nil, exclusiveStartKey)
-
-			if newResultSet != nil && newFilter != "" {
-				newResultSet = c.tableService.Filter(newResultSet, newFilter)
-			}
-
-			return newResultSet, err
+			return c.filterResultSet(newResultSet, newFilter, err)
 		}).OnEither(c.handleResultSetFromJobResult(newFilter, pushSnapshot, false, resultSetUpdateQuery)).Submit()
 	}
 
 	return c.doIfNoneDirty(func() tea.Msg {
 		return NewJob(c.jobController, "Running query…", func(ctx context.Context) (*models.ResultSet, error) {
 			newResultSet, err := c.tableService.ScanOrQuery(context.Background(), tableInfo, query, exclusiveStartKey)
-
-			if newFilter != "" && newResultSet != nil {
-				newResultSet = c.tableService.Filter(newResultSet, newFilter)
-			}
-			return newResultSet, err
+			return c.filterResultSet(newResultSet, newFilter, err)
 		}).OnEither(c.handleResultSetFromJobResult(newFilter, pushSnapshot, false, resultSetUpdateQuery)).Submit()
 	})
 }
 
 func (c *TableReadController) doIfNoneDirty(cmd tea.Cmd) tea.Msg {
 	var anyDirty = false
-	for i := 0; i < len(c.state.ResultSet().Items()); i++ {
-		anyDirty = anyDirty || c.state.ResultSet().IsDirty(i)
+	if resultSet := c.state.ResultSet(); resultSet != nil {
+		for i := 0; i < len(resultSet.Items()); i++ {
+			anyDirty = anyDirty || resultSet.IsDirty(i)
+		}
 	}
 
 	if !anyDirty {
 		return cmd()
 	}
 
-	return events.PromptForInputMsg{
-		Prompt: "reset modified items? ",
-		OnDone: func(value string) tea.Msg {
-			if value != "y" {
-				return events.StatusMsg("operation aborted")
-			}
+	return events.Confirm("reset modified items? ", func(yes bool) tea.Msg {
+		if !yes {
+			return events.StatusMsg("operation aborted")
+		}
 
-			return cmd()
-		},
-	}
+		return cmd()
+	})
 }
 
 func (c *TableReadController) Rescan() tea.Msg {
@@ -245,22 +321,37 @@ func (c *TableReadController) Rescan() tea.Msg {
 func (c *TableReadController) doScan(resultSet *models.ResultSet, query models.Queryable, pushBackstack bool, op resultSetUpdateOp) tea.Msg {
 	return NewJob(c.jobController, "Rescan…", func(ctx context.Context) (*models.ResultSet, error) {
 		newResultSet, err := c.tableService.ScanOrQuery(ctx, resultSet.TableInfo, query, resultSet.LastEvaluatedKey)
-		if newResultSet != nil {
-			newResultSet = c.tableService.Filter(newResultSet, c.state.Filter())
-		}
-
-		return newResultSet, err
+		return c.filterResultSet(newResultSet, c.state.Filter(), err)
 	}).OnEither(c.handleResultSetFromJobResult(c.state.Filter(), pushBackstack, false, op)).Submit()
 }
 
 func (c *TableReadController) setResultSetAndFilter(resultSet *models.ResultSet, filter string, pushBackstack bool, op resultSetUpdateOp) tea.Msg {
+	return c.setResultSetAndFilterWithStatus(resultSet, filter, pushBackstack, op, "")
+}
+
+func (c *TableReadController) setResultSetAndFilterWithStatus(
+	resultSet *models.ResultSet,
+	filter string,
+	pushBackstack bool,
+	op resultSetUpdateOp,
+	statusMessage string,
+) tea.Msg {
 	if resultSet != nil && pushBackstack {
+		conn := c.state.Connection()
 		details := serialisable.ViewSnapshotDetails{
+			Connection: serialisable.ViewSnapshotConnection{
+				Profile:  conn.Profile,
+				Region:   conn.Region,
+				Endpoint: conn.Endpoint,
+			},
 			TableName: resultSet.TableInfo.Name,
 			Filter:    filter,
 		}
 
-		if q := resultSet.Query; q != nil {
+		if stmt, isStmt := resultSet.Query.(*partiql.Statement); isStmt {
+			details.PartiQL = stmt.Statement()
+			details.QueryHash = stmt.HashCode()
+		} else if q := resultSet.Query; q != nil {
 			if bs, err := q.SerializeToBytes(); err == nil {
 				details.Query = bs
 				details.QueryHash = q.HashCode()
@@ -288,7 +379,7 @@ func (c *TableReadController) setResultSetAndFilter(resultSet *models.ResultSet,
 
 	c.eventBus.Fire(newResultSetEvent, resultSet, op)
 
-	return c.state.buildNewResultSetMessage("")
+	return c.state.buildNewResultSetMessage(statusMessage)
 }
 
 func (c *TableReadController) Mark(op MarkOp, where string) tea.Msg {
@@ -347,13 +438,26 @@ func (c *TableReadController) Filter() tea.Msg {
 			}
 
 			return NewJob(c.jobController, "Applying Filter…", func(ctx context.Context) (*models.ResultSet, error) {
-				newResultSet := c.tableService.Filter(resultSet, value)
-				return newResultSet, nil
+				return c.tableService.Filter(resultSet, value)
 			}).OnEither(c.handleResultSetFromJobResult(value, true, false, resultSetUpdateFilter)).Submit()
 		},
 	}
 }
 
+// filterResultSet applies the filter to a result set which has been read.  An error applying the filter is only
+// returned if the result set was read without error.
+func (c *TableReadController) filterResultSet(resultSet *models.ResultSet, filter string, err error) (*models.ResultSet, error) {
+	if resultSet == nil {
+		return nil, err
+	}
+
+	resultSet, filterErr := c.tableService.Filter(resultSet, filter)
+	if err == nil {
+		err = filterErr
+	}
+	return resultSet, err
+}
+
 func (c *TableReadController) handleResultSetFromJobResult(
 	filter string,
 	pushbackStack, errIfEmpty bool,
@@ -382,6 +486,11 @@ func (c *TableReadController) handleResultSetFromJobResult(
 			})
 		}
 
+		var filterErr models.FilterError
+		if errors.As(err, &filterErr) && newResultSet != nil {
+			return c.setResultSetAndFilterWithStatus(newResultSet, filter, pushbackStack, op, filterErr.Error())
+		}
+
 		if newResultSet != nil {
 			return c.setResultSetAndFilter(newResultSet, filter, pushbackStack, op)
 		}
@@ -428,11 +537,40 @@ func (c *TableReadController) NextPage() tea.Msg {
 }
 
 func (c *TableReadController) updateViewToSnapshot(viewSnapshot *serialisable.ViewSnapshot) tea.Msg {
+	snapshotConn := models.Connection{
+		Profile:  viewSnapshot.Details.Connection.Profile,
+		Region:   viewSnapshot.Details.Connection.Region,
+		Endpoint: viewSnapshot.Details.Connection.Endpoint,
+	}
+	if snapshotConn.IsZero() || snapshotConn == c.state.Connection() {
+		return c.restoreSnapshot(viewSnapshot, c.state.ResultSet())
+	}
+
+	// The view was taken using a different connection, so switch to it before restoring the view.
+	return NewJob(c.jobController, "Connecting to "+snapshotConn.String()+"…", func(ctx context.Context) (models.Connection, error) {
+		if err := c.tableService.Connect(ctx, snapshotConn); err != nil {
+			return models.Connection{}, err
+		}
+		return c.tableService.Connection(), nil
+	}).OnDone(func(newConn models.Connection) tea.Msg {
+		c.state.setConnection(newConn)
+		return events.WrappedStatusMsg{
+			Message: events.StatusMsg("Switched connection to " + newConn.String()),
+			Next:    c.restoreSnapshot(viewSnapshot, nil),
+		}
+	}).Submit()
+}
+
+func (c *TableReadController) restoreSnapshot(viewSnapshot *serialisable.ViewSnapshot, currentResultSet *models.ResultSet) tea.Msg {
 	var err error
-	currentResultSet := c.state.ResultSet()
 
-	var query *queryexpr.QueryExpr
-	if len(viewSnapshot.Details.Query) > 0 {
+	var query models.Queryable
+	if viewSnapshot.Details.PartiQL != "" {
+		query, err = partiql.Parse(viewSnapshot.Details.PartiQL)
+		if err != nil {
+			return err
+		}
+	} else if len(viewSnapshot.Details.Query) > 0 {
 		query, err = queryexpr.DeserializeFrom(bytes.NewReader(viewSnapshot.Details.Query))
 		if err != nil {
 			return err
@@ -460,13 +598,20 @@ func (c *TableReadController) updateViewToSnapshot(viewSnapshot *serialisable.Vi
 	}
 
 	queryEqualsCurrentQuery := false
-	if q, ok := currentResultSet.Query.(*queryexpr.QueryExpr); ok && q != nil {
-		queryEqualsCurrentQuery = q.Equal(query)
+	switch q := currentResultSet.Query.(type) {
+	case *queryexpr.QueryExpr:
+		if otherQ, isQueryExpr := query.(*queryexpr.QueryExpr); isQueryExpr && q != nil {
+			queryEqualsCurrentQuery = q.Equal(otherQ)
+		}
+	case *partiql.Statement:
+		if otherStmt, isStmt := query.(*partiql.Statement); isStmt {
+			queryEqualsCurrentQuery = q.Statement() == otherStmt.Statement()
+		}
 	}
 
 	if viewSnapshot.Details.TableName == currentResultSet.TableInfo.Name && queryEqualsCurrentQuery {
 		return NewJob(c.jobController, "Applying filter…", func(ctx context.Context) (*models.ResultSet, error) {
-			return c.tableService.Filter(currentResultSet, viewSnapshot.Details.Filter), nil
+			return c.tableService.Filter(currentResultSet, viewSnapshot.Details.Filter)
 		}).OnEither(c.handleResultSetFromJobResult(viewSnapshot.Details.Filter, false, false, resultSetUpdateSnapshotRestore)).Submit()
 	}
 
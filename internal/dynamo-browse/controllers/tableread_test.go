@@ -6,6 +6,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lmika/dynamo-browse/internal/common/ui/events"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/controllers"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/aggregate"
 	"github.com/lmika/dynamo-browse/test/testdynamo"
 	"github.com/stretchr/testify/assert"
 	"os"
@@ -48,6 +50,65 @@ func TestTableReadController_ListTables(t *testing.T) {
 	})
 }
 
+func TestTableReadController_Connect(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "abc")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "123")
+
+	newConn := models.Connection{Region: "ap-southeast-2", Endpoint: "http://localhost:4566"}
+
+	t.Run("should switch connection once a table is selected", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+		invokeCommand(t, srv.readController.Init())
+
+		event := srv.readController.Connect(newConn).(controllers.PromptForTableMsg)
+		assert.Equal(t, []string{"alpha-table", "bravo-table", "count-to-30"}, event.Tables)
+
+		resultSet := event.OnSelected("bravo-table").(controllers.NewResultSet)
+		assert.Equal(t, "bravo-table", resultSet.ResultSet.TableInfo.Name)
+		assert.Equal(t, newConn, srv.readController.Connection())
+	})
+
+	t.Run("should keep the current connection and result set if no table is selected", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+		invokeCommand(t, srv.readController.Init())
+		prevConn := srv.readController.Connection()
+
+		event := srv.readController.Connect(newConn).(controllers.PromptForTableMsg)
+		invokeCommand(t, event.OnSelected(""))
+
+		assert.Equal(t, prevConn, srv.readController.Connection())
+		assert.Equal(t, "alpha-table", srv.state.ResultSet().TableInfo.Name)
+	})
+
+	t.Run("should keep the current connection and result set if the table cannot be scanned", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+		invokeCommand(t, srv.readController.Init())
+		prevConn := srv.readController.Connection()
+
+		event := srv.readController.Connect(newConn).(controllers.PromptForTableMsg)
+		invokeCommandExpectingError(t, event.OnSelected("missing-table"))
+
+		assert.Equal(t, prevConn, srv.readController.Connection())
+		assert.Equal(t, "alpha-table", srv.state.ResultSet().TableInfo.Name)
+	})
+}
+
+func TestTableReadController_DescribeTable(t *testing.T) {
+	t.Run("should show the description of the current table", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "bravo-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		msg := invokeCommand(t, srv.readController.DescribeTable())
+
+		assert.IsType(t, controllers.ShowTextOverlay{}, msg)
+
+		textOverlay := msg.(controllers.ShowTextOverlay)
+		assert.Equal(t, "Table: bravo-table", textOverlay.Title)
+		assert.Regexp(t, `Partition key\s+pk\n`, textOverlay.Text)
+		assert.Regexp(t, `Sort key\s+sk\n`, textOverlay.Text)
+	})
+}
+
 func TestTableReadController_Rescan(t *testing.T) {
 	t.Run("should perform a rescan", func(t *testing.T) {
 		srv := newService(t, serviceConfig{tableName: "bravo-table"})
@@ -89,7 +150,7 @@ func TestTableReadController_Query(t *testing.T) {
 
 		invokeCommand(t, srv.readController.Init())
 		invokeCommandWithPrompts(t, srv.readController.PromptForQuery(), `pk ^= "abc"`)
-		invokeCommand(t, srv.exportController.ExportCSV(tempFile, controllers.ExportOptions{}))
+		invokeCommand(t, srv.exportController.Export(tempFile, controllers.ExportOptions{}))
 
 		bts, err := os.ReadFile(tempFile)
 		assert.NoError(t, err)
@@ -107,7 +168,7 @@ func TestTableReadController_Query(t *testing.T) {
 
 		invokeCommand(t, srv.readController.Init())
 		invokeCommandWithPrompts(t, srv.readController.PromptForQuery(), `alpha = "This is some value"`)
-		invokeCommand(t, srv.exportController.ExportCSV(tempFile, controllers.ExportOptions{}))
+		invokeCommand(t, srv.exportController.Export(tempFile, controllers.ExportOptions{}))
 
 		bts, err := os.ReadFile(tempFile)
 		assert.NoError(t, err)
@@ -118,13 +179,22 @@ func TestTableReadController_Query(t *testing.T) {
 		}, ""))
 	})
 
+	t.Run("should return error if the table of a PartiQL statement cannot be determined", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithPromptsExpectingError(t, srv.readController.PromptForQuery(), `sql: SELECT * FROM "alpha-table`)
+
+		assert.Equal(t, "alpha-table", srv.state.ResultSet().TableInfo.Name)
+	})
+
 	t.Run("should return error if result set is not set", func(t *testing.T) {
 		srv := newService(t, serviceConfig{tableName: "non-existant-table"})
 
 		tempFile := tempFile(t)
 
 		invokeCommandExpectingError(t, srv.readController.Init())
-		invokeCommandExpectingError(t, srv.exportController.ExportCSV(tempFile, controllers.ExportOptions{}))
+		invokeCommandExpectingError(t, srv.exportController.Export(tempFile, controllers.ExportOptions{}))
 	})
 }
 
@@ -176,6 +246,68 @@ func TestTableReadController_NextPage(t *testing.T) {
 	})
 }
 
+func TestTableReadController_Aggregate(t *testing.T) {
+	t.Run("should count all items matching the where expression", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "count-to-30"})
+
+		invokeCommand(t, srv.readController.Init())
+
+		agg, err := aggregate.New("count", "", "")
+		assert.NoError(t, err)
+		invokeCommand(t, srv.readController.Aggregate(agg, `pk = "NUM" and num > 10`))
+
+		rs := srv.state.ResultSet()
+		assert.Equal(t, "aggregate of count-to-30", rs.TableInfo.Name)
+		assert.Len(t, rs.Items(), 1)
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "20"}, rs.Items()[0]["count"])
+	})
+
+	t.Run("should aggregate all items of the table by group", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+
+		agg, err := aggregate.New("sum", "beta", "pk")
+		assert.NoError(t, err)
+		invokeCommand(t, srv.readController.Aggregate(agg, ""))
+
+		rs := srv.state.ResultSet()
+		assert.Len(t, rs.Items(), 2)
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "abc"}, rs.Items()[0]["pk"])
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "1231"}, rs.Items()[0]["sum"])
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "bbb"}, rs.Items()[1]["pk"])
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "2468"}, rs.Items()[1]["sum"])
+	})
+}
+
+func TestTableReadController_GetKeys(t *testing.T) {
+	t.Run("should get the items with the keys listed in the file", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		keyFile := tempFile(t)
+		assert.NoError(t, os.WriteFile(keyFile, []byte("pk,sk\nabc,222\nbbb,131\nabc,222\nzzz,999\n"), 0644))
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommand(t, srv.readController.GetKeys(keyFile))
+
+		rs := srv.state.ResultSet()
+		assert.Len(t, rs.Items(), 2)
+		assert.Equal(t, "abc", rs.Items()[0]["pk"].(*types.AttributeValueMemberS).Value)
+		assert.Equal(t, "222", rs.Items()[0]["sk"].(*types.AttributeValueMemberS).Value)
+		assert.Equal(t, "bbb", rs.Items()[1]["pk"].(*types.AttributeValueMemberS).Value)
+	})
+
+	t.Run("should return error if a key is missing key attributes", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		keyFile := tempFile(t)
+		assert.NoError(t, os.WriteFile(keyFile, []byte("pk\nabc\n"), 0644))
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandExpectingError(t, srv.readController.GetKeys(keyFile))
+	})
+}
+
 func tempFile(t *testing.T) string {
 	t.Helper()
 
@@ -232,6 +364,20 @@ func invokeCommandWithPromptsExpectingError(t *testing.T, msg tea.Msg, promptVal
 	assert.True(t, isErr)
 }
 
+func invokeCommandWithDiffConfirmation(t *testing.T, msg tea.Msg, confirm bool) {
+	diffOverlay, isDiffOverlay := msg.(controllers.ShowDiffOverlay)
+	if !isDiffOverlay {
+		assert.Fail(t, fmt.Sprintf("expected diff overlay but didn't get one: %T", msg))
+		return
+	}
+
+	if confirm {
+		invokeCommand(t, diffOverlay.OnConfirm())
+	} else {
+		invokeCommand(t, diffOverlay.OnCancel())
+	}
+}
+
 func invokeCommandExpectingError(t *testing.T, msg tea.Msg) {
 	_, isErr := msg.(events.ErrorMsg)
 	assert.True(t, isErr)
@@ -8,11 +8,16 @@ import (
 	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
 	"github.com/lmika/dynamo-browse/internal/common/ui/events"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/itemrender"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/queryexpr"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/jobs"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/tables"
 	"github.com/pkg/errors"
+	"io"
 	"log"
+	"os"
 	"strconv"
+	"time"
 )
 
 type TableWriteController struct {
@@ -21,6 +26,9 @@ type TableWriteController struct {
 	jobController        *JobsController
 	tableReadControllers *TableReadController
 	settingProvider      SettingsProvider
+
+	// beforePut, if set, is called with the items about to be put.  Returning an error will abort the put.
+	beforePut func(ctx context.Context, rs *models.ResultSet, items []models.ItemIndex) error
 }
 
 func NewTableWriteController(
@@ -71,10 +79,12 @@ func (twc *TableWriteController) NewItem() tea.Msg {
 				newItem[rs.TableInfo.Keys.SortKey] = &types.AttributeValueMemberS{Value: values[1]}
 			}
 
-			set.AddNewItem(newItem, models.ItemAttribute{
+			edit := set.RecordEdit()
+			edit.Add(newItem, models.ItemAttribute{
 				New:   true,
 				Dirty: true,
 			})
+			edit.Commit()
 		})
 		return twc.state.buildNewResultSetMessage("New item added")
 	}
@@ -134,11 +144,14 @@ func (twc *TableWriteController) setStringValue(idx int, attr *queryexpr.QueryEx
 		Prompt: "string value: ",
 		OnDone: func(value string) tea.Msg {
 			if err := twc.state.withResultSetReturningError(func(set *models.ResultSet) error {
+				edit := set.RecordEdit()
+				defer edit.Commit()
+
 				if err := applyToMarkedItems(set, idx, func(idx int, item models.Item) error {
+					edit.Modify(idx)
 					if err := attr.SetEvalItem(item, &types.AttributeValueMemberS{Value: value}); err != nil {
 						return err
 					}
-					set.SetDirty(idx, true)
 					return nil
 				}); err != nil {
 					return err
@@ -163,7 +176,11 @@ func (twc *TableWriteController) setToExpressionValue(idx int, attr *queryexpr.Q
 			}
 
 			if err := twc.state.withResultSetReturningError(func(set *models.ResultSet) error {
+				edit := set.RecordEdit()
+				defer edit.Commit()
+
 				if err := applyToMarkedItems(set, idx, func(idx int, item models.Item) error {
+					edit.Modify(idx)
 					newValue, err := valueExpr.EvalItem(item)
 					if err != nil {
 						return err
@@ -171,7 +188,6 @@ func (twc *TableWriteController) setToExpressionValue(idx int, attr *queryexpr.Q
 					if err := attr.SetEvalItem(item, newValue); err != nil {
 						return err
 					}
-					set.SetDirty(idx, true)
 					return nil
 				}); err != nil {
 					return err
@@ -191,11 +207,14 @@ func (twc *TableWriteController) setNumberValue(idx int, attr *queryexpr.QueryEx
 		Prompt: "number value: ",
 		OnDone: func(value string) tea.Msg {
 			if err := twc.state.withResultSetReturningError(func(set *models.ResultSet) error {
+				edit := set.RecordEdit()
+				defer edit.Commit()
+
 				if err := applyToMarkedItems(set, idx, func(idx int, item models.Item) error {
+					edit.Modify(idx)
 					if err := attr.SetEvalItem(item, &types.AttributeValueMemberN{Value: value}); err != nil {
 						return err
 					}
-					set.SetDirty(idx, true)
 					return nil
 				}); err != nil {
 					return err
@@ -220,11 +239,14 @@ func (twc *TableWriteController) setBoolValue(idx int, attr *queryexpr.QueryExpr
 			}
 
 			if err := twc.state.withResultSetReturningError(func(set *models.ResultSet) error {
+				edit := set.RecordEdit()
+				defer edit.Commit()
+
 				if err := applyToMarkedItems(set, idx, func(idx int, item models.Item) error {
+					edit.Modify(idx)
 					if err := attr.SetEvalItem(item, &types.AttributeValueMemberBOOL{Value: b}); err != nil {
 						return err
 					}
-					set.SetDirty(idx, true)
 					return nil
 				}); err != nil {
 					return err
@@ -241,11 +263,14 @@ func (twc *TableWriteController) setBoolValue(idx int, attr *queryexpr.QueryExpr
 
 func (twc *TableWriteController) setNullValue(idx int, attr *queryexpr.QueryExpr) tea.Msg {
 	if err := twc.state.withResultSetReturningError(func(set *models.ResultSet) error {
+		edit := set.RecordEdit()
+		defer edit.Commit()
+
 		if err := applyToMarkedItems(set, idx, func(idx int, item models.Item) error {
+			edit.Modify(idx)
 			if err := attr.SetEvalItem(item, &types.AttributeValueMemberNULL{Value: true}); err != nil {
 				return err
 			}
-			set.SetDirty(idx, true)
 			return nil
 		}); err != nil {
 			return err
@@ -274,12 +299,14 @@ func (twc *TableWriteController) DeleteAttribute(idx int, key string) tea.Msg {
 	}
 
 	if err := twc.state.withResultSetReturningError(func(set *models.ResultSet) error {
+		edit := set.RecordEdit()
+		defer edit.Commit()
+
 		if err := applyToMarkedItems(set, idx, func(idx int, item models.Item) error {
+			edit.Modify(idx)
 			if err := path.DeleteAttribute(set.Items()[idx]); err != nil {
 				return err
 			}
-
-			set.SetDirty(idx, true)
 			return nil
 		}); err != nil {
 			return err
@@ -301,10 +328,12 @@ func (twc *TableWriteController) PutItems() tea.Msg {
 
 	var (
 		markedItemCount int
+		projected       bool
 	)
 	var itemsToPut []models.ItemIndex
 
 	twc.state.withResultSet(func(rs *models.ResultSet) {
+		projected = rs.Projected
 		if markedItems := rs.MarkedItems(); len(markedItems) > 0 {
 			for _, mi := range markedItems {
 				markedItemCount += 1
@@ -327,6 +356,8 @@ func (twc *TableWriteController) PutItems() tea.Msg {
 		} else {
 			return events.StatusMsg("no items are modified")
 		}
+	} else if projected {
+		return events.Error(models.ErrProjectedItems)
 	}
 
 	var promptMessage string
@@ -336,52 +367,151 @@ func (twc *TableWriteController) PutItems() tea.Msg {
 		promptMessage = applyToN("put ", len(itemsToPut), "item", "items", "? ")
 	}
 
-	return events.PromptForInputMsg{
-		Prompt: promptMessage,
-		OnDone: func(value string) tea.Msg {
-			if value != "y" {
-				return events.StatusMsg("operation aborted")
+	// The before put hooks are run before the changes are shown, so that the changes made by the hooks are confirmed
+	return NewJob(twc.jobController, "Running before put hooks…", func(ctx context.Context) (*models.ResultSet, error) {
+		rs := twc.state.ResultSet()
+		err := twc.runBeforePut(ctx, rs, itemsToPut)
+		return rs, err
+	}).OnDone(func(rs *models.ResultSet) tea.Msg {
+		itemDiffs := make([]ItemDiff, len(itemsToPut))
+		for i, item := range itemsToPut {
+			description := describeItemKey(rs.TableInfo, item.Item)
+			if rs.IsNew(item.Index) {
+				description += " (new)"
+			}
+			itemDiffs[i] = ItemDiff{
+				Description: description,
+				Diff:        itemrender.Diff(rs.OriginalItem(item.Index), item.Item),
 			}
+		}
 
-			return NewJob(twc.jobController, "Updating items…", func(ctx context.Context) (*models.ResultSet, error) {
-				rs := twc.state.ResultSet()
-				err := twc.tableService.PutSelectedItems(ctx, rs, itemsToPut)
-				if err != nil {
-					return nil, err
-				}
-				return rs, nil
-			}).OnDone(func(rs *models.ResultSet) tea.Msg {
-				return ResultSetUpdated{
-					statusMessage: applyToN("", len(itemsToPut), "item", "item", " put to table"),
-				}
-			}).Submit()
-		},
+		return ShowDiffOverlay{
+			Title:    promptMessage,
+			Items:    itemDiffs,
+			OnCancel: abortOperation,
+			OnConfirm: func() tea.Msg {
+				return NewJob(twc.jobController, "Updating items…", func(ctx context.Context) (*models.ResultSet, error) {
+					err := twc.tableService.PutSelectedItems(ctx, rs, itemsToPut)
+					return rs, err
+				}).OnEither(func(rs *models.ResultSet, err error) tea.Msg {
+					var conflictErr models.PutConflictError
+					if errors.As(err, &conflictErr) {
+						return ResultSetUpdated{
+							statusMessage: applyToN("", len(itemsToPut)-conflictErr.Count, "item", "items", " put to table") +
+								applyToN(", ", conflictErr.Count, "item", "items", " modified since read"),
+						}
+					} else if err != nil {
+						return events.Error(err)
+					}
+
+					return ResultSetUpdated{
+						statusMessage: applyToN("", len(itemsToPut), "item", "item", " put to table"),
+					}
+				}).Submit()
+			},
+		}
+	}).Submit()
+}
+
+// runBeforePut calls the before put hook with the items about to be put.  Hooks can modify the items, so this is
+// to be called before the changes are shown to the user.
+func (twc *TableWriteController) runBeforePut(ctx context.Context, rs *models.ResultSet, items []models.ItemIndex) error {
+	if twc.beforePut == nil {
+		return nil
 	}
+	return twc.beforePut(ctx, rs, items)
 }
 
-func (twc *TableWriteController) TouchItem(idx int) tea.Msg {
+func (twc *TableWriteController) ImportItems(filename string, opts ImportOptions) tea.Msg {
 	if err := twc.assertReadWrite(); err != nil {
 		return events.Error(err)
 	}
 
 	resultSet := twc.state.ResultSet()
-	if resultSet.IsDirty(idx) {
-		return events.Error(errors.New("cannot touch dirty items"))
+	if resultSet == nil {
+		return events.Error(errors.New("no result set"))
 	}
+	tableInfo := resultSet.TableInfo
 
-	return events.PromptForInputMsg{
-		Prompt: "touch item? ",
-		OnDone: func(value string) tea.Msg {
-			if value != "y" {
-				return nil
+	return NewJob(twc.jobController, fmt.Sprintf("Importing from %v…", filename), func(ctx context.Context) ([]models.Item, error) {
+		f, err := os.Open(filename)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot import from '%v'", filename)
+		}
+		defer f.Close()
+
+		var (
+			items      = make([]models.Item, 0)
+			nextUpdate = time.Now().Add(1 * time.Second)
+			ir         = newItemReader(f, opts.Format)
+		)
+		for {
+			item, err := ir.next()
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, errors.Wrapf(err, "cannot import item %d from '%v'", len(items)+1, filename)
 			}
 
-			if err := twc.tableService.PutItemAt(context.Background(), resultSet, idx); err != nil {
-				return events.Error(err)
+			if pk, sk := item.PKSK(tableInfo); pk == nil || (tableInfo.Keys.SortKey != "" && sk == nil) {
+				return nil, errors.Errorf("item %d from '%v' is missing key attributes", len(items)+1, filename)
 			}
-			return ResultSetUpdated{}
-		},
+			if err := checkKeyTypes(item, tableInfo, opts.Format); err != nil {
+				return nil, errors.Wrapf(err, "item %d from '%v'", len(items)+1, filename)
+			}
+			items = append(items, item)
+
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if time.Now().After(nextUpdate) {
+				jobs.PostUpdate(ctx, fmt.Sprintf("read %d items", len(items)))
+				nextUpdate = time.Now().Add(1 * time.Second)
+			}
+		}
+		return items, nil
+	}).OnDone(func(items []models.Item) tea.Msg {
+		var rs *models.ResultSet
+		if err := twc.state.withResultSetReturningError(func(set *models.ResultSet) error {
+			if set == nil || set.TableInfo.Name != tableInfo.Name {
+				return errors.Errorf("table changed while importing from '%v': items were read for '%v'", filename, tableInfo.Name)
+			}
+
+			edit := set.RecordEdit()
+			for _, item := range items {
+				edit.Add(item, models.ItemAttribute{
+					New:   true,
+					Dirty: true,
+				})
+			}
+			edit.Commit()
+			set.RefreshColumns()
+			rs = set
+			return nil
+		}); err != nil {
+			return events.Error(err)
+		}
+		twc.tableReadControllers.eventBus.Fire(newResultSetEvent, rs, resultSetUpdateImport)
+		return twc.state.buildNewResultSetMessage(applyToN("Imported ", len(items), "item", "items", " from "+filename))
+	}).Submit()
+}
+
+func (twc *TableWriteController) TouchItem(idx int) tea.Msg {
+	if err := twc.assertReadWrite(); err != nil {
+		return events.Error(err)
+	}
+
+	resultSet := twc.state.ResultSet()
+	if resultSet.IsDirty(idx) {
+		return events.Error(errors.New("cannot touch dirty items"))
 	}
+
+	return events.ConfirmYes("touch item? ", func() tea.Msg {
+		if err := twc.tableService.PutItemAt(context.Background(), resultSet, idx); err != nil {
+			return events.Error(err)
+		}
+		return ResultSetUpdated{}
+	})
 }
 
 func (twc *TableWriteController) NoisyTouchItem(idx int) tea.Msg {
@@ -392,29 +522,24 @@ func (twc *TableWriteController) NoisyTouchItem(idx int) tea.Msg {
 	resultSet := twc.state.ResultSet()
 	if resultSet.IsDirty(idx) {
 		return events.Error(errors.New("cannot noisy touch dirty items"))
+	} else if resultSet.Projected {
+		return events.Error(models.ErrProjectedItems)
 	}
 
-	return events.PromptForInputMsg{
-		Prompt: "noisy touch item? ",
-		OnDone: func(value string) tea.Msg {
-			ctx := context.Background()
-
-			if value != "y" {
-				return nil
-			}
+	return events.ConfirmYes("noisy touch item? ", func() tea.Msg {
+		ctx := context.Background()
 
-			item := resultSet.Items()[0]
-			if err := twc.tableService.Delete(ctx, resultSet.TableInfo, []models.Item{item}); err != nil {
-				return events.Error(err)
-			}
+		item := resultSet.Items()[0]
+		if err := twc.tableService.Delete(ctx, resultSet.TableInfo, []models.Item{item}); err != nil {
+			return events.Error(err)
+		}
 
-			if err := twc.tableService.Put(ctx, resultSet.TableInfo, item); err != nil {
-				return events.Error(err)
-			}
+		if err := twc.tableService.Put(ctx, resultSet.TableInfo, item); err != nil {
+			return events.Error(err)
+		}
 
-			return twc.tableReadControllers.doScan(resultSet, resultSet.Query, false, resultSetUpdateTouch)
-		},
-	}
+		return twc.tableReadControllers.doScan(resultSet, resultSet.Query, false, resultSetUpdateTouch)
+	})
 }
 
 func (twc *TableWriteController) DeleteMarked() tea.Msg {
@@ -429,13 +554,19 @@ func (twc *TableWriteController) DeleteMarked() tea.Msg {
 		return events.StatusMsg("no marked items")
 	}
 
-	return events.PromptForInputMsg{
-		Prompt: applyToN("delete ", len(markedItems), "item", "items", "? "),
-		OnDone: func(value string) tea.Msg {
-			if value != "y" {
-				return events.StatusMsg("operation aborted")
-			}
+	itemDiffs := make([]ItemDiff, len(markedItems))
+	for i, item := range markedItems {
+		itemDiffs[i] = ItemDiff{
+			Description: describeItemKey(resultSet.TableInfo, item.Item),
+			Diff:        itemrender.Diff(item.Item, nil),
+		}
+	}
 
+	return ShowDiffOverlay{
+		Title:    applyToN("delete ", len(markedItems), "item", "items", "? "),
+		Items:    itemDiffs,
+		OnCancel: abortOperation,
+		OnConfirm: func() tea.Msg {
 			return NewJob(twc.jobController, "Deleting items…", func(ctx context.Context) (struct{}, error) {
 				err := twc.tableService.Delete(ctx, resultSet.TableInfo, sliceutils.Map(markedItems, func(index models.ItemIndex) models.Item {
 					return index.Item
@@ -448,6 +579,39 @@ func (twc *TableWriteController) DeleteMarked() tea.Msg {
 	}
 }
 
+func (twc *TableWriteController) Undo() tea.Msg {
+	return twc.undoOrRedo("undo", (*models.ResultSet).Undo)
+}
+
+func (twc *TableWriteController) Redo() tea.Msg {
+	return twc.undoOrRedo("redo", (*models.ResultSet).Redo)
+}
+
+func (twc *TableWriteController) undoOrRedo(opName string, op func(rs *models.ResultSet) (models.ItemEdit, bool)) tea.Msg {
+	var (
+		edit models.ItemEdit
+		ok   bool
+	)
+	twc.state.withResultSet(func(set *models.ResultSet) {
+		if set == nil {
+			return
+		}
+		edit, ok = op(set)
+		if ok {
+			set.RefreshColumns()
+		}
+	})
+	if !ok {
+		return events.StatusMsg("nothing to " + opName)
+	}
+
+	statusMessage := applyToN(opName+": ", len(edit.Changes), "item", "items", " changed")
+	if edit.AddsItems() {
+		return twc.state.buildNewResultSetMessage(statusMessage)
+	}
+	return ResultSetUpdated{statusMessage: statusMessage}
+}
+
 func (twc *TableWriteController) assertReadWrite() error {
 	b, err := twc.settingProvider.IsReadOnly()
 	if err != nil {
@@ -474,8 +638,10 @@ func (twc *TableWriteController) CloneItem(idx int) tea.Msg {
 	}
 	keyPrompts.onAllDone = func(values []string) tea.Msg {
 		twc.state.withResultSet(func(set *models.ResultSet) {
+			edit := set.RecordEdit()
+			defer edit.Commit()
+
 			applyToMarkedItems(set, idx, func(idx int, item models.Item) error {
-				// TODO: should be a deep clone
 				clonedItem := item.Clone()
 
 				clonedItem[rs.TableInfo.Keys.PartitionKey] = &types.AttributeValueMemberS{Value: values[0]}
@@ -483,7 +649,7 @@ func (twc *TableWriteController) CloneItem(idx int) tea.Msg {
 					clonedItem[rs.TableInfo.Keys.SortKey] = &types.AttributeValueMemberS{Value: values[1]}
 				}
 
-				set.AddNewItem(clonedItem, models.ItemAttribute{
+				edit.Add(clonedItem, models.ItemAttribute{
 					New:   true,
 					Dirty: true,
 				})
@@ -496,6 +662,10 @@ func (twc *TableWriteController) CloneItem(idx int) tea.Msg {
 	return keyPrompts.next()
 }
 
+func abortOperation() tea.Msg {
+	return events.StatusMsg("operation aborted")
+}
+
 func applyToN(prefix string, n int, singular, plural, suffix string) string {
 	if n == 1 {
 		return fmt.Sprintf("%v%v %v%v", prefix, n, singular, suffix)
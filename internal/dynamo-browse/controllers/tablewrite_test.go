@@ -1,12 +1,16 @@
 package controllers_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
 	"github.com/lmika/dynamo-browse/internal/common/ui/commandctrl"
+	"github.com/lmika/dynamo-browse/internal/common/ui/events"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/controllers"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/itemrender"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/providers/dynamo"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/providers/inputhistorystore"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/providers/pasteboardprovider"
@@ -23,6 +27,7 @@ import (
 	bus "github.com/lmika/events"
 	"github.com/stretchr/testify/assert"
 	"io/fs"
+	"os"
 	"sync"
 	"testing"
 	"testing/fstest"
@@ -72,29 +77,34 @@ func TestTableWriteController_SetAttributeValue(t *testing.T) {
 	t.Run("should preserve the type of the field if unspecified", func(t *testing.T) {
 
 		scenarios := []struct {
-			attrKey   string
-			attrValue string
-			expected  types.AttributeValue
+			attrKey       string
+			attrValue     string
+			expected      types.AttributeValue
+			expectedDirty bool
 		}{
 			{
-				attrKey:   "alpha",
-				attrValue: "a new value",
-				expected:  &types.AttributeValueMemberS{Value: "a new value"},
+				attrKey:       "alpha",
+				attrValue:     "a new value",
+				expected:      &types.AttributeValueMemberS{Value: "a new value"},
+				expectedDirty: true,
 			},
 			{
-				attrKey:   "age",
-				attrValue: "1234",
-				expected:  &types.AttributeValueMemberN{Value: "1234"},
+				attrKey:       "age",
+				attrValue:     "1234",
+				expected:      &types.AttributeValueMemberN{Value: "1234"},
+				expectedDirty: true,
 			},
 			{
-				attrKey:   "useMailing",
-				attrValue: "t",
-				expected:  &types.AttributeValueMemberBOOL{Value: true},
+				attrKey:       "useMailing",
+				attrValue:     "t",
+				expected:      &types.AttributeValueMemberBOOL{Value: true},
+				expectedDirty: false,
 			},
 			{
-				attrKey:   "useMailing",
-				attrValue: "f",
-				expected:  &types.AttributeValueMemberBOOL{Value: false},
+				attrKey:       "useMailing",
+				attrValue:     "f",
+				expected:      &types.AttributeValueMemberBOOL{Value: false},
+				expectedDirty: true,
 			},
 		}
 
@@ -107,7 +117,7 @@ func TestTableWriteController_SetAttributeValue(t *testing.T) {
 
 				after, _ := srv.state.ResultSet().Items()[0][scenario.attrKey]
 				assert.Equal(t, scenario.expected, after)
-				assert.True(t, srv.state.ResultSet().IsDirty(0))
+				assert.Equal(t, scenario.expectedDirty, srv.state.ResultSet().IsDirty(0))
 			})
 		}
 	})
@@ -240,6 +250,78 @@ func TestTableWriteController_DeleteAttribute(t *testing.T) {
 	})
 }
 
+func TestTableWriteController_UndoRedo(t *testing.T) {
+	t.Run("should undo and redo changes to attributes", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(0, models.StringItemType, "alpha"), "a new value")
+		invokeCommand(t, srv.writeController.DeleteAttribute(0, "age"))
+
+		assert.Equal(t, "a new value", srv.state.ResultSet().Items()[0]["alpha"].(*types.AttributeValueMemberS).Value)
+		assert.NotContains(t, srv.state.ResultSet().Items()[0], "age")
+		assert.True(t, srv.state.ResultSet().IsDirty(0))
+
+		invokeCommand(t, srv.writeController.Undo())
+		assert.Equal(t, "a new value", srv.state.ResultSet().Items()[0]["alpha"].(*types.AttributeValueMemberS).Value)
+		assert.Contains(t, srv.state.ResultSet().Items()[0], "age")
+		assert.True(t, srv.state.ResultSet().IsDirty(0))
+
+		invokeCommand(t, srv.writeController.Undo())
+		assert.Equal(t, "This is some value", srv.state.ResultSet().Items()[0]["alpha"].(*types.AttributeValueMemberS).Value)
+		assert.False(t, srv.state.ResultSet().IsDirty(0))
+
+		invokeCommand(t, srv.writeController.Redo())
+		assert.Equal(t, "a new value", srv.state.ResultSet().Items()[0]["alpha"].(*types.AttributeValueMemberS).Value)
+		assert.True(t, srv.state.ResultSet().IsDirty(0))
+	})
+
+	t.Run("should undo changes to all marked items as a single edit", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommand(t, srv.writeController.ToggleMark(0))
+		invokeCommand(t, srv.writeController.ToggleMark(1))
+		invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(0, models.StringItemType, "alpha"), "a new value")
+
+		assert.True(t, srv.state.ResultSet().IsDirty(0))
+		assert.True(t, srv.state.ResultSet().IsDirty(1))
+
+		invokeCommand(t, srv.writeController.Undo())
+		assert.Equal(t, "This is some value", srv.state.ResultSet().Items()[0]["alpha"].(*types.AttributeValueMemberS).Value)
+		assert.Equal(t, "This is another some value", srv.state.ResultSet().Items()[1]["alpha"].(*types.AttributeValueMemberS).Value)
+		assert.False(t, srv.state.ResultSet().IsDirty(0))
+		assert.False(t, srv.state.ResultSet().IsDirty(1))
+	})
+
+	t.Run("should remove new items on undo and restore them on redo", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithPrompts(t, srv.writeController.NewItem(), "pk-value", "sk-value")
+		assert.Len(t, srv.state.ResultSet().Items(), 4)
+
+		invokeCommand(t, srv.writeController.Undo())
+		assert.Len(t, srv.state.ResultSet().Items(), 3)
+
+		invokeCommand(t, srv.writeController.Redo())
+		assert.Len(t, srv.state.ResultSet().Items(), 4)
+		assert.Equal(t, "pk-value", srv.state.ResultSet().Items()[3]["pk"].(*types.AttributeValueMemberS).Value)
+		assert.True(t, srv.state.ResultSet().IsNew(3))
+		assert.True(t, srv.state.ResultSet().IsDirty(3))
+	})
+
+	t.Run("should do nothing if there is nothing to undo", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommand(t, srv.writeController.Undo())
+		invokeCommand(t, srv.writeController.Redo())
+
+		assert.Len(t, srv.state.ResultSet().Items(), 3)
+	})
+}
+
 func TestTableWriteController_PutItem(t *testing.T) {
 	t.Run("should put the selected item if dirty", func(t *testing.T) {
 		srv := newService(t, serviceConfig{tableName: "alpha-table"})
@@ -252,7 +334,7 @@ func TestTableWriteController_PutItem(t *testing.T) {
 
 		// Modify the item and put it
 		invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(0, models.StringItemType, "alpha"), "a new value")
-		invokeCommandWithPrompt(t, srv.writeController.PutItems(), "y")
+		invokeCommandWithDiffConfirmation(t, srv.writeController.PutItems(), true)
 
 		// Rescan the table
 		invokeCommand(t, srv.readController.Rescan())
@@ -261,6 +343,28 @@ func TestTableWriteController_PutItem(t *testing.T) {
 		assert.False(t, srv.state.ResultSet().IsDirty(0))
 	})
 
+	t.Run("should show the changes to the item before putting it", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(0, models.StringItemType, "alpha"), "a new value")
+		invokeCommand(t, srv.writeController.DeleteAttribute(0, "age"))
+
+		diffOverlay, isDiffOverlay := srv.writeController.PutItems().(controllers.ShowDiffOverlay)
+		assert.True(t, isDiffOverlay)
+		assert.Len(t, diffOverlay.Items, 1)
+		assert.Equal(t, "pk=abc, sk=111", diffOverlay.Items[0].Description)
+
+		diff := diffOverlay.Items[0].Diff
+		assert.Len(t, diff, 2)
+		assert.Equal(t, "age", diff[0].Key)
+		assert.Equal(t, itemrender.DiffRemoved, diff[0].Type)
+		assert.Equal(t, "alpha", diff[1].Key)
+		assert.Equal(t, itemrender.DiffChanged, diff[1].Type)
+		assert.Equal(t, "This is some value", diff[1].Before.StringValue())
+		assert.Equal(t, "a new value", diff[1].After.StringValue())
+	})
+
 	t.Run("should not put the selected item if user does not confirm", func(t *testing.T) {
 		srv := newService(t, serviceConfig{tableName: "alpha-table"})
 
@@ -272,7 +376,7 @@ func TestTableWriteController_PutItem(t *testing.T) {
 
 		// Modify the item but do not put it
 		invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(0, models.StringItemType, "alpha"), "a new value")
-		invokeCommandWithPrompt(t, srv.writeController.PutItems(), "n")
+		invokeCommandWithDiffConfirmation(t, srv.writeController.PutItems(), false)
 
 		current, _ := srv.state.ResultSet().Items()[0].AttributeValueAsString("alpha")
 		assert.Equal(t, "a new value", current)
@@ -332,7 +436,7 @@ func TestTableWriteController_PutItems(t *testing.T) {
 		invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(0, models.StringItemType, "alpha"), "a new value")
 		invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(2, models.StringItemType, "alpha"), "another new value")
 
-		invokeCommandWithPrompt(t, srv.writeController.PutItems(), "y")
+		invokeCommandWithDiffConfirmation(t, srv.writeController.PutItems(), true)
 
 		// Rescan the table
 		invokeCommand(t, srv.readController.Rescan())
@@ -354,7 +458,7 @@ func TestTableWriteController_PutItems(t *testing.T) {
 		invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(2, models.StringItemType, "alpha"), "another new value")
 		invokeCommand(t, srv.writeController.ToggleMark(0))
 
-		invokeCommandWithPrompt(t, srv.writeController.PutItems(), "y")
+		invokeCommandWithDiffConfirmation(t, srv.writeController.PutItems(), true)
 
 		// Verify dirty items are unchanged
 		assert.Equal(t, "a new value", srv.state.ResultSet().Items()[0]["alpha"].(*types.AttributeValueMemberS).Value)
@@ -402,6 +506,68 @@ func TestTableWriteController_PutItems(t *testing.T) {
 		assert.False(t, srv.state.ResultSet().IsDirty(2))
 	})
 
+	t.Run("should not put items read with selected attributes", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithPrompts(t, srv.readController.PromptForQuery(), `pk="abc" and sk="111" using select(alpha)`)
+		assert.True(t, srv.state.ResultSet().Projected)
+
+		// Modify the item and attempt to put it
+		invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(0, models.StringItemType, "alpha"), "a new value")
+		invokeCommandExpectingError(t, srv.writeController.PutItems())
+		assert.True(t, srv.state.ResultSet().IsDirty(0))
+
+		// Verify the item in the table has kept the attributes which were not selected
+		items, err := srv.provider.BatchGetItems(context.Background(), "alpha-table", []map[string]types.AttributeValue{{
+			"pk": &types.AttributeValueMemberS{Value: "abc"},
+			"sk": &types.AttributeValueMemberS{Value: "111"},
+		}}, models.ReadOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, items, 1)
+
+		item := items[0]
+		assert.Equal(t, "This is some value", item["alpha"].(*types.AttributeValueMemberS).Value)
+		assert.Equal(t, "23", item["age"].(*types.AttributeValueMemberN).Value)
+	})
+
+	for _, putMode := range []models.PutMode{models.PutModeConditional, models.PutModeTransaction} {
+		t.Run(fmt.Sprintf("should flag items modified since read as conflicting in %v put mode", putMode), func(t *testing.T) {
+			srv := newService(t, serviceConfig{tableName: "alpha-table", putMode: putMode})
+
+			invokeCommand(t, srv.readController.Init())
+
+			// Add an attribute to the first item in the table after it has been read
+			modifiedItem := srv.state.ResultSet().Items()[0].Clone()
+			modifiedItem["delta"] = &types.AttributeValueMemberS{Value: "added since read"}
+			assert.NoError(t, srv.provider.PutItem(context.Background(), "alpha-table", modifiedItem))
+
+			// Modify the items and put them
+			invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(0, models.StringItemType, "alpha"), "a new value")
+			invokeCommandWithPrompt(t, srv.writeController.SetAttributeValue(2, models.StringItemType, "alpha"), "another new value")
+
+			diffOverlay, isDiffOverlay := srv.writeController.PutItems().(controllers.ShowDiffOverlay)
+			assert.True(t, isDiffOverlay)
+
+			msg := invokeCommand(t, diffOverlay.OnConfirm())
+			assert.IsType(t, controllers.ResultSetUpdated{}, msg)
+			assert.Equal(t, "1 item put to table, 1 item modified since read", msg.(controllers.ResultSetUpdated).StatusMessage())
+
+			// Verify the conflicting item is flagged and still dirty
+			assert.True(t, srv.state.ResultSet().IsConflict(0))
+			assert.True(t, srv.state.ResultSet().IsDirty(0))
+			assert.False(t, srv.state.ResultSet().IsConflict(2))
+			assert.False(t, srv.state.ResultSet().IsDirty(2))
+
+			// Rescan the table and verify the conflicting item was not written
+			invokeCommandWithPrompt(t, srv.readController.Rescan(), "y")
+
+			assert.Equal(t, "This is some value", srv.state.ResultSet().Items()[0]["alpha"].(*types.AttributeValueMemberS).Value)
+			assert.Equal(t, "added since read", srv.state.ResultSet().Items()[0]["delta"].(*types.AttributeValueMemberS).Value)
+			assert.Equal(t, "another new value", srv.state.ResultSet().Items()[2]["alpha"].(*types.AttributeValueMemberS).Value)
+		})
+	}
+
 	t.Run("do nothing if in read-only mode", func(t *testing.T) {
 		srv := newService(t, serviceConfig{tableName: "alpha-table", isReadOnly: true})
 
@@ -432,6 +598,87 @@ func TestTableWriteController_PutItems(t *testing.T) {
 	})
 }
 
+func TestTableWriteController_ImportItems(t *testing.T) {
+	t.Run("should add imported items as new and dirty", func(t *testing.T) {
+		scenarios := []struct {
+			format controllers.ExportFormat
+			data   string
+		}{
+			{
+				format: controllers.ExportFormatCSV,
+				data:   "pk,sk,alpha\nnew1,n1,first\nnew2,n2,second\n",
+			},
+			{
+				format: controllers.ExportFormatJSONLines,
+				data:   `{"pk":"new1","sk":"n1","alpha":"first"}` + "\n" + `{"pk":"new2","sk":"n2","alpha":"second"}` + "\n",
+			},
+			{
+				format: controllers.ExportFormatDynamoJSON,
+				data: `{"pk":{"S":"new1"},"sk":{"S":"n1"},"alpha":{"S":"first"}}` + "\n" +
+					`{"pk":{"S":"new2"},"sk":{"S":"n2"},"alpha":{"S":"second"}}` + "\n",
+			},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(fmt.Sprintf("format %v", scenario.format), func(t *testing.T) {
+				srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+				tempFile := tempFile(t)
+				assert.NoError(t, os.WriteFile(tempFile, []byte(scenario.data), 0644))
+
+				invokeCommand(t, srv.readController.Init())
+				invokeCommand(t, srv.writeController.ImportItems(tempFile, controllers.ImportOptions{Format: scenario.format}))
+
+				rs := srv.state.ResultSet()
+				assert.Len(t, rs.Items(), 5)
+
+				for i, expected := range []string{"first", "second"} {
+					alpha, _ := rs.Items()[3+i].AttributeValueAsString("alpha")
+					assert.Equal(t, expected, alpha)
+					assert.True(t, rs.IsNew(3+i))
+					assert.True(t, rs.IsDirty(3+i))
+				}
+			})
+		}
+	})
+
+	t.Run("should return error if item is missing keys", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		tempFile := tempFile(t)
+		assert.NoError(t, os.WriteFile(tempFile, []byte(`{"pk":"new1","alpha":"first"}`), 0644))
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandExpectingError(t, srv.writeController.ImportItems(tempFile, controllers.ImportOptions{Format: controllers.ExportFormatJSONLines}))
+
+		assert.Len(t, srv.state.ResultSet().Items(), 3)
+	})
+
+	t.Run("should return error if key attribute types do not match the table", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		tempFile := tempFile(t)
+		assert.NoError(t, os.WriteFile(tempFile, []byte(`{"pk":"new1","sk":123,"alpha":"first"}`), 0644))
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandExpectingError(t, srv.writeController.ImportItems(tempFile, controllers.ImportOptions{Format: controllers.ExportFormatJSONLines}))
+
+		assert.Len(t, srv.state.ResultSet().Items(), 3)
+	})
+
+	t.Run("should not import items when in read-only mode", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table", isReadOnly: true})
+
+		tempFile := tempFile(t)
+		assert.NoError(t, os.WriteFile(tempFile, []byte("pk,sk\nnew1,n1\n"), 0644))
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandExpectingError(t, srv.writeController.ImportItems(tempFile, controllers.ImportOptions{}))
+
+		assert.Len(t, srv.state.ResultSet().Items(), 3)
+	})
+}
+
 func TestTableWriteController_TouchItem(t *testing.T) {
 	t.Run("should put the selected item if unmodified", func(t *testing.T) {
 		srv := newService(t, serviceConfig{tableName: "alpha-table"})
@@ -452,6 +699,26 @@ func TestTableWriteController_TouchItem(t *testing.T) {
 		assert.False(t, srv.state.ResultSet().IsDirty(0))
 	})
 
+	t.Run("should not put the selected item if modified since read in conditional put mode", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table", putMode: models.PutModeConditional})
+
+		invokeCommand(t, srv.readController.Init())
+
+		// Add an attribute to the item in the table after it has been read
+		modifiedItem := srv.state.ResultSet().Items()[0].Clone()
+		modifiedItem["delta"] = &types.AttributeValueMemberS{Value: "added since read"}
+		assert.NoError(t, srv.provider.PutItem(context.Background(), "alpha-table", modifiedItem))
+
+		prompt, isPrompt := srv.writeController.TouchItem(0).(events.PromptForInputMsg)
+		assert.True(t, isPrompt)
+		invokeCommandExpectingError(t, prompt.OnDone("y"))
+		assert.True(t, srv.state.ResultSet().IsConflict(0))
+
+		// Rescan the table and verify the item was not overwritten
+		invokeCommand(t, srv.readController.Rescan())
+		assert.Equal(t, "added since read", srv.state.ResultSet().Items()[0]["delta"].(*types.AttributeValueMemberS).Value)
+	})
+
 	t.Run("should not put the selected item if modified", func(t *testing.T) {
 		srv := newService(t, serviceConfig{tableName: "alpha-table"})
 
@@ -547,7 +814,7 @@ func TestTableWriteController_DeleteMarked(t *testing.T) {
 		invokeCommand(t, srv.writeController.ToggleMark(2))
 
 		// Delete it
-		invokeCommandWithPrompt(t, srv.writeController.DeleteMarked(), "y")
+		invokeCommandWithDiffConfirmation(t, srv.writeController.DeleteMarked(), true)
 
 		// Rescan and confirm marked items are deleted
 		invokeCommand(t, srv.readController.Init())
@@ -589,12 +856,173 @@ type services struct {
 	exportController   *controllers.ExportController
 	scriptController   *controllers.ScriptController
 	commandController  *commandctrl.CommandController
+	provider           *dynamo.Provider
+}
+
+func TestTableWriteController_CopyItemsTo(t *testing.T) {
+	t.Run("should copy marked items to another table with remapped keys", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommand(t, srv.writeController.ToggleMark(0))
+		invokeCommand(t, srv.writeController.ToggleMark(2))
+
+		invokeCommandWithDiffConfirmation(t, srv.writeController.CopyItemsTo(0, "bravo-table", `sk="copy-" + sk, origin="alpha"`), true)
+
+		invokeCommand(t, srv.readController.ScanTable("bravo-table"))
+		items := srv.state.ResultSet().Items()
+		assert.Len(t, items, 5)
+
+		copiedItems := sliceutils.Filter(items, func(item models.Item) bool {
+			origin, _ := item.AttributeValueAsString("origin")
+			return origin == "alpha"
+		})
+		assert.Len(t, copiedItems, 2)
+
+		sk, _ := copiedItems[0].AttributeValueAsString("sk")
+		assert.Equal(t, "copy-111", sk)
+		sk, _ = copiedItems[1].AttributeValueAsString("sk")
+		assert.Equal(t, "copy-131", sk)
+	})
+
+	t.Run("should copy selected item if no items are marked", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithDiffConfirmation(t, srv.writeController.CopyItemsTo(1, "bravo-table", `pk="copied"`), true)
+
+		invokeCommand(t, srv.readController.ScanTable("bravo-table"))
+		assert.Len(t, srv.state.ResultSet().Items(), 4)
+	})
+
+	t.Run("should not copy items if cancelled", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithDiffConfirmation(t, srv.writeController.CopyItemsTo(1, "bravo-table", `pk="copied"`), false)
+
+		invokeCommand(t, srv.readController.ScanTable("bravo-table"))
+		assert.Len(t, srv.state.ResultSet().Items(), 3)
+	})
+
+	t.Run("should return error if remapped item is missing keys of the target table", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandExpectingError(t, srv.writeController.CopyItemsTo(0, "bravo-table", `sk=missing`))
+		invokeCommandExpectingError(t, srv.writeController.CopyItemsTo(0, "bravo-table", `sk`))
+	})
+
+	t.Run("should not copy items if in read-only mode", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table", isReadOnly: true})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandExpectingError(t, srv.writeController.CopyItemsTo(0, "bravo-table", ""))
+	})
+}
+
+func TestTableWriteController_BulkDelete(t *testing.T) {
+	t.Run("should delete items matching the query after confirmation", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithPrompt(t, srv.writeController.BulkDelete(`pk="abc"`), "y")
+
+		invokeCommand(t, srv.readController.Rescan())
+		items := srv.state.ResultSet().Items()
+		assert.Len(t, items, 1)
+		assert.Equal(t, "bbb", items[0]["pk"].(*types.AttributeValueMemberS).Value)
+	})
+
+	t.Run("should not delete items if not confirmed", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithPrompt(t, srv.writeController.BulkDelete(`pk="abc"`), "n")
+
+		invokeCommand(t, srv.readController.Rescan())
+		assert.Len(t, srv.state.ResultSet().Items(), 3)
+	})
+
+	t.Run("should not prompt if no items match", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		msg := invokeCommand(t, srv.writeController.BulkDelete(`pk="zzz"`))
+		assert.Equal(t, events.StatusMsg("no items match"), msg)
+	})
+
+	t.Run("should not delete items if in read-only mode", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table", isReadOnly: true})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandExpectingError(t, srv.writeController.BulkDelete(`pk="abc"`))
+	})
+
+	t.Run("should return error if the query has a limit or selects attributes", func(t *testing.T) {
+		for _, query := range []string{`pk="abc" using limit(1)`, `pk="abc" using select(alpha)`} {
+			t.Run(query, func(t *testing.T) {
+				srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+				invokeCommand(t, srv.readController.Init())
+				invokeCommandExpectingError(t, srv.writeController.BulkDelete(query))
+
+				invokeCommand(t, srv.readController.Rescan())
+				assert.Len(t, srv.state.ResultSet().Items(), 3)
+			})
+		}
+	})
+}
+
+func TestTableWriteController_BulkUpdate(t *testing.T) {
+	t.Run("should update items matching the query after confirmation", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithPrompt(t, srv.writeController.BulkUpdate(`beta > 0`, `beta = beta + 1, gamma = "updated"`), "y")
+
+		invokeCommand(t, srv.readController.Rescan())
+		items := srv.state.ResultSet().Items()
+		assert.Len(t, items, 3)
+		assert.Nil(t, items[0]["beta"])
+		assert.Equal(t, "1232", items[1]["beta"].(*types.AttributeValueMemberN).Value)
+		assert.Equal(t, "updated", items[1]["gamma"].(*types.AttributeValueMemberS).Value)
+		assert.Equal(t, "2469", items[2]["beta"].(*types.AttributeValueMemberN).Value)
+		assert.Equal(t, "updated", items[2]["gamma"].(*types.AttributeValueMemberS).Value)
+	})
+
+	t.Run("should return error if setting a key attribute", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandWithPromptsExpectingError(t, srv.writeController.BulkUpdate(`pk="abc"`, `sk = "new"`), "y")
+	})
+
+	t.Run("should not update items if in read-only mode", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table", isReadOnly: true})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandExpectingError(t, srv.writeController.BulkUpdate(`pk="abc"`, `beta = 1`))
+	})
+
+	t.Run("should return error if the query has a limit", func(t *testing.T) {
+		srv := newService(t, serviceConfig{tableName: "alpha-table"})
+
+		invokeCommand(t, srv.readController.Init())
+		invokeCommandExpectingError(t, srv.writeController.BulkUpdate(`beta > 0 using limit(1)`, `gamma = "updated"`))
+
+		invokeCommand(t, srv.readController.Rescan())
+		for _, item := range srv.state.ResultSet().Items() {
+			assert.NotEqual(t, &types.AttributeValueMemberS{Value: "updated"}, item["gamma"])
+		}
+	})
 }
 
 type serviceConfig struct {
 	tableName    string
 	isReadOnly   bool
 	defaultLimit int
+	putMode      models.PutMode
 	scriptFS     fs.FS
 }
 
@@ -634,7 +1062,7 @@ func newService(t *testing.T, cfg serviceConfig) *services {
 	settingsController := controllers.NewSettingsController(settingStore, eventBus)
 	columnsController := controllers.NewColumnsController(readController, eventBus)
 	exportController := controllers.NewExportController(state, service, jobsController, columnsController, pasteboardprovider.NilProvider{})
-	scriptController := controllers.NewScriptController(scriptService, readController, jobsController, settingsController, eventBus)
+	scriptController := controllers.NewScriptController(scriptService, readController, writeController, columnsController, jobsController, settingsController, eventBus)
 
 	commandController := commandctrl.NewCommandController(inputHistoryService)
 	commandController.AddCommandLookupExtension(scriptController)
@@ -649,6 +1077,11 @@ func newService(t *testing.T, cfg serviceConfig) *services {
 			t.Errorf("cannot set default limit: %v", err)
 		}
 	}
+	if cfg.putMode != "" {
+		if err := settingStore.SetPutMode(cfg.putMode); err != nil {
+			t.Errorf("cannot set put mode: %v", err)
+		}
+	}
 
 	msgSender := &msgSender{}
 	scriptController.Init()
@@ -669,6 +1102,7 @@ func newService(t *testing.T, cfg serviceConfig) *services {
 		scriptController:   scriptController,
 		commandController:  commandController,
 		msgSender:          msgSender,
+		provider:           provider,
 	}
 }
 
@@ -1,6 +1,12 @@
 package controllers
 
-import "github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
+)
 
 func applyToMarkedItems(rs *models.ResultSet, selectedIndex int, applyFn func(idx int, item models.Item) error) error {
 	if markedItems := rs.MarkedItems(); len(markedItems) > 0 {
@@ -14,3 +20,17 @@ func applyToMarkedItems(rs *models.ResultSet, selectedIndex int, applyFn func(id
 
 	return applyFn(selectedIndex, rs.Items()[selectedIndex])
 }
+
+// describeItemKey returns the key attributes of the item in the form "pk=value, sk=value"
+func describeItemKey(tableInfo *models.TableInfo, item models.Item) string {
+	var sb strings.Builder
+
+	pk, sk := item.PKSK(tableInfo)
+	pkStr, _ := attrutils.AttributeToString(pk)
+	fmt.Fprintf(&sb, "%v=%v", tableInfo.Keys.PartitionKey, pkStr)
+	if tableInfo.Keys.SortKey != "" {
+		skStr, _ := attrutils.AttributeToString(sk)
+		fmt.Fprintf(&sb, ", %v=%v", tableInfo.Keys.SortKey, skStr)
+	}
+	return sb.String()
+}
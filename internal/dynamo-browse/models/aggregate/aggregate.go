@@ -0,0 +1,247 @@
+package aggregate
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/queryexpr"
+	"github.com/pkg/errors"
+)
+
+// Func is an aggregate function
+type Func string
+
+const (
+	Count Func = "count"
+	Sum   Func = "sum"
+	Min   Func = "min"
+	Max   Func = "max"
+	Avg   Func = "avg"
+)
+
+// Aggregation is an aggregate function applied to the items of a query or scan, optionally grouped by the
+// value of an expression.
+type Aggregation struct {
+	Func Func
+
+	// Value is the expression to aggregate.  It is not used for count.
+	Value *queryexpr.QueryExpr
+
+	// GroupBy, if set, is the expression which groups the items.  Items with the same value are aggregated
+	// together.
+	GroupBy *queryexpr.QueryExpr
+}
+
+// New returns a new aggregation.  The value expression is required for all functions except count, and the
+// group by expression is optional.
+func New(fn string, value string, groupBy string) (Aggregation, error) {
+	agg := Aggregation{Func: Func(strings.ToLower(fn))}
+
+	switch agg.Func {
+	case Count:
+		if value != "" {
+			return Aggregation{}, errors.New("count does not take a value expression")
+		}
+	case Sum, Min, Max, Avg:
+		if value == "" {
+			return Aggregation{}, errors.Errorf("%v requires a value expression", agg.Func)
+		}
+
+		var err error
+		agg.Value, err = queryexpr.Parse(value)
+		if err != nil {
+			return Aggregation{}, err
+		}
+	default:
+		return Aggregation{}, errors.Errorf("unrecognised aggregate function: %v", fn)
+	}
+
+	if groupBy != "" {
+		var err error
+		agg.GroupBy, err = queryexpr.Parse(groupBy)
+		if err != nil {
+			return Aggregation{}, err
+		}
+	}
+
+	return agg, nil
+}
+
+// NeedsItems returns true if the aggregation needs to read the items themselves.  Ungrouped counts only need
+// the number of items, which can be returned by DynamoDB directly.
+func (a Aggregation) NeedsItems() bool {
+	return a.Func != Count || a.GroupBy != nil
+}
+
+// NewAccumulator returns a new, empty accumulator for the aggregation.
+func (a Aggregation) NewAccumulator() *Accumulator {
+	return &Accumulator{agg: a, groups: make(map[uint64][]*group)}
+}
+
+// Accumulator aggregates items as they are read, without retaining them.
+type Accumulator struct {
+	agg        Aggregation
+	groups     map[uint64][]*group
+	groupCount int
+}
+
+type group struct {
+	key   types.AttributeValue
+	count int64
+	n     int64
+	value *big.Rat
+}
+
+// Add adds the items to the aggregation.
+func (acc *Accumulator) Add(items ...models.Item) error {
+	for _, item := range items {
+		grp := acc.groupOf(item)
+		grp.count++
+		if acc.agg.Value == nil {
+			continue
+		}
+
+		// Values which cannot be evaluated, or are not numbers, do not take part in the aggregation
+		val, err := acc.agg.Value.EvalItem(item)
+		if err != nil {
+			continue
+		}
+		numVal, isNum := val.(*types.AttributeValueMemberN)
+		if !isNum {
+			continue
+		}
+		num, err := attrutils.ParseNumber(numVal.Value)
+		if err != nil {
+			return err
+		}
+
+		grp.n++
+		if grp.value == nil {
+			grp.value = num
+			continue
+		}
+
+		switch acc.agg.Func {
+		case Sum, Avg:
+			grp.value.Add(grp.value, num)
+		case Min:
+			if num.Cmp(grp.value) < 0 {
+				grp.value = num
+			}
+		case Max:
+			if num.Cmp(grp.value) > 0 {
+				grp.value = num
+			}
+		}
+	}
+	return nil
+}
+
+// AddCount adds the count of items which were not read, such as those returned by a query which only
+// counts the matching items.  This can only be used for ungrouped aggregations.
+func (acc *Accumulator) AddCount(n int64) {
+	grp := acc.groupOf(nil)
+	grp.count += n
+}
+
+func (acc *Accumulator) groupOf(item models.Item) *group {
+	var key types.AttributeValue
+	if acc.agg.GroupBy != nil && item != nil {
+		// Items whose group cannot be evaluated are treated as having no group
+		if groupKey, err := acc.agg.GroupBy.EvalItem(item); err == nil {
+			key = groupKey
+		}
+	}
+
+	var hashCode uint64
+	if key != nil {
+		hashCode = attrutils.HashCode(key)
+	}
+	for _, grp := range acc.groups[hashCode] {
+		if (grp.key == nil && key == nil) || (grp.key != nil && key != nil && attrutils.Equals(grp.key, key)) {
+			return grp
+		}
+	}
+
+	grp := &group{key: key}
+	acc.groups[hashCode] = append(acc.groups[hashCode], grp)
+	acc.groupCount++
+	return grp
+}
+
+// ResultSet returns the aggregation as a result set, with one item per group.  The result set is read from a
+// table which does not exist, so that modified items cannot be written back to the source table.
+func (acc *Accumulator) ResultSet(tableInfo *models.TableInfo) *models.ResultSet {
+	var (
+		valueCol = string(acc.agg.Func)
+		groupCol string
+		keys     = models.KeyAttribute{PartitionKey: valueCol}
+	)
+	if acc.agg.GroupBy != nil {
+		groupCol = acc.agg.GroupBy.String()
+		keys = models.KeyAttribute{PartitionKey: groupCol, SortKey: valueCol}
+	}
+
+	if acc.groupCount == 0 && acc.agg.GroupBy == nil {
+		acc.AddCount(0)
+	}
+
+	// Items without a group are listed last
+	var (
+		items       = make([]models.Item, 0, acc.groupCount)
+		noGroupItem models.Item
+	)
+	for _, grps := range acc.groups {
+		for _, grp := range grps {
+			item := models.Item{}
+			if groupCol != "" && grp.key != nil {
+				item[groupCol] = grp.key
+			}
+			if val := acc.valueOf(grp); val != nil {
+				item[valueCol] = val
+			}
+
+			if grp.key == nil {
+				noGroupItem = item
+			} else {
+				items = append(items, item)
+			}
+		}
+	}
+
+	tableInfo = &models.TableInfo{Name: "aggregate of " + tableInfo.Name, Keys: keys}
+	models.Sort(items, models.PKSKSortFilter(tableInfo))
+	if noGroupItem != nil {
+		items = append(items, noGroupItem)
+	}
+
+	resultSet := &models.ResultSet{
+		TableInfo: tableInfo,
+		Created:   time.Now(),
+	}
+	resultSet.SetItems(items)
+	resultSet.RefreshColumns()
+	return resultSet
+}
+
+func (acc *Accumulator) valueOf(grp *group) types.AttributeValue {
+	switch acc.agg.Func {
+	case Count:
+		return &types.AttributeValueMemberN{Value: big.NewInt(grp.count).String()}
+	case Avg:
+		if grp.n == 0 {
+			return nil
+		}
+		avg := new(big.Rat).Quo(grp.value, new(big.Rat).SetInt64(grp.n))
+		return &types.AttributeValueMemberN{Value: attrutils.FormatNumber(avg)}
+	}
+
+	if grp.value == nil {
+		return nil
+	}
+	return &types.AttributeValueMemberN{Value: attrutils.FormatNumber(grp.value)}
+}
@@ -0,0 +1,144 @@
+package aggregate_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/aggregate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccumulator(t *testing.T) {
+	items := []models.Item{
+		{"status": &types.AttributeValueMemberS{Value: "open"}, "amount": &types.AttributeValueMemberN{Value: "10"}},
+		{"status": &types.AttributeValueMemberS{Value: "open"}, "amount": &types.AttributeValueMemberN{Value: "2.5"}},
+		{"status": &types.AttributeValueMemberS{Value: "closed"}, "amount": &types.AttributeValueMemberN{Value: "4"}},
+		{"status": &types.AttributeValueMemberS{Value: "closed"}, "amount": &types.AttributeValueMemberS{Value: "n/a"}},
+		{"amount": &types.AttributeValueMemberN{Value: "1"}},
+	}
+
+	t.Run("should aggregate all items when not grouped", func(t *testing.T) {
+		scenarios := []struct {
+			fn       string
+			value    string
+			expected string
+		}{
+			{fn: "count", expected: "5"},
+			{fn: "sum", value: "amount", expected: "17.5"},
+			{fn: "min", value: "amount", expected: "1"},
+			{fn: "max", value: "amount", expected: "10"},
+			{fn: "avg", value: "amount", expected: "4.375"},
+			{fn: "SUM", value: "amount * 2", expected: "35"},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.fn+" "+scenario.value, func(t *testing.T) {
+				agg, err := aggregate.New(scenario.fn, scenario.value, "")
+				assert.NoError(t, err)
+
+				acc := agg.NewAccumulator()
+				assert.NoError(t, acc.Add(items...))
+
+				rs := acc.ResultSet(&models.TableInfo{Name: "test-table"})
+				assert.Equal(t, "aggregate of test-table", rs.TableInfo.Name)
+				assert.Len(t, rs.Items(), 1)
+
+				val, _ := rs.Items()[0].AttributeValueAsString(rs.TableInfo.Keys.PartitionKey)
+				assert.Equal(t, scenario.expected, val)
+			})
+		}
+	})
+
+	t.Run("should aggregate items by group", func(t *testing.T) {
+		agg, err := aggregate.New("sum", "amount", "status")
+		assert.NoError(t, err)
+
+		acc := agg.NewAccumulator()
+		assert.NoError(t, acc.Add(items[:2]...))
+		assert.NoError(t, acc.Add(items[2:]...))
+
+		rs := acc.ResultSet(&models.TableInfo{Name: "test-table"})
+		assert.Equal(t, models.KeyAttribute{PartitionKey: "status", SortKey: "sum"}, rs.TableInfo.Keys)
+		assert.Equal(t, []models.Item{
+			{"status": &types.AttributeValueMemberS{Value: "closed"}, "sum": &types.AttributeValueMemberN{Value: "4"}},
+			{"status": &types.AttributeValueMemberS{Value: "open"}, "sum": &types.AttributeValueMemberN{Value: "12.5"}},
+			{"sum": &types.AttributeValueMemberN{Value: "1"}},
+		}, rs.Items())
+	})
+
+	t.Run("should aggregate numbers without losing precision", func(t *testing.T) {
+		preciseItems := []models.Item{
+			{"amount": &types.AttributeValueMemberN{Value: "0.1"}},
+			{"amount": &types.AttributeValueMemberN{Value: "0.2"}},
+			{"amount": &types.AttributeValueMemberN{Value: "12345678901234567890123"}},
+		}
+
+		scenarios := []struct {
+			fn       string
+			expected string
+		}{
+			{fn: "sum", expected: "12345678901234567890123.3"},
+			{fn: "min", expected: "0.1"},
+			{fn: "max", expected: "12345678901234567890123"},
+			{fn: "avg", expected: "4115226300411522630041.1"},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.fn, func(t *testing.T) {
+				agg, err := aggregate.New(scenario.fn, "amount", "")
+				assert.NoError(t, err)
+
+				acc := agg.NewAccumulator()
+				assert.NoError(t, acc.Add(preciseItems...))
+
+				rs := acc.ResultSet(&models.TableInfo{Name: "test-table"})
+				val, _ := rs.Items()[0].AttributeValueAsString(rs.TableInfo.Keys.PartitionKey)
+				assert.Equal(t, scenario.expected, val)
+			})
+		}
+	})
+
+	t.Run("should add counts returned without items", func(t *testing.T) {
+		agg, err := aggregate.New("count", "", "")
+		assert.NoError(t, err)
+		assert.False(t, agg.NeedsItems())
+
+		acc := agg.NewAccumulator()
+		acc.AddCount(100)
+		acc.AddCount(23)
+
+		rs := acc.ResultSet(&models.TableInfo{Name: "test-table"})
+		assert.Equal(t, []models.Item{{"count": &types.AttributeValueMemberN{Value: "123"}}}, rs.Items())
+	})
+
+	t.Run("should return a count of zero if there are no items", func(t *testing.T) {
+		agg, _ := aggregate.New("count", "", "")
+
+		rs := agg.NewAccumulator().ResultSet(&models.TableInfo{Name: "test-table"})
+		assert.Equal(t, []models.Item{{"count": &types.AttributeValueMemberN{Value: "0"}}}, rs.Items())
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should return error if aggregation is invalid", func(t *testing.T) {
+		scenarios := []struct {
+			fn      string
+			value   string
+			groupBy string
+		}{
+			{fn: "median", value: "amount"},
+			{fn: "count", value: "amount"},
+			{fn: "sum"},
+			{fn: "sum", value: "amount +"},
+			{fn: "count", groupBy: "status ="},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.fn, func(t *testing.T) {
+				_, err := aggregate.New(scenario.fn, scenario.value, scenario.groupBy)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
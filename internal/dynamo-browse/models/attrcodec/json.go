@@ -0,0 +1,302 @@
+package attrcodec
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/pkg/errors"
+)
+
+// ToDynamoJSON converts an attribute value to the typed DynamoDB-JSON representation, i.e. {"S": "value"}.
+// The result can be passed to json.Marshal.
+func ToDynamoJSON(val types.AttributeValue) (any, error) {
+	switch v := val.(type) {
+	case *types.AttributeValueMemberS:
+		return map[string]any{"S": v.Value}, nil
+	case *types.AttributeValueMemberN:
+		return map[string]any{"N": v.Value}, nil
+	case *types.AttributeValueMemberBOOL:
+		return map[string]any{"BOOL": v.Value}, nil
+	case *types.AttributeValueMemberNULL:
+		return map[string]any{"NULL": v.Value}, nil
+	case *types.AttributeValueMemberB:
+		return map[string]any{"B": v.Value}, nil
+	case *types.AttributeValueMemberSS:
+		return map[string]any{"SS": v.Value}, nil
+	case *types.AttributeValueMemberNS:
+		return map[string]any{"NS": v.Value}, nil
+	case *types.AttributeValueMemberBS:
+		return map[string]any{"BS": v.Value}, nil
+	case *types.AttributeValueMemberL:
+		items := make([]any, len(v.Value))
+		for i, nv := range v.Value {
+			jv, err := ToDynamoJSON(nv)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = jv
+		}
+		return map[string]any{"L": items}, nil
+	case *types.AttributeValueMemberM:
+		m, err := MapToDynamoJSON(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"M": m}, nil
+	}
+	return nil, errors.New("unhandled type")
+}
+
+// MapToDynamoJSON converts a map of attribute values, such as an item, to the DynamoDB-JSON representation.
+func MapToDynamoJSON(ms map[string]types.AttributeValue) (map[string]any, error) {
+	m := make(map[string]any, len(ms))
+	for k, kv := range ms {
+		jv, err := ToDynamoJSON(kv)
+		if err != nil {
+			return nil, errors.Wrapf(err, "attribute '%v'", k)
+		}
+		m[k] = jv
+	}
+	return m, nil
+}
+
+// ToPlainJSON converts an attribute value to a plain JSON value.  Type information that cannot be represented
+// in JSON is lost: sets become arrays, and binary values become base64 encoded strings.
+func ToPlainJSON(val types.AttributeValue) (any, error) {
+	switch v := val.(type) {
+	case *types.AttributeValueMemberS:
+		return v.Value, nil
+	case *types.AttributeValueMemberN:
+		return json.Number(v.Value), nil
+	case *types.AttributeValueMemberBOOL:
+		return v.Value, nil
+	case *types.AttributeValueMemberNULL:
+		return nil, nil
+	case *types.AttributeValueMemberB:
+		return base64.StdEncoding.EncodeToString(v.Value), nil
+	case *types.AttributeValueMemberSS:
+		return v.Value, nil
+	case *types.AttributeValueMemberNS:
+		nums := make([]json.Number, len(v.Value))
+		for i, n := range v.Value {
+			nums[i] = json.Number(n)
+		}
+		return nums, nil
+	case *types.AttributeValueMemberBS:
+		strs := make([]string, len(v.Value))
+		for i, b := range v.Value {
+			strs[i] = base64.StdEncoding.EncodeToString(b)
+		}
+		return strs, nil
+	case *types.AttributeValueMemberL:
+		items := make([]any, len(v.Value))
+		for i, nv := range v.Value {
+			jv, err := ToPlainJSON(nv)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = jv
+		}
+		return items, nil
+	case *types.AttributeValueMemberM:
+		return MapToPlainJSON(v.Value)
+	}
+	return nil, errors.New("unhandled type")
+}
+
+// MapToPlainJSON converts a map of attribute values, such as an item, to a plain JSON object.
+func MapToPlainJSON(ms map[string]types.AttributeValue) (map[string]any, error) {
+	m := make(map[string]any, len(ms))
+	for k, kv := range ms {
+		jv, err := ToPlainJSON(kv)
+		if err != nil {
+			return nil, errors.Wrapf(err, "attribute '%v'", k)
+		}
+		m[k] = jv
+	}
+	return m, nil
+}
+
+// FromDynamoJSON converts a typed DynamoDB-JSON value, as decoded by json.Unmarshal, back to an attribute value.
+func FromDynamoJSON(val any) (types.AttributeValue, error) {
+	m, isMap := val.(map[string]any)
+	if !isMap || len(m) != 1 {
+		return nil, errors.New("expected an object with a single type key")
+	}
+
+	for typeName, v := range m {
+		switch typeName {
+		case "S":
+			s, err := jsonString(v)
+			if err != nil {
+				return nil, err
+			}
+			return &types.AttributeValueMemberS{Value: s}, nil
+		case "N":
+			s, err := jsonString(v)
+			if err != nil {
+				return nil, err
+			}
+			return &types.AttributeValueMemberN{Value: s}, nil
+		case "BOOL":
+			b, isBool := v.(bool)
+			if !isBool {
+				return nil, errors.New("expected BOOL to be a boolean")
+			}
+			return &types.AttributeValueMemberBOOL{Value: b}, nil
+		case "NULL":
+			b, isBool := v.(bool)
+			if !isBool {
+				return nil, errors.New("expected NULL to be a boolean")
+			}
+			return &types.AttributeValueMemberNULL{Value: b}, nil
+		case "B":
+			bts, err := jsonBytes(v)
+			if err != nil {
+				return nil, err
+			}
+			return &types.AttributeValueMemberB{Value: bts}, nil
+		case "SS", "NS", "BS":
+			arr, isArr := v.([]any)
+			if !isArr {
+				return nil, errors.Errorf("expected %v to be an array", typeName)
+			}
+			return setFromDynamoJSON(typeName, arr)
+		case "L":
+			arr, isArr := v.([]any)
+			if !isArr {
+				return nil, errors.New("expected L to be an array")
+			}
+			items := make([]types.AttributeValue, len(arr))
+			for i, av := range arr {
+				item, err := FromDynamoJSON(av)
+				if err != nil {
+					return nil, errors.Wrapf(err, "list index %d", i)
+				}
+				items[i] = item
+			}
+			return &types.AttributeValueMemberL{Value: items}, nil
+		case "M":
+			mv, isMap := v.(map[string]any)
+			if !isMap {
+				return nil, errors.New("expected M to be an object")
+			}
+			items, err := MapFromDynamoJSON(mv)
+			if err != nil {
+				return nil, err
+			}
+			return &types.AttributeValueMemberM{Value: items}, nil
+		default:
+			return nil, errors.Errorf("unrecognised type: %v", typeName)
+		}
+	}
+	return nil, errors.New("unreachable")
+}
+
+// MapFromDynamoJSON converts a DynamoDB-JSON object back to a map of attribute values.
+func MapFromDynamoJSON(m map[string]any) (map[string]types.AttributeValue, error) {
+	ms := make(map[string]types.AttributeValue, len(m))
+	for k, v := range m {
+		av, err := FromDynamoJSON(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "attribute '%v'", k)
+		}
+		ms[k] = av
+	}
+	return ms, nil
+}
+
+// FromPlainJSON converts a plain JSON value, as decoded by a json.Decoder with UseNumber set, to an
+// attribute value.  Arrays are always converted to lists.
+func FromPlainJSON(val any) (types.AttributeValue, error) {
+	switch v := val.(type) {
+	case nil:
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	case string:
+		return &types.AttributeValueMemberS{Value: v}, nil
+	case json.Number:
+		return &types.AttributeValueMemberN{Value: v.String()}, nil
+	case float64:
+		return &types.AttributeValueMemberN{Value: strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case bool:
+		return &types.AttributeValueMemberBOOL{Value: v}, nil
+	case []any:
+		items := make([]types.AttributeValue, len(v))
+		for i, av := range v {
+			item, err := FromPlainJSON(av)
+			if err != nil {
+				return nil, errors.Wrapf(err, "list index %d", i)
+			}
+			items[i] = item
+		}
+		return &types.AttributeValueMemberL{Value: items}, nil
+	case map[string]any:
+		items, err := MapFromPlainJSON(v)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberM{Value: items}, nil
+	}
+	return nil, errors.Errorf("unhandled JSON type: %T", val)
+}
+
+// MapFromPlainJSON converts a plain JSON object to a map of attribute values.
+func MapFromPlainJSON(m map[string]any) (map[string]types.AttributeValue, error) {
+	ms := make(map[string]types.AttributeValue, len(m))
+	for k, v := range m {
+		av, err := FromPlainJSON(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "attribute '%v'", k)
+		}
+		ms[k] = av
+	}
+	return ms, nil
+}
+
+func setFromDynamoJSON(typeName string, arr []any) (types.AttributeValue, error) {
+	switch typeName {
+	case "SS", "NS":
+		strs := make([]string, len(arr))
+		for i, v := range arr {
+			s, err := jsonString(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "set index %d", i)
+			}
+			strs[i] = s
+		}
+		if typeName == "SS" {
+			return &types.AttributeValueMemberSS{Value: strs}, nil
+		}
+		return &types.AttributeValueMemberNS{Value: strs}, nil
+	default:
+		bts := make([][]byte, len(arr))
+		for i, v := range arr {
+			b, err := jsonBytes(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "set index %d", i)
+			}
+			bts[i] = b
+		}
+		return &types.AttributeValueMemberBS{Value: bts}, nil
+	}
+}
+
+func jsonString(v any) (string, error) {
+	switch s := v.(type) {
+	case string:
+		return s, nil
+	case json.Number:
+		return s.String(), nil
+	}
+	return "", errors.Errorf("expected string but was %T", v)
+}
+
+func jsonBytes(v any) ([]byte, error) {
+	s, isStr := v.(string)
+	if !isStr {
+		return nil, errors.Errorf("expected base64 string but was %T", v)
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
@@ -0,0 +1,102 @@
+package attrcodec_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrcodec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoJSON(t *testing.T) {
+	t.Run("should round-trip item through DynamoDB-JSON", func(t *testing.T) {
+		item := map[string]types.AttributeValue{
+			"str":   &types.AttributeValueMemberS{Value: "Hello world"},
+			"num":   &types.AttributeValueMemberN{Value: "123456789012345678901234567890"},
+			"bool":  &types.AttributeValueMemberBOOL{Value: true},
+			"null":  &types.AttributeValueMemberNULL{Value: true},
+			"bytes": &types.AttributeValueMemberB{Value: []byte{1, 2, 3, 4, 5}},
+			"ss":    &types.AttributeValueMemberSS{Value: []string{"more", "string", "stuff"}},
+			"ns":    &types.AttributeValueMemberNS{Value: []string{"123", "4.56"}},
+			"bs":    &types.AttributeValueMemberBS{Value: [][]byte{{1, 2, 3}, {4, 5, 6}}},
+			"list": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "apple"},
+				&types.AttributeValueMemberN{Value: "12.34"},
+			}},
+			"map": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"lat":  &types.AttributeValueMemberN{Value: "12.34"},
+				"name": &types.AttributeValueMemberS{Value: "place"},
+			}},
+		}
+
+		jm, err := attrcodec.MapToDynamoJSON(item)
+		assert.NoError(t, err)
+
+		bts, err := json.Marshal(jm)
+		assert.NoError(t, err)
+
+		var decoded map[string]any
+		assert.NoError(t, json.Unmarshal(bts, &decoded))
+
+		otherItem, err := attrcodec.MapFromDynamoJSON(decoded)
+		assert.NoError(t, err)
+		assert.Equal(t, item, otherItem)
+	})
+
+	t.Run("should encode typed values", func(t *testing.T) {
+		jv, err := attrcodec.ToDynamoJSON(&types.AttributeValueMemberN{Value: "123"})
+		assert.NoError(t, err)
+
+		bts, err := json.Marshal(jv)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"N":"123"}`, string(bts))
+	})
+}
+
+func TestPlainJSON(t *testing.T) {
+	t.Run("should encode item as plain JSON", func(t *testing.T) {
+		item := map[string]types.AttributeValue{
+			"str":  &types.AttributeValueMemberS{Value: "Hello"},
+			"num":  &types.AttributeValueMemberN{Value: "12.50"},
+			"bool": &types.AttributeValueMemberBOOL{Value: false},
+			"null": &types.AttributeValueMemberNULL{Value: true},
+			"ns":   &types.AttributeValueMemberNS{Value: []string{"1", "2"}},
+			"map": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"inner": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+					&types.AttributeValueMemberS{Value: "a"},
+				}},
+			}},
+		}
+
+		jm, err := attrcodec.MapToPlainJSON(item)
+		assert.NoError(t, err)
+
+		bts, err := json.Marshal(jm)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"str":"Hello","num":12.50,"bool":false,"null":null,"ns":[1,2],"map":{"inner":["a"]}}`, string(bts))
+	})
+
+	t.Run("should decode plain JSON", func(t *testing.T) {
+		jm := map[string]any{
+			"str":  "Hello",
+			"num":  json.Number("12.50"),
+			"bool": true,
+			"null": nil,
+			"list": []any{"a", json.Number("1")},
+		}
+
+		item, err := attrcodec.MapFromPlainJSON(jm)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]types.AttributeValue{
+			"str":  &types.AttributeValueMemberS{Value: "Hello"},
+			"num":  &types.AttributeValueMemberN{Value: "12.50"},
+			"bool": &types.AttributeValueMemberBOOL{Value: true},
+			"null": &types.AttributeValueMemberNULL{Value: true},
+			"list": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "a"},
+				&types.AttributeValueMemberN{Value: "1"},
+			}},
+		}, item)
+	})
+}
@@ -1,8 +1,6 @@
 package attrutils
 
 import (
-	"math/big"
-
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
@@ -14,12 +12,12 @@ func CompareScalarAttributes(x, y types.AttributeValue) (int, bool) {
 		}
 	case *types.AttributeValueMemberN:
 		if yVal, ok := y.(*types.AttributeValueMemberN); ok {
-			xNumVal, _, err := big.ParseFloat(xVal.Value, 10, 63, big.ToNearestEven)
+			xNumVal, err := ParseNumber(xVal.Value)
 			if err != nil {
 				return 0, false
 			}
 
-			yNumVal, _, err := big.ParseFloat(yVal.Value, 10, 63, big.ToNearestEven)
+			yNumVal, err := ParseNumber(yVal.Value)
 			if err != nil {
 				return 0, false
 			}
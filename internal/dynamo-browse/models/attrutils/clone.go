@@ -0,0 +1,45 @@
+package attrutils
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"golang.org/x/exp/slices"
+)
+
+// Clone returns a deep copy of the attribute value.
+func Clone(x types.AttributeValue) types.AttributeValue {
+	switch xVal := x.(type) {
+	case *types.AttributeValueMemberS:
+		return &types.AttributeValueMemberS{Value: xVal.Value}
+	case *types.AttributeValueMemberN:
+		return &types.AttributeValueMemberN{Value: xVal.Value}
+	case *types.AttributeValueMemberBOOL:
+		return &types.AttributeValueMemberBOOL{Value: xVal.Value}
+	case *types.AttributeValueMemberNULL:
+		return &types.AttributeValueMemberNULL{Value: xVal.Value}
+	case *types.AttributeValueMemberB:
+		return &types.AttributeValueMemberB{Value: slices.Clone(xVal.Value)}
+	case *types.AttributeValueMemberSS:
+		return &types.AttributeValueMemberSS{Value: slices.Clone(xVal.Value)}
+	case *types.AttributeValueMemberNS:
+		return &types.AttributeValueMemberNS{Value: slices.Clone(xVal.Value)}
+	case *types.AttributeValueMemberBS:
+		newVals := make([][]byte, len(xVal.Value))
+		for i, b := range xVal.Value {
+			newVals[i] = slices.Clone(b)
+		}
+		return &types.AttributeValueMemberBS{Value: newVals}
+	case *types.AttributeValueMemberL:
+		newVals := make([]types.AttributeValue, len(xVal.Value))
+		for i, v := range xVal.Value {
+			newVals[i] = Clone(v)
+		}
+		return &types.AttributeValueMemberL{Value: newVals}
+	case *types.AttributeValueMemberM:
+		newVals := make(map[string]types.AttributeValue, len(xVal.Value))
+		for k, v := range xVal.Value {
+			newVals[k] = Clone(v)
+		}
+		return &types.AttributeValueMemberM{Value: newVals}
+	}
+	return x
+}
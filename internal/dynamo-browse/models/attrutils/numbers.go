@@ -0,0 +1,89 @@
+package attrutils
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MaxNumberDigits is the number of significant digits of a DynamoDB number.
+const MaxNumberDigits = 38
+
+var bigTen = big.NewInt(10)
+
+// ParseNumber parses a DynamoDB number exactly.
+func ParseNumber(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || strings.ContainsAny(s, "/") {
+		return nil, errors.Errorf("invalid number: '%v'", s)
+	}
+	return r, nil
+}
+
+// FormatNumber formats a number as a decimal string accepted by DynamoDB.  Numbers are written in full without an
+// exponent, and are rounded to the maximum number of significant digits of a DynamoDB number.
+func FormatNumber(r *big.Rat) string {
+	if r.Sign() == 0 {
+		return "0"
+	}
+
+	num := new(big.Int).Abs(r.Num())
+	den := r.Denom()
+
+	// Find exp such that 10^exp <= |r| < 10^(exp+1), starting from an estimate based on the number of digits
+	exp := len(num.String()) - len(den.String())
+	if cmpScaled(num, den, exp) < 0 {
+		exp--
+	}
+
+	// Round to the maximum number of significant digits, which gives digits * 10^(exp - (MaxNumberDigits-1))
+	scale := MaxNumberDigits - 1 - exp
+	scaledNum, scaledDen := new(big.Int).Set(num), new(big.Int).Set(den)
+	if scale > 0 {
+		scaledNum.Mul(scaledNum, pow10(scale))
+	} else if scale < 0 {
+		scaledDen.Mul(scaledDen, pow10(-scale))
+	}
+
+	digits, rem := new(big.Int).QuoRem(scaledNum, scaledDen, new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(scaledDen) >= 0 {
+		digits.Add(digits, big.NewInt(1))
+	}
+
+	// Remove trailing zeros
+	digitStr := strings.TrimRight(digits.String(), "0")
+	scale -= len(digits.String()) - len(digitStr)
+
+	var sb strings.Builder
+	if r.Sign() < 0 {
+		sb.WriteString("-")
+	}
+
+	switch {
+	case scale <= 0:
+		sb.WriteString(digitStr)
+		sb.WriteString(strings.Repeat("0", -scale))
+	case scale < len(digitStr):
+		sb.WriteString(digitStr[:len(digitStr)-scale])
+		sb.WriteString(".")
+		sb.WriteString(digitStr[len(digitStr)-scale:])
+	default:
+		sb.WriteString("0.")
+		sb.WriteString(strings.Repeat("0", scale-len(digitStr)))
+		sb.WriteString(digitStr)
+	}
+	return sb.String()
+}
+
+// cmpScaled compares num/den with 10^exp
+func cmpScaled(num, den *big.Int, exp int) int {
+	if exp >= 0 {
+		return num.Cmp(new(big.Int).Mul(den, pow10(exp)))
+	}
+	return new(big.Int).Mul(num, pow10(-exp)).Cmp(den)
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,33 @@
+package models
+
+import "strings"
+
+// Connection identifies the AWS profile, region and endpoint used to connect to DynamoDB.
+type Connection struct {
+	Profile  string
+	Region   string
+	Endpoint string
+}
+
+func (c Connection) IsZero() bool {
+	return c == Connection{}
+}
+
+func (c Connection) String() string {
+	var sb strings.Builder
+
+	if c.Profile != "" {
+		sb.WriteString(c.Profile)
+	} else {
+		sb.WriteString("default")
+	}
+	if c.Region != "" {
+		sb.WriteString("/")
+		sb.WriteString(c.Region)
+	}
+	if c.Endpoint != "" {
+		sb.WriteString(" @ ")
+		sb.WriteString(c.Endpoint)
+	}
+	return sb.String()
+}
@@ -0,0 +1,26 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnection_String(t *testing.T) {
+	scenarios := []struct {
+		conn     models.Connection
+		expected string
+	}{
+		{conn: models.Connection{}, expected: "default"},
+		{conn: models.Connection{Profile: "prod"}, expected: "prod"},
+		{conn: models.Connection{Profile: "prod", Region: "ap-southeast-2"}, expected: "prod/ap-southeast-2"},
+		{conn: models.Connection{Region: "us-east-1", Endpoint: "http://localhost:8000"}, expected: "default/us-east-1 @ http://localhost:8000"},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.expected, func(t *testing.T) {
+			assert.Equal(t, scenario.expected, scenario.conn.String())
+		})
+	}
+}
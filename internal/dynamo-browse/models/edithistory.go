@@ -0,0 +1,192 @@
+package models
+
+const maxEditHistory = 100
+
+// ItemChange records the state of a single item before and after an edit.  Before is nil if the edit added
+// the item to the result set.
+type ItemChange struct {
+	Index  int
+	Before Item
+	After  Item
+}
+
+// ItemEdit is a set of changes made to the items of a result set by a single operation.
+type ItemEdit struct {
+	Changes []ItemChange
+}
+
+// AddsItems returns true if the edit added items to the result set.
+func (ie ItemEdit) AddsItems() bool {
+	for _, c := range ie.Changes {
+		if c.Before == nil {
+			return true
+		}
+	}
+	return false
+}
+
+type editHistory struct {
+	undoStack []ItemEdit
+	redoStack []ItemEdit
+}
+
+func (eh *editHistory) push(edit ItemEdit) {
+	eh.undoStack = append(eh.undoStack, edit)
+	if len(eh.undoStack) > maxEditHistory {
+		eh.undoStack = eh.undoStack[len(eh.undoStack)-maxEditHistory:]
+	}
+	eh.redoStack = nil
+}
+
+// remapIndices updates the item indices of all the recorded changes.
+func (eh *editHistory) remapIndices(fn func(idx int) int) {
+	for _, stack := range [][]ItemEdit{eh.undoStack, eh.redoStack} {
+		for _, edit := range stack {
+			for i := range edit.Changes {
+				edit.Changes[i].Index = fn(edit.Changes[i].Index)
+			}
+		}
+	}
+}
+
+// EditRecorder records the changes made to the items of a result set so that they can be undone.
+type EditRecorder struct {
+	rs      *ResultSet
+	changes []ItemChange
+	seen    map[int]bool
+}
+
+// RecordEdit starts recording a new edit.  Call Modify before changing an existing item, or use Add to add a new
+// item.  The edit is recorded once Commit is called.
+func (rs *ResultSet) RecordEdit() *EditRecorder {
+	return &EditRecorder{rs: rs, seen: make(map[int]bool)}
+}
+
+// Modify records the current state of the item at idx.  This must be called before the item is changed.
+func (er *EditRecorder) Modify(idx int) {
+	if er.seen[idx] {
+		return
+	}
+	er.seen[idx] = true
+	er.changes = append(er.changes, ItemChange{Index: idx, Before: er.rs.items[idx].Clone()})
+}
+
+// Add adds a new item to the result set and records it as part of the edit.
+func (er *EditRecorder) Add(item Item, attrs ItemAttribute) {
+	er.rs.AddNewItem(item, attrs)
+
+	idx := len(er.rs.items) - 1
+	er.seen[idx] = true
+	er.changes = append(er.changes, ItemChange{Index: idx})
+}
+
+// Commit records the state of the changed items and pushes the edit onto the undo stack.  The dirty flag of
+// each modified item is updated based on whether it differs from the original item.
+func (er *EditRecorder) Commit() {
+	if len(er.changes) == 0 {
+		return
+	}
+
+	for i, c := range er.changes {
+		er.changes[i].After = er.rs.items[c.Index].Clone()
+		er.rs.refreshDirty(c.Index)
+	}
+	er.rs.history.push(ItemEdit{Changes: er.changes})
+	er.changes = nil
+}
+
+// ClearEditHistory discards all the edits which can be undone or redone.  This is used once items have been
+// written to the table, as undoing edits made before then would no longer reflect what is in the table.
+func (rs *ResultSet) ClearEditHistory() {
+	rs.history = editHistory{}
+}
+
+// CanUndo returns true if there are edits that can be undone.
+func (rs *ResultSet) CanUndo() bool {
+	return len(rs.history.undoStack) > 0
+}
+
+// CanRedo returns true if there are edits that can be redone.
+func (rs *ResultSet) CanRedo() bool {
+	return len(rs.history.redoStack) > 0
+}
+
+// Undo reverts the most recent edit.  Returns the reverted edit and true, or false if there was nothing to undo.
+func (rs *ResultSet) Undo() (ItemEdit, bool) {
+	if !rs.CanUndo() {
+		return ItemEdit{}, false
+	}
+
+	edit := rs.history.undoStack[len(rs.history.undoStack)-1]
+	rs.history.undoStack = rs.history.undoStack[:len(rs.history.undoStack)-1]
+
+	for i := len(edit.Changes) - 1; i >= 0; i-- {
+		c := edit.Changes[i]
+		if c.Before == nil {
+			rs.removeItem(c.Index)
+		} else {
+			rs.items[c.Index].replaceWith(c.Before)
+			rs.refreshDirty(c.Index)
+		}
+	}
+
+	rs.history.redoStack = append(rs.history.redoStack, edit)
+	return edit, true
+}
+
+// Redo reapplies the most recently undone edit.  Returns the edit and true, or false if there was nothing to redo.
+func (rs *ResultSet) Redo() (ItemEdit, bool) {
+	if !rs.CanRedo() {
+		return ItemEdit{}, false
+	}
+
+	edit := rs.history.redoStack[len(rs.history.redoStack)-1]
+	rs.history.redoStack = rs.history.redoStack[:len(rs.history.redoStack)-1]
+
+	for _, c := range edit.Changes {
+		if c.Before == nil {
+			rs.insertItem(c.Index, c.After.Clone(), ItemAttribute{New: true, Dirty: true})
+		} else {
+			rs.items[c.Index].replaceWith(c.After)
+			rs.refreshDirty(c.Index)
+		}
+	}
+
+	rs.history.undoStack = append(rs.history.undoStack, edit)
+	return edit, true
+}
+
+// refreshDirty sets the dirty flag of the item at idx based on whether it differs from the original item.
+func (rs *ResultSet) refreshDirty(idx int) {
+	if rs.attributes[idx].New {
+		rs.attributes[idx].Dirty = true
+		return
+	}
+	rs.attributes[idx].Dirty = !rs.items[idx].Equals(rs.originalItems[idx])
+}
+
+func (rs *ResultSet) removeItem(idx int) {
+	rs.items = append(rs.items[:idx], rs.items[idx+1:]...)
+	rs.attributes = append(rs.attributes[:idx], rs.attributes[idx+1:]...)
+	rs.originalItems = append(rs.originalItems[:idx], rs.originalItems[idx+1:]...)
+
+	rs.history.remapIndices(func(i int) int {
+		if i > idx {
+			return i - 1
+		}
+		return i
+	})
+}
+
+func (rs *ResultSet) insertItem(idx int, item Item, attrs ItemAttribute) {
+	rs.history.remapIndices(func(i int) int {
+		if i >= idx {
+			return i + 1
+		}
+		return i
+	})
+
+	rs.items = append(rs.items[:idx], append([]Item{item}, rs.items[idx:]...)...)
+	rs.attributes = append(rs.attributes[:idx], append([]ItemAttribute{attrs}, rs.attributes[idx:]...)...)
+	rs.originalItems = append(rs.originalItems[:idx], append([]Item{nil}, rs.originalItems[idx:]...)...)
+}
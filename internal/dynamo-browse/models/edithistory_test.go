@@ -0,0 +1,126 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultSet_UndoRedo(t *testing.T) {
+	t.Run("should undo and redo modifications", func(t *testing.T) {
+		rs := newEditTestResultSet()
+
+		edit := rs.RecordEdit()
+		edit.Modify(0)
+		rs.Items()[0]["val"] = &types.AttributeValueMemberS{Value: "changed"}
+		edit.Commit()
+
+		assert.True(t, rs.IsDirty(0))
+		assert.True(t, rs.CanUndo())
+
+		_, ok := rs.Undo()
+		assert.True(t, ok)
+		assert.Equal(t, "a", stringValue(rs.Items()[0], "val"))
+		assert.False(t, rs.IsDirty(0))
+		assert.False(t, rs.CanUndo())
+		assert.True(t, rs.CanRedo())
+
+		_, ok = rs.Redo()
+		assert.True(t, ok)
+		assert.Equal(t, "changed", stringValue(rs.Items()[0], "val"))
+		assert.True(t, rs.IsDirty(0))
+	})
+
+	t.Run("should not mark item as dirty if changed back to original value", func(t *testing.T) {
+		rs := newEditTestResultSet()
+
+		edit := rs.RecordEdit()
+		edit.Modify(1)
+		rs.Items()[1]["val"] = &types.AttributeValueMemberS{Value: "b"}
+		edit.Commit()
+
+		assert.False(t, rs.IsDirty(1))
+	})
+
+	t.Run("should remove added items on undo", func(t *testing.T) {
+		rs := newEditTestResultSet()
+
+		edit := rs.RecordEdit()
+		edit.Add(models.Item{"pk": &types.AttributeValueMemberS{Value: "0"}}, models.ItemAttribute{New: true, Dirty: true})
+		edit.Commit()
+		assert.Len(t, rs.Items(), 4)
+
+		undone, ok := rs.Undo()
+		assert.True(t, ok)
+		assert.True(t, undone.AddsItems())
+		assert.Len(t, rs.Items(), 3)
+
+		_, ok = rs.Redo()
+		assert.True(t, ok)
+		assert.Len(t, rs.Items(), 4)
+		assert.True(t, rs.IsNew(3))
+		assert.Nil(t, rs.OriginalItem(3))
+	})
+
+	t.Run("should track item indices when sorted", func(t *testing.T) {
+		rs := newEditTestResultSet()
+
+		edit := rs.RecordEdit()
+		edit.Modify(0)
+		rs.Items()[0]["val"] = &types.AttributeValueMemberS{Value: "changed"}
+		edit.Commit()
+
+		rs.Sort(models.SortCriteria{Fields: []models.SortField{
+			{Field: models.SimpleFieldValueEvaluator("pk"), Asc: false},
+		}})
+		assert.Equal(t, "1", stringValue(rs.Items()[2], "pk"))
+		assert.True(t, rs.IsDirty(2))
+
+		rs.Undo()
+		assert.Equal(t, "a", stringValue(rs.Items()[2], "val"))
+		assert.False(t, rs.IsDirty(2))
+	})
+
+	t.Run("should do nothing if there is nothing to undo or redo", func(t *testing.T) {
+		rs := newEditTestResultSet()
+
+		_, ok := rs.Undo()
+		assert.False(t, ok)
+
+		_, ok = rs.Redo()
+		assert.False(t, ok)
+	})
+
+	t.Run("should not undo edits once the history is cleared", func(t *testing.T) {
+		rs := newEditTestResultSet()
+
+		edit := rs.RecordEdit()
+		edit.Add(models.Item{"pk": &types.AttributeValueMemberS{Value: "0"}}, models.ItemAttribute{New: true, Dirty: true})
+		edit.Commit()
+
+		rs.ClearEditHistory()
+		assert.False(t, rs.CanUndo())
+		assert.False(t, rs.CanRedo())
+
+		_, ok := rs.Undo()
+		assert.False(t, ok)
+		assert.Len(t, rs.Items(), 4)
+	})
+}
+
+func newEditTestResultSet() *models.ResultSet {
+	rs := &models.ResultSet{TableInfo: &models.TableInfo{Keys: models.KeyAttribute{PartitionKey: "pk"}}}
+	rs.SetItems([]models.Item{
+		{"pk": &types.AttributeValueMemberS{Value: "1"}, "val": &types.AttributeValueMemberS{Value: "a"}},
+		{"pk": &types.AttributeValueMemberS{Value: "2"}, "val": &types.AttributeValueMemberS{Value: "b"}},
+		{"pk": &types.AttributeValueMemberS{Value: "3"}, "val": &types.AttributeValueMemberS{Value: "c"}},
+	})
+	return rs
+}
+
+func stringValue(item models.Item, key string) string {
+	s, _ := item.AttributeValueAsString(key)
+	return s
+}
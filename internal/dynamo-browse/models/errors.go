@@ -1,11 +1,16 @@
 package models
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 )
 
 var ErrReadOnly = errors.New("in read-only mode")
 
+// ErrProjectedItems indicates that items cannot be put as only some of their attributes were read
+var ErrProjectedItems = errors.New("items were read with select() and cannot be put, as the attributes not selected would be removed")
+
 type PartialResultsError struct {
 	err error
 }
@@ -21,3 +26,34 @@ func (pr PartialResultsError) Error() string {
 func (pr PartialResultsError) Unwrap() error {
 	return pr.err
 }
+
+// FilterError indicates that a filter could not be evaluated against some of the items.  These items are hidden.
+type FilterError struct {
+	Count int
+	err   error
+}
+
+func NewFilterError(count int, err error) FilterError {
+	return FilterError{Count: count, err: err}
+}
+
+func (fe FilterError) Error() string {
+	return fmt.Sprintf("filter cannot be evaluated against %d items: %v", fe.Count, fe.err)
+}
+
+func (fe FilterError) Unwrap() error {
+	return fe.err
+}
+
+// PutConflictError indicates that some items were not put as they were modified in the table since they were read
+type PutConflictError struct {
+	Count int
+}
+
+func NewPutConflictError(count int) PutConflictError {
+	return PutConflictError{Count: count}
+}
+
+func (pc PutConflictError) Error() string {
+	return fmt.Sprintf("%d items were modified since they were read", pc.Count)
+}
@@ -8,6 +8,21 @@ type FieldValueEvaluator interface {
 	EvaluateForItem(item Item) types.AttributeValue
 }
 
+// SyncFieldValueEvaluator is implemented by evaluators which evaluate values in the background, and so may return
+// nil from EvaluateForItem until the value is available.  EvaluateForItemSync waits for the value instead.
+type SyncFieldValueEvaluator interface {
+	EvaluateForItemSync(item Item) types.AttributeValue
+}
+
+// EvaluateForItemSync returns the value of the evaluator for the item, waiting for the value if it is evaluated
+// in the background.  This is to be used when the value is needed right away, such as when sorting or exporting.
+func EvaluateForItemSync(evaluator FieldValueEvaluator, item Item) types.AttributeValue {
+	if se, isSync := evaluator.(SyncFieldValueEvaluator); isSync {
+		return se.EvaluateForItemSync(item)
+	}
+	return evaluator.EvaluateForItem(item)
+}
+
 type SimpleFieldValueEvaluator string
 
 func (sfve SimpleFieldValueEvaluator) EvaluateForItem(item Item) types.AttributeValue {
@@ -1,6 +1,8 @@
 package evaluators
 
 import (
+	"reflect"
+
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/queryexpr"
 )
@@ -21,5 +23,9 @@ func Equals(x, y models.FieldValueEvaluator) bool {
 		}
 	}
 
+	// Other evaluators are only equal to themselves
+	if reflect.TypeOf(x).Comparable() {
+		return x == y
+	}
 	return false
 }
@@ -0,0 +1,70 @@
+package itemrender
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
+)
+
+type DiffType int
+
+const (
+	DiffAdded DiffType = iota
+	DiffRemoved
+	DiffChanged
+)
+
+// DiffItem is a single attribute that differs between two items.
+type DiffItem struct {
+	Key    string
+	Type   DiffType
+	Before Renderer // nil if the attribute was added
+	After  Renderer // nil if the attribute was removed
+
+	// SubItems holds the differences between the attributes of a map, if the attribute was a map
+	// both before and after.
+	SubItems []DiffItem
+}
+
+// Diff returns the attributes which differ between before and after, sorted by key.  Attributes that are maps in
+// both are compared attribute by attribute.  Either before or after can be nil, in which case every attribute is
+// either added or removed.
+func Diff(before, after map[string]types.AttributeValue) []DiffItem {
+	diffs := make([]DiffItem, 0)
+
+	for k, bv := range before {
+		av, hasAfter := after[k]
+		if !hasAfter {
+			diffs = append(diffs, DiffItem{Key: k, Type: DiffRemoved, Before: ToRenderer(bv)})
+			continue
+		} else if attrutils.Equals(bv, av) {
+			continue
+		}
+
+		bm, beforeIsMap := bv.(*types.AttributeValueMemberM)
+		am, afterIsMap := av.(*types.AttributeValueMemberM)
+		if beforeIsMap && afterIsMap {
+			diffs = append(diffs, DiffItem{
+				Key:      k,
+				Type:     DiffChanged,
+				Before:   ToRenderer(bv),
+				After:    ToRenderer(av),
+				SubItems: Diff(bm.Value, am.Value),
+			})
+		} else {
+			diffs = append(diffs, DiffItem{Key: k, Type: DiffChanged, Before: ToRenderer(bv), After: ToRenderer(av)})
+		}
+	}
+
+	for k, av := range after {
+		if _, hasBefore := before[k]; !hasBefore {
+			diffs = append(diffs, DiffItem{Key: k, Type: DiffAdded, After: ToRenderer(av)})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
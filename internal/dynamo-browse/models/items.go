@@ -12,16 +12,38 @@ type ItemIndex struct {
 
 type Item map[string]types.AttributeValue
 
-// Clone creates a clone of the current item
+// Clone creates a deep clone of the current item
 func (i Item) Clone() Item {
 	newItem := Item{}
+	for k, v := range i {
+		newItem[k] = attrutils.Clone(v)
+	}
+	return newItem
+}
 
-	// TODO: should be a deep clone? YES!!
+// Equals returns true if both items have the same attributes with the same values
+func (i Item) Equals(other Item) bool {
+	if len(i) != len(other) {
+		return false
+	}
 	for k, v := range i {
-		newItem[k] = v
+		ov, hasOther := other[k]
+		if !hasOther || !attrutils.Equals(v, ov) {
+			return false
+		}
 	}
+	return true
+}
 
-	return newItem
+// replaceWith replaces the attributes of this item with a copy of the attributes of the other item.  This is done
+// in place, so that anything holding a reference to this item will see the new attributes.
+func (i Item) replaceWith(other Item) {
+	for k := range i {
+		delete(i, k)
+	}
+	for k, v := range other {
+		i[k] = attrutils.Clone(v)
+	}
 }
 
 func (i Item) KeyValue(info *TableInfo) map[string]types.AttributeValue {
@@ -13,13 +13,19 @@ type ResultSet struct {
 	Created           time.Time
 	ExclusiveStartKey map[string]types.AttributeValue
 
+	// Projected is true if only some of the attributes of the items were read.  Putting these items would remove
+	// the attributes which were not read.
+	Projected bool
+
 	// Result information
 	LastEvaluatedKey map[string]types.AttributeValue
 	items            []Item
 	attributes       []ItemAttribute
+	originalItems    []Item
 
 	columns      []string
 	sortCriteria SortCriteria
+	history      editHistory
 }
 
 type Queryable interface {
@@ -30,10 +36,11 @@ type Queryable interface {
 }
 
 type ItemAttribute struct {
-	Marked bool
-	Hidden bool
-	Dirty  bool
-	New    bool
+	Marked   bool
+	Hidden   bool
+	Dirty    bool
+	New      bool
+	Conflict bool
 }
 
 func (rs *ResultSet) NoResults() bool {
@@ -47,6 +54,10 @@ func (rs *ResultSet) Items() []Item {
 func (rs *ResultSet) SetItems(items []Item) {
 	rs.items = items
 	rs.attributes = make([]ItemAttribute, len(items))
+	rs.originalItems = make([]Item, len(items))
+	for i, item := range items {
+		rs.originalItems[i] = item.Clone()
+	}
 }
 
 func (rs *ResultSet) SortCriteria() SortCriteria {
@@ -56,6 +67,19 @@ func (rs *ResultSet) SortCriteria() SortCriteria {
 func (rs *ResultSet) AddNewItem(item Item, attrs ItemAttribute) {
 	rs.items = append(rs.items, item)
 	rs.attributes = append(rs.attributes, attrs)
+	rs.originalItems = append(rs.originalItems, nil)
+}
+
+// OriginalItem returns the item at idx as it was when it was last read from, or written to, the table.
+// Returns nil if the item is new.
+func (rs *ResultSet) OriginalItem(idx int) Item {
+	return rs.originalItems[idx]
+}
+
+// ResetOriginalItem sets the original item at idx to a copy of the current item.  This is used once an item
+// has been written to the table.
+func (rs *ResultSet) ResetOriginalItem(idx int) {
+	rs.originalItems[idx] = rs.items[idx].Clone()
 }
 
 func (rs *ResultSet) SetMark(idx int, marked bool) {
@@ -74,6 +98,10 @@ func (rs *ResultSet) SetNew(idx int, isNew bool) {
 	rs.attributes[idx].New = isNew
 }
 
+func (rs *ResultSet) SetConflict(idx int, conflict bool) {
+	rs.attributes[idx].Conflict = conflict
+}
+
 func (rs *ResultSet) Marked(idx int) bool {
 	return rs.attributes[idx].Marked
 }
@@ -90,6 +118,10 @@ func (rs *ResultSet) IsNew(idx int) bool {
 	return rs.attributes[idx].New
 }
 
+func (rs *ResultSet) IsConflict(idx int) bool {
+	return rs.attributes[idx].Conflict
+}
+
 func (rs *ResultSet) MarkedItems() []ItemIndex {
 	items := make([]ItemIndex, 0)
 	for i, itemAttr := range rs.attributes {
@@ -149,5 +181,29 @@ func (rs *ResultSet) HasNextPage() bool {
 
 func (rs *ResultSet) Sort(criteria SortCriteria) {
 	rs.sortCriteria = criteria
-	Sort(rs.items, criteria)
+
+	// Track where each item came from so the indices of the edit history can be updated
+	prevIndices := make([]int, len(rs.items))
+	for i := range prevIndices {
+		prevIndices[i] = i
+	}
+
+	si := sortedItems{items: rs.items, criteria: criteria, onSwap: func(i, j int) {
+		if len(rs.attributes) == len(rs.items) {
+			rs.attributes[j], rs.attributes[i] = rs.attributes[i], rs.attributes[j]
+		}
+		if len(rs.originalItems) == len(rs.items) {
+			rs.originalItems[j], rs.originalItems[i] = rs.originalItems[i], rs.originalItems[j]
+		}
+		prevIndices[j], prevIndices[i] = prevIndices[i], prevIndices[j]
+	}}
+	sort.Sort(&si)
+
+	newIndices := make([]int, len(prevIndices))
+	for newIdx, prevIdx := range prevIndices {
+		newIndices[prevIdx] = newIdx
+	}
+	rs.history.remapIndices(func(idx int) int {
+		return newIndices[idx]
+	})
 }
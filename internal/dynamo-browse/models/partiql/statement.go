@@ -0,0 +1,76 @@
+package partiql
+
+import (
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/pkg/errors"
+)
+
+// Prefix is the prefix of a query which is to be run as a PartiQL statement
+const Prefix = "sql:"
+
+var (
+	selectStatement = regexp.MustCompile(`(?i)^select\s`)
+	fromTableName   = regexp.MustCompile(`(?i)\sfrom\s+(?:"([^"]+)"|([a-z_][a-z0-9_]*))`)
+)
+
+// Statement is a PartiQL SELECT statement which can be used in place of a query expression.
+type Statement struct {
+	statement string
+}
+
+// IsStatement returns true if the query is to be run as a PartiQL statement.
+func IsStatement(query string) bool {
+	return strings.HasPrefix(strings.TrimSpace(query), Prefix)
+}
+
+// Parse returns the PartiQL statement of the query, with or without the prefix.  Only SELECT statements are
+// supported.
+func Parse(query string) (*Statement, error) {
+	stmt := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(query), Prefix))
+	if stmt == "" {
+		return nil, errors.New("missing PartiQL statement")
+	} else if !selectStatement.MatchString(stmt) {
+		return nil, errors.Errorf("only SELECT statements can be used as a query: '%v'", stmt)
+	}
+	return &Statement{statement: stmt}, nil
+}
+
+// Statement returns the PartiQL statement, without the prefix.
+func (s *Statement) Statement() string {
+	return s.statement
+}
+
+// TableName returns the name of the table the statement selects from, or "" if it cannot be determined.  The table
+// name can be quoted or unquoted, and any index following the table name is ignored.
+func (s *Statement) TableName() string {
+	m := fromTableName.FindStringSubmatch(s.statement)
+	if m == nil {
+		return ""
+	} else if m[1] != "" {
+		return m[1]
+	}
+	return m[2]
+}
+
+func (s *Statement) String() string {
+	return Prefix + " " + s.statement
+}
+
+func (s *Statement) SerializeToBytes() ([]byte, error) {
+	return []byte(s.statement), nil
+}
+
+func (s *Statement) HashCode() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(Prefix))
+	h.Write([]byte(s.statement))
+	return h.Sum64()
+}
+
+func (s *Statement) Plan(tableInfo *models.TableInfo) (*models.QueryExecutionPlan, error) {
+	return &models.QueryExecutionPlan{Statement: s.statement}, nil
+}
@@ -0,0 +1,73 @@
+package partiql_test
+
+import (
+	"testing"
+
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/partiql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("should parse select statements with or without the prefix", func(t *testing.T) {
+		scenarios := []struct {
+			query             string
+			expectedStatement string
+			expectedTableName string
+		}{
+			{query: `sql: SELECT * FROM "my-table" WHERE pk = 'abc'`, expectedStatement: `SELECT * FROM "my-table" WHERE pk = 'abc'`, expectedTableName: "my-table"},
+			{query: `sql:select pk, sk from "other"`, expectedStatement: `select pk, sk from "other"`, expectedTableName: "other"},
+			{query: `SELECT * FROM "my-table"`, expectedStatement: `SELECT * FROM "my-table"`, expectedTableName: "my-table"},
+			{query: `sql: SELECT * FROM my_table`, expectedStatement: `SELECT * FROM my_table`, expectedTableName: "my_table"},
+			{query: `sql: SELECT * FROM Orders WHERE pk = 'abc'`, expectedStatement: `SELECT * FROM Orders WHERE pk = 'abc'`, expectedTableName: "Orders"},
+			{query: `sql: SELECT * FROM "my-table"."my-index"`, expectedStatement: `SELECT * FROM "my-table"."my-index"`, expectedTableName: "my-table"},
+			{query: `sql: SELECT * FROM my_table.my_index`, expectedStatement: `SELECT * FROM my_table.my_index`, expectedTableName: "my_table"},
+			{query: `sql: SELECT * FROM "my_table`, expectedStatement: `SELECT * FROM "my_table`, expectedTableName: ""},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.query, func(t *testing.T) {
+				stmt, err := partiql.Parse(scenario.query)
+				assert.NoError(t, err)
+
+				assert.Equal(t, scenario.expectedStatement, stmt.Statement())
+				assert.Equal(t, scenario.expectedTableName, stmt.TableName())
+				assert.Equal(t, "sql: "+scenario.expectedStatement, stmt.String())
+			})
+		}
+	})
+
+	t.Run("should return error if statement is not a select statement", func(t *testing.T) {
+		scenarios := []string{
+			`sql:`,
+			`sql: DELETE FROM "my-table" WHERE pk = 'abc'`,
+			`sql: INSERT INTO "my-table" VALUE {'pk': 'abc'}`,
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario, func(t *testing.T) {
+				_, err := partiql.Parse(scenario)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestIsStatement(t *testing.T) {
+	assert.True(t, partiql.IsStatement(`sql: SELECT * FROM "tbl"`))
+	assert.True(t, partiql.IsStatement(`  sql:SELECT * FROM "tbl"`))
+	assert.False(t, partiql.IsStatement(`pk = "sql:"`))
+}
+
+func TestStatement_Plan(t *testing.T) {
+	stmt, err := partiql.Parse(`sql: SELECT * FROM "tbl"`)
+	assert.NoError(t, err)
+
+	plan, err := stmt.Plan(&models.TableInfo{Name: "tbl"})
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "tbl"`, plan.Statement)
+	assert.False(t, plan.CanQuery)
+
+	other, _ := partiql.Parse(`SELECT * FROM "tbl"`)
+	assert.Equal(t, stmt.HashCode(), other.HashCode())
+}
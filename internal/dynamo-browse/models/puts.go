@@ -0,0 +1,36 @@
+package models
+
+import "github.com/pkg/errors"
+
+// PutMode determines how dirty items are written to the table
+type PutMode string
+
+const (
+	// PutModeBatch writes items using batch writes without any conditions
+	PutModeBatch PutMode = "batch"
+
+	// PutModeConditional writes items one at a time, only if the stored item is unchanged since it was read
+	PutModeConditional PutMode = "conditional"
+
+	// PutModeTransaction writes items in transactions, only if the stored items are unchanged since they were read
+	PutModeTransaction PutMode = "transaction"
+)
+
+func ParsePutMode(s string) (PutMode, error) {
+	switch PutMode(s) {
+	case PutModeBatch, PutModeConditional, PutModeTransaction:
+		return PutMode(s), nil
+	}
+	return "", errors.Errorf("unrecognised put mode: %v", s)
+}
+
+// ConditionalPut is an item to put which is conditional on the item in the table matching Original.
+// If Original is nil, the item must not exist in the table.
+//
+// The item in the table is re-read and compared to Original just before the put, and the put itself is
+// conditional on the attributes of Original being unchanged.  An attribute added to the item by another writer
+// between the re-read and the put will not be detected.
+type ConditionalPut struct {
+	Item     Item
+	Original Item
+}
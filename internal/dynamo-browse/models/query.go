@@ -10,10 +10,41 @@ type QueryExecutionPlan struct {
 	CanQuery   bool
 	IndexName  string
 	Expression expression.Expression
+
+	// Statement, if set, is a PartiQL statement which is executed in place of a query or scan
+	Statement string
+
+	// KeyTerms are the terms of the query expression used in the key condition, and FilterTerms are the
+	// terms evaluated as a filter.
+	KeyTerms    []string
+	FilterTerms []string
+
+	// ItemFilterTerms are the terms which cannot be evaluated by DynamoDB.  When set, ItemFilter is to be applied
+	// to the items that are read, keeping only the items it returns true for.
+	ItemFilterTerms []string
+	ItemFilter      func(item Item) (bool, error)
+
+	// ReadOptions are applied to the query or scan.  Limit, if greater than zero, is the maximum number of items
+	// to read, overriding the default limit.
+	ReadOptions ReadOptions
+	Limit       int
+}
+
+// ReadOptions are options which affect how the items of a query or scan are read.
+type ReadOptions struct {
+	// Descending reads the items of a query in descending sort key order
+	Descending bool
+
+	// ConsistentRead performs a strongly consistent read
+	ConsistentRead bool
 }
 
 func (qep QueryExecutionPlan) Describe(dp DescribingPrinter) {
-	if qep.CanQuery {
+	if qep.Statement != "" {
+		dp.Println("  execute as: statement")
+		dp.Printf("  statement: %v", qep.Statement)
+		return
+	} else if qep.CanQuery {
 		dp.Println("  execute as: query")
 	} else {
 		dp.Println("  execute as: scan")
@@ -22,6 +53,33 @@ func (qep QueryExecutionPlan) Describe(dp DescribingPrinter) {
 	if qep.IndexName != "" {
 		dp.Printf("  index: %v", qep.IndexName)
 	}
+	if qep.ReadOptions.Descending {
+		dp.Println("  order: descending")
+	}
+	if qep.ReadOptions.ConsistentRead {
+		dp.Println("  consistent read: true")
+	}
+	if qep.Limit > 0 {
+		dp.Printf("  limit: %v", qep.Limit)
+	}
+	if len(qep.KeyTerms) > 0 {
+		dp.Println("  key terms:")
+		for _, t := range qep.KeyTerms {
+			dp.Printf("    %v", t)
+		}
+	}
+	if len(qep.FilterTerms) > 0 {
+		dp.Println("  filter terms:")
+		for _, t := range qep.FilterTerms {
+			dp.Printf("    %v", t)
+		}
+	}
+	if len(qep.ItemFilterTerms) > 0 {
+		dp.Println("  item filter terms:")
+		for _, t := range qep.ItemFilterTerms {
+			dp.Printf("    %v", t)
+		}
+	}
 	if keyCond := aws.ToString(qep.Expression.KeyCondition()); keyCond != "" {
 		dp.Printf("  key condition: %v", keyCond)
 	}
@@ -31,6 +89,9 @@ func (qep QueryExecutionPlan) Describe(dp DescribingPrinter) {
 	if filter := aws.ToString(qep.Expression.Filter()); filter != "" {
 		dp.Printf("  filter: %v", filter)
 	}
+	if proj := aws.ToString(qep.Expression.Projection()); proj != "" {
+		dp.Printf("  projection: %v", proj)
+	}
 	if names := qep.Expression.Names(); len(names) > 0 {
 		dp.Println("  names:")
 		for k, v := range names {
@@ -0,0 +1,296 @@
+package queryexpr
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+)
+
+func (a *astAddOp) evalToIR(ctx *evalContext, info *models.TableInfo) (irAtom, error) {
+	leftIR, err := a.Ref.evalToIR(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opr := range a.Operands {
+		rightIR, err := opr.Value.evalToIR(ctx, info)
+		if err != nil {
+			return nil, err
+		}
+
+		leftIR, err = evalArithToIR(opr.Op, leftIR, rightIR)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return leftIR, nil
+}
+
+func (a *astAddOp) evalItem(ctx *evalContext, item models.Item) (exprValue, error) {
+	left, err := a.Ref.evalItem(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opr := range a.Operands {
+		right, err := opr.Value.evalItem(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+
+		left, err = evalArith(opr.Op, left, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (a *astAddOp) canModifyItem(ctx *evalContext, item models.Item) bool {
+	if len(a.Operands) > 0 {
+		return false
+	}
+	return a.Ref.canModifyItem(ctx, item)
+}
+
+func (a *astAddOp) setEvalItem(ctx *evalContext, item models.Item, value exprValue) error {
+	if len(a.Operands) > 0 {
+		return PathNotSettableError{}
+	}
+	return a.Ref.setEvalItem(ctx, item, value)
+}
+
+func (a *astAddOp) deleteAttribute(ctx *evalContext, item models.Item) error {
+	if len(a.Operands) > 0 {
+		return PathNotSettableError{}
+	}
+	return a.Ref.deleteAttribute(ctx, item)
+}
+
+func (a *astAddOp) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(a.Ref.String())
+	for _, opr := range a.Operands {
+		sb.WriteString(" " + opr.Op + " ")
+		sb.WriteString(opr.Value.String())
+	}
+	return sb.String()
+}
+
+func (a *astMultOp) evalToIR(ctx *evalContext, info *models.TableInfo) (irAtom, error) {
+	leftIR, err := a.Ref.evalToIR(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opr := range a.Operands {
+		rightIR, err := opr.Value.evalToIR(ctx, info)
+		if err != nil {
+			return nil, err
+		}
+
+		leftIR, err = evalArithToIR(opr.Op, leftIR, rightIR)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return leftIR, nil
+}
+
+func (a *astMultOp) evalItem(ctx *evalContext, item models.Item) (exprValue, error) {
+	left, err := a.Ref.evalItem(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opr := range a.Operands {
+		right, err := opr.Value.evalItem(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+
+		left, err = evalArith(opr.Op, left, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (a *astMultOp) canModifyItem(ctx *evalContext, item models.Item) bool {
+	if len(a.Operands) > 0 {
+		return false
+	}
+	return a.Ref.canModifyItem(ctx, item)
+}
+
+func (a *astMultOp) setEvalItem(ctx *evalContext, item models.Item, value exprValue) error {
+	if len(a.Operands) > 0 {
+		return PathNotSettableError{}
+	}
+	return a.Ref.setEvalItem(ctx, item, value)
+}
+
+func (a *astMultOp) deleteAttribute(ctx *evalContext, item models.Item) error {
+	if len(a.Operands) > 0 {
+		return PathNotSettableError{}
+	}
+	return a.Ref.deleteAttribute(ctx, item)
+}
+
+func (a *astMultOp) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(a.Ref.String())
+	for _, opr := range a.Operands {
+		sb.WriteString(" " + opr.Op + " ")
+		sb.WriteString(opr.Value.String())
+	}
+	return sb.String()
+}
+
+func (a *astUnaryOp) evalToIR(ctx *evalContext, info *models.TableInfo) (irAtom, error) {
+	ir, err := a.Value.evalToIR(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	if a.Op != "-" {
+		return ir, nil
+	}
+	return evalArithToIR("-", irValue{value: int64ExprValue(0)}, ir)
+}
+
+func (a *astUnaryOp) evalItem(ctx *evalContext, item models.Item) (exprValue, error) {
+	val, err := a.Value.evalItem(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	if a.Op != "-" {
+		return val, nil
+	}
+	return evalArith("-", int64ExprValue(0), val)
+}
+
+func (a *astUnaryOp) canModifyItem(ctx *evalContext, item models.Item) bool {
+	if a.Op != "" {
+		return false
+	}
+	return a.Value.canModifyItem(ctx, item)
+}
+
+func (a *astUnaryOp) setEvalItem(ctx *evalContext, item models.Item, value exprValue) error {
+	if a.Op != "" {
+		return PathNotSettableError{}
+	}
+	return a.Value.setEvalItem(ctx, item, value)
+}
+
+func (a *astUnaryOp) deleteAttribute(ctx *evalContext, item models.Item) error {
+	if a.Op != "" {
+		return PathNotSettableError{}
+	}
+	return a.Value.deleteAttribute(ctx, item)
+}
+
+func (a *astUnaryOp) String() string {
+	return a.Op + a.Value.String()
+}
+
+// evalArithToIR returns the IR node of an arithmetic operator.  Operators over literal values are evaluated
+// straight away.  Otherwise, only addition and subtraction are supported, as these are the only operators
+// available in update expressions.
+func evalArithToIR(op string, leftIR, rightIR irAtom) (irAtom, error) {
+	leftVal, isLeftVal := leftIR.(valueIRAtom)
+	rightVal, isRightVal := rightIR.(valueIRAtom)
+	if isLeftVal && isRightVal {
+		res, err := evalArith(op, leftVal.exprValue(), rightVal.exprValue())
+		if err != nil {
+			return nil, err
+		}
+		return irValue{value: res}, nil
+	}
+
+	if op != "+" && op != "-" {
+		return nil, OperatorNotSupportedInExpressionError{Op: op}
+	}
+
+	leftOpr, isLeftOpr := leftIR.(oprIRAtom)
+	rightOpr, isRightOpr := rightIR.(oprIRAtom)
+	if !isLeftOpr || !isRightOpr {
+		return nil, OperatorNotSupportedInExpressionError{Op: op}
+	}
+	return irArith{op: op, left: leftOpr, right: rightOpr}, nil
+}
+
+// evalArith applies an arithmetic operator to two values.  Addition of two strings will concatenate them.
+func evalArith(op string, left, right exprValue) (exprValue, error) {
+	if op == "+" {
+		leftStr, isLeftStr := left.(stringableExprValue)
+		rightStr, isRightStr := right.(stringableExprValue)
+		if isLeftStr && isRightStr {
+			return stringExprValue(leftStr.asString() + rightStr.asString()), nil
+		}
+	}
+
+	leftNum, isLeftNum := left.(numberableExprValue)
+	rightNum, isRightNum := right.(numberableExprValue)
+	if !isLeftNum || !isRightNum {
+		return nil, OperatorNotApplicableError{Op: op, Left: typeNameOf(left), Right: typeNameOf(right)}
+	}
+
+	var res big.Rat
+	switch op {
+	case "+":
+		res.Add(leftNum.asBigRat(), rightNum.asBigRat())
+	case "-":
+		res.Sub(leftNum.asBigRat(), rightNum.asBigRat())
+	case "*":
+		res.Mul(leftNum.asBigRat(), rightNum.asBigRat())
+	case "/":
+		if rightNum.asBigRat().Sign() == 0 {
+			return nil, DivideByZeroError{}
+		}
+		res.Quo(leftNum.asBigRat(), rightNum.asBigRat())
+	default:
+		return nil, OperatorNotApplicableError{Op: op, Left: typeNameOf(left), Right: typeNameOf(right)}
+	}
+
+	if res.IsInt() && res.Num().IsInt64() {
+		return int64ExprValue(res.Num().Int64()), nil
+	}
+	return bigNumExprValue{num: &res}, nil
+}
+
+func typeNameOf(v exprValue) string {
+	if v == nil {
+		return undefinedExprValue{}.typeName()
+	}
+	return v.typeName()
+}
+
+type irArith struct {
+	op          string
+	left, right oprIRAtom
+}
+
+func (i irArith) calcQueryForScan(info *models.TableInfo) (expression.ConditionBuilder, error) {
+	return expression.ConditionBuilder{}, NodeCannotBeConvertedToQueryError{}
+}
+
+func (i irArith) calcSetValue(info *models.TableInfo) (expression.OperandBuilder, error) {
+	for _, opr := range []oprIRAtom{i.left, i.right} {
+		if val, isVal := opr.(valueIRAtom); isVal {
+			if _, isNum := val.exprValue().(numberableExprValue); !isNum {
+				return nil, OperatorNotApplicableError{Op: i.op, Left: "N", Right: typeNameOf(val.exprValue())}
+			}
+		}
+	}
+
+	if i.op == "-" {
+		return expression.Minus(i.left.calcOperand(info), i.right.calcOperand(info)), nil
+	}
+	return expression.Plus(i.left.calcOperand(info), i.right.calcOperand(info)), nil
+}
@@ -1,13 +1,14 @@
 package queryexpr
 
 import (
+	"strings"
+
 	"github.com/alecthomas/participle/v2"
 	"github.com/alecthomas/participle/v2/lexer"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	"github.com/pkg/errors"
-	"strconv"
 )
 
 // Modelled on the expression language here
@@ -15,12 +16,16 @@ import (
 
 type astExpr struct {
 	Root    *astDisjunction `parser:"@@"`
-	Options *astOptions     `parser:"( 'using' @@ )?"`
+	Options []*astOption    `parser:"( 'using' @@ (',' @@)* )?"`
 }
 
-type astOptions struct {
-	Scan  bool   `parser:"@'scan'"`
-	Index string `parser:" | 'index' '(' @String ')'"`
+type astOption struct {
+	Scan       bool         `parser:"@'scan'"`
+	Index      string       `parser:" | 'index' '(' @String ')'"`
+	Desc       bool         `parser:" | @'desc'"`
+	Consistent bool         `parser:" | @'consistent'"`
+	Limit      *int64       `parser:" | 'limit' '(' @Int ')'"`
+	Select     []*astSubRef `parser:" | 'select' '(' @@ (',' @@)* ')'"`
 }
 
 type astDisjunction struct {
@@ -62,11 +67,36 @@ type astEqualityOp struct {
 }
 
 type astIsOp struct {
-	Ref    *astSubRef `parser:"@@ ( 'is' "`
+	Ref    *astAddOp  `parser:"@@ ( 'is' "`
 	HasNot bool       `parser:"@'not'?"`
 	Value  *astSubRef `parser:"@@ )?"`
 }
 
+type astAddOp struct {
+	Ref      *astMultOp       `parser:"@@"`
+	Operands []*astAddOperand `parser:"@@*"`
+}
+
+type astAddOperand struct {
+	Op    string     `parser:"@('+' | '-')"`
+	Value *astMultOp `parser:"@@"`
+}
+
+type astMultOp struct {
+	Ref      *astUnaryOp       `parser:"@@"`
+	Operands []*astMultOperand `parser:"@@*"`
+}
+
+type astMultOperand struct {
+	Op    string      `parser:"@('*' | '/')"`
+	Value *astUnaryOp `parser:"@@"`
+}
+
+type astUnaryOp struct {
+	Op    string     `parser:"@('-' | '+')?"`
+	Value *astSubRef `parser:"@@"`
+}
+
 type astSubRef struct {
 	Ref     *astFunctionCall `parser:"@@"`
 	SubRefs []*astSubRefType `parser:"@@*"`
@@ -100,6 +130,7 @@ type astPlaceholder struct {
 
 type astLiteralValue struct {
 	StringVal      *string `parser:"@String"`
+	NumberVal      *string `parser:"| @Number"`
 	IntVal         *int64  `parser:"| @Int"`
 	TrueBoolValue  bool    `parser:"| @KwdTrue"`
 	FalseBoolValue bool    `parser:"| @KwdFalse"`
@@ -111,8 +142,8 @@ var scanner = lexer.MustSimple([]lexer.SimpleRule{
 	{Name: "Eq", Pattern: `=|[\\^]=|[!]=`},
 	{Name: "Cmp", Pattern: `<[=]?|>[=]?`},
 	{Name: "String", Pattern: `"(\\"|[^"])*"`},
-	{Name: "Int", Pattern: `[-+]?(\d*\.)?\d+`},
-	{Name: "Number", Pattern: `[-+]?(\d*\.)?\d+`},
+	{Name: "Number", Pattern: `\d*\.\d+`},
+	{Name: "Int", Pattern: `\d+`},
 	{Name: "Ident", Pattern: `[a-zA-Z_][a-zA-Z0-9_-]*`},
 	{Name: "PlaceholderIdent", Pattern: `[$:][a-zA-Z0-9_-][a-zA-Z0-9_-]*`},
 	{Name: "Punct", Pattern: `[-[!@#$%^&*()+_={}\|:;"'<,>.?/]|][=]?`},
@@ -133,7 +164,39 @@ func Parse(expr string) (*QueryExpr, error) {
 }
 
 func (a *astExpr) calcQuery(ctx *evalContext, info *models.TableInfo, preferredIndex string) (*models.QueryExecutionPlan, error) {
-	plans, err := a.determinePlausibleExecutionPlans(ctx, info)
+	opts, err := a.queryOptions(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := a.choosePlan(ctx, info, preferredIndex, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(plan.ItemFilterTerms) > 0 {
+		if opts.projection != nil {
+			return nil, errors.Errorf("cannot select attributes when filtering items by: %v", strings.Join(plan.ItemFilterTerms, ", "))
+		}
+		plan.ItemFilter = func(item models.Item) (bool, error) {
+			return a.matchItem(ctx, item)
+		}
+	}
+
+	plan.ReadOptions = opts.readOptions
+	plan.Limit = opts.limit
+	return plan, nil
+}
+
+func (a *astExpr) choosePlan(ctx *evalContext, info *models.TableInfo, preferredIndex string, opts queryOptions) (*models.QueryExecutionPlan, error) {
+	optionsIndex := opts.index
+
+	explicitIndex := preferredIndex
+	if explicitIndex == "" {
+		explicitIndex = optionsIndex
+	}
+
+	plans, err := a.determinePlausibleExecutionPlans(ctx, info, explicitIndex, opts.projection)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +211,7 @@ func (a *astExpr) calcQuery(ctx *evalContext, info *models.TableInfo, preferredI
 		return true
 	})
 
-	if len(queryPlans) == 0 || (a.Options != nil && a.Options.Scan) {
+	if len(queryPlans) == 0 || opts.scan {
 		if preferredIndex != "" {
 			return nil, NoPlausiblePlanWithIndexError{
 				PreferredIndex:  preferredIndex,
@@ -158,11 +221,8 @@ func (a *astExpr) calcQuery(ctx *evalContext, info *models.TableInfo, preferredI
 		return scanPlan, nil
 	}
 
-	if preferredIndex == "" && (a.Options != nil && a.Options.Index != "") {
-		preferredIndex, err = strconv.Unquote(a.Options.Index)
-		if err != nil {
-			return nil, err
-		}
+	if preferredIndex == "" {
+		preferredIndex = optionsIndex
 	}
 
 	if preferredIndex != "" {
@@ -176,73 +236,263 @@ func (a *astExpr) calcQuery(ctx *evalContext, info *models.TableInfo, preferredI
 		}
 	}
 
+	// Prefer the plans which use the most terms in the key condition.  If there's still more than one, prefer
+	// querying the table itself over the indices.
+	mostKeyTerms := 0
+	for _, p := range queryPlans {
+		if len(p.KeyTerms) > mostKeyTerms {
+			mostKeyTerms = len(p.KeyTerms)
+		}
+	}
+	queryPlans = sliceutils.Filter(queryPlans, func(p *models.QueryExecutionPlan) bool { return len(p.KeyTerms) == mostKeyTerms })
+	if len(queryPlans) == 1 {
+		return queryPlans[0], nil
+	} else if tablePlan, hasTablePlan := sliceutils.FindFirst(queryPlans, func(p *models.QueryExecutionPlan) bool {
+		return p.IndexName == ""
+	}); hasTablePlan {
+		return tablePlan, nil
+	}
+
 	return nil, MultiplePlansWithIndexError{
 		PossibleIndices: sliceutils.Map(queryPlans, func(p *models.QueryExecutionPlan) string { return p.IndexName }),
 	}
 }
 
-func (a *astExpr) determinePlausibleExecutionPlans(ctx *evalContext, info *models.TableInfo) ([]*models.QueryExecutionPlan, error) {
+// determinePlausibleExecutionPlans returns the plans that can be used to execute the expression.  Since a local
+// secondary index shares the partition key of the table, a query over an LSI is only plausible if the expression
+// constrains the sort key of the LSI, unless the LSI is the explicitly chosen index.
+//
+// If the expression is a conjunction, only some of the terms need to be usable as the key condition of a query.
+// The remaining terms are included in the query plan as a filter expression, apart from those which cannot be
+// expressed as a DynamoDB condition.  These are left to be evaluated against the items once they're read.
+func (a *astExpr) determinePlausibleExecutionPlans(
+	ctx *evalContext,
+	info *models.TableInfo,
+	explicitIndex string,
+	projection *expression.ProjectionBuilder,
+) ([]*models.QueryExecutionPlan, error) {
 	plans := make([]*models.QueryExecutionPlan, 0)
 
 	type queryTestAttempt struct {
-		index         string
-		keysUnderTest models.KeyAttribute
+		index          string
+		keysUnderTest  models.KeyAttribute
+		requireSortKey bool
 	}
 	queryTestAttempts := append(
 		[]queryTestAttempt{{keysUnderTest: info.Keys}},
 		sliceutils.Map(info.GSIs, func(gsi models.TableGSI) queryTestAttempt {
 			return queryTestAttempt{index: gsi.Name, keysUnderTest: gsi.Keys}
 		})...)
+	queryTestAttempts = append(queryTestAttempts,
+		sliceutils.Map(info.LSIs, func(lsi models.TableLSI) queryTestAttempt {
+			return queryTestAttempt{index: lsi.Name, keysUnderTest: lsi.Keys, requireSortKey: lsi.Name != explicitIndex}
+		})...)
 
-	ir, err := a.evalToIR(ctx, info)
+	terms, err := a.evalToPlanTerms(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	terms, itemTerms, err := splitItemTerms(terms, info)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, attempt := range queryTestAttempts {
-		var qci = queryCalcInfo{keysUnderTest: attempt.keysUnderTest}
-		if canExecuteAsQuery(ir, &qci) {
-			ke, err := ir.(queryableIRAtom).calcQueryForQuery()
+		keyTerms, filterTerms, qci := splitKeyTerms(terms, attempt.keysUnderTest)
+		if qci == nil || (attempt.requireSortKey && !qci.hasSeenSortKey()) {
+			continue
+		}
+
+		ke, err := keyTerms[0].ir.(queryableIRAtom).calcQueryForQuery()
+		if err != nil {
+			return nil, err
+		}
+		if len(keyTerms) > 1 {
+			skKe, err := keyTerms[1].ir.(queryableIRAtom).calcQueryForQuery()
 			if err != nil {
 				return nil, err
 			}
+			ke = expression.KeyAnd(ke, skKe)
+		}
 
-			builder := expression.NewBuilder()
-			builder = builder.WithKeyCondition(ke)
+		builder := expression.NewBuilder()
+		builder = builder.WithKeyCondition(ke)
 
-			expr, err := builder.Build()
+		if len(filterTerms) > 0 {
+			cb, err := calcFilterForTerms(filterTerms, info)
 			if err != nil {
 				return nil, err
 			}
+			builder = builder.WithFilter(cb)
+		}
+		if projection != nil {
+			builder = builder.WithProjection(*projection)
+		}
 
-			plans = append(plans, &models.QueryExecutionPlan{
-				CanQuery:   true,
-				IndexName:  attempt.index,
-				Expression: expr,
-			})
+		expr, err := builder.Build()
+		if err != nil {
+			return nil, err
 		}
-	}
 
-	cb, err := ir.calcQueryForScan(info)
-	if err != nil {
-		return nil, err
+		plans = append(plans, &models.QueryExecutionPlan{
+			CanQuery:        true,
+			IndexName:       attempt.index,
+			Expression:      expr,
+			KeyTerms:        sliceutils.Map(keyTerms, planTerm.String),
+			FilterTerms:     sliceutils.Map(filterTerms, planTerm.String),
+			ItemFilterTerms: sliceutils.Map(itemTerms, planTerm.String),
+		})
 	}
 
-	builder := expression.NewBuilder()
-	builder = builder.WithFilter(cb)
+	var (
+		builder = expression.NewBuilder()
+		hasExpr bool
+	)
+	if len(terms) > 0 {
+		cb, err := calcFilterForTerms(terms, info)
+		if err != nil {
+			return nil, err
+		}
+		builder, hasExpr = builder.WithFilter(cb), true
+	}
+	if projection != nil {
+		builder, hasExpr = builder.WithProjection(*projection), true
+	}
 
-	expr, err := builder.Build()
-	if err != nil {
-		return nil, err
+	var expr expression.Expression
+	if hasExpr {
+		if expr, err = builder.Build(); err != nil {
+			return nil, err
+		}
 	}
 
 	plans = append(plans, &models.QueryExecutionPlan{
-		CanQuery:   false,
-		Expression: expr,
+		CanQuery:        false,
+		Expression:      expr,
+		FilterTerms:     sliceutils.Map(terms, planTerm.String),
+		ItemFilterTerms: sliceutils.Map(itemTerms, planTerm.String),
 	})
 	return plans, nil
 }
 
+// planTerm is a term of the top-level conjunction of the expression.
+type planTerm struct {
+	text string
+	ir   irAtom
+}
+
+func (pt planTerm) String() string {
+	return pt.text
+}
+
+// evalToPlanTerms returns the terms of the top-level conjunction of the expression.  If the expression is not
+// a conjunction, the entire expression is returned as a single term.
+func (a *astExpr) evalToPlanTerms(ctx *evalContext, info *models.TableInfo) ([]planTerm, error) {
+	if len(a.Root.Operands) != 1 {
+		ir, err := a.evalToIR(ctx, info)
+		if err != nil {
+			return nil, err
+		}
+		return []planTerm{{text: a.Root.String(), ir: ir}}, nil
+	}
+
+	operands := a.Root.Operands[0].Operands
+	terms := make([]planTerm, len(operands))
+	for i, op := range operands {
+		ir, err := op.evalToIR(ctx, info)
+		if err != nil {
+			return nil, err
+		}
+		terms[i] = planTerm{text: op.String(), ir: ir}
+	}
+	return terms, nil
+}
+
+// splitKeyTerms splits the terms into those that can be used as the key condition of a query over the passed
+// in keys, and those that will need to be evaluated as a filter.  The key terms consist of a term on the
+// partition key, optionally followed by a term on the sort key.  The returned query calc info records the keys
+// used by the key terms, and is nil if no term can be used for the partition key.
+func splitKeyTerms(terms []planTerm, keys models.KeyAttribute) (keyTerms []planTerm, filterTerms []planTerm, qci *queryCalcInfo) {
+	qci = &queryCalcInfo{keysUnderTest: keys}
+
+	var (
+		pkTermAt = -1
+		skTermAt = -1
+	)
+
+	for i, term := range terms {
+		testQci := qci.clone()
+		if canExecuteAsQuery(term.ir, testQci) && testQci.hasSeenPrimaryKey() {
+			pkTermAt, qci = i, testQci
+			break
+		}
+	}
+	if pkTermAt < 0 {
+		return nil, terms, nil
+	}
+
+	if !qci.hasSeenSortKey() {
+		for i, term := range terms {
+			if i == pkTermAt {
+				continue
+			}
+
+			testQci := qci.clone()
+			if canExecuteAsQuery(term.ir, testQci) && testQci.hasSeenSortKey() {
+				skTermAt, qci = i, testQci
+				break
+			}
+		}
+	}
+
+	keyTerms = []planTerm{terms[pkTermAt]}
+	if skTermAt >= 0 {
+		keyTerms = append(keyTerms, terms[skTermAt])
+	}
+	for i, term := range terms {
+		if i != pkTermAt && i != skTermAt {
+			filterTerms = append(filterTerms, term)
+		}
+	}
+	return keyTerms, filterTerms, qci
+}
+
+// splitItemTerms splits the terms into those that can be expressed as a DynamoDB condition, and those that will need
+// to be evaluated against the items once they're read.
+func splitItemTerms(terms []planTerm, info *models.TableInfo) (dbTerms []planTerm, itemTerms []planTerm, err error) {
+	for _, term := range terms {
+		if _, err := term.ir.calcQueryForScan(info); err != nil {
+			var cnse ConditionNotSupportedError
+			if !errors.As(err, &cnse) {
+				return nil, nil, err
+			}
+			itemTerms = append(itemTerms, term)
+			continue
+		}
+		dbTerms = append(dbTerms, term)
+	}
+	return dbTerms, itemTerms, nil
+}
+
+func calcFilterForTerms(terms []planTerm, info *models.TableInfo) (expression.ConditionBuilder, error) {
+	conds := make([]expression.ConditionBuilder, len(terms))
+	for i, term := range terms {
+		cond, err := term.ir.calcQueryForScan(info)
+		if err != nil {
+			return expression.ConditionBuilder{}, err
+		}
+		conds[i] = cond
+	}
+
+	switch len(conds) {
+	case 1:
+		return conds[0], nil
+	case 2:
+		return expression.And(conds[0], conds[1]), nil
+	}
+	return expression.And(conds[0], conds[1], conds[2:]...), nil
+}
+
 func (a *astExpr) evalToIR(ctx *evalContext, tableInfo *models.TableInfo) (irAtom, error) {
 	return a.Root.evalToIR(ctx, tableInfo)
 }
@@ -251,6 +501,16 @@ func (a *astExpr) evalItem(ctx *evalContext, item models.Item) (exprValue, error
 	return a.Root.evalItem(ctx, item)
 }
 
+func (a *astExpr) matchItem(ctx *evalContext, item models.Item) (bool, error) {
+	val, err := a.evalItem(ctx, item)
+	if err != nil {
+		return false, err
+	} else if val == nil {
+		return false, nil
+	}
+	return isAttributeTrue(val), nil
+}
+
 func (a *astExpr) setEvalItem(ctx *evalContext, item models.Item, value exprValue) error {
 	return a.Root.setEvalItem(ctx, item, value)
 }
@@ -82,8 +82,8 @@ func (a *astBetweenOp) evalItem(ctx *evalContext, item models.Item) (exprValue,
 			return nil, ValuesNotComparable{Left: val.asAttributeValue(), Right: toNumVal.asAttributeValue()}
 		}
 
-		fromCmp := v.asBigFloat().Cmp(fromNumVal.asBigFloat())
-		toCmp := v.asBigFloat().Cmp(toNumVal.asBigFloat())
+		fromCmp := v.asBigRat().Cmp(fromNumVal.asBigRat())
+		toCmp := v.asBigRat().Cmp(toNumVal.asBigRat())
 
 		return boolExprValue(fromCmp >= 0 && toCmp <= 0), nil
 	}
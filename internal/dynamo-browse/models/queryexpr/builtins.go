@@ -2,7 +2,18 @@ package queryexpr
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrcodec"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
 	"github.com/pkg/errors"
 )
 
@@ -42,8 +53,8 @@ var nativeFuncs = map[string]nativeFunc{
 			return nil, InvalidArgumentTypeError{Name: "range", ArgIndex: 1, Expected: "N"}
 		}
 
-		xInt, _ := xVal.asBigFloat().Int64()
-		yInt, _ := yVal.asBigFloat().Int64()
+		xInt := xVal.asInt()
+		yInt := yVal.asInt()
 		xs := make([]exprValue, 0)
 		for x := xInt; x <= yInt; x++ {
 			xs = append(xs, int64ExprValue(x))
@@ -87,6 +98,293 @@ var nativeFuncs = map[string]nativeFunc{
 		return listExprValue(items), nil
 	},
 
+	"contains": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 2 {
+			return nil, InvalidArgumentNumberError{Name: "contains", Expected: 2, Actual: len(args)}
+		}
+
+		switch t := args[0].(type) {
+		case nil, undefinedExprValue:
+			return boolExprValue(false), nil
+		case stringableExprValue:
+			substr, isStr := args[1].(stringableExprValue)
+			if !isStr {
+				return nil, InvalidArgumentTypeError{Name: "contains", ArgIndex: 1, Expected: "S"}
+			}
+			return boolExprValue(strings.Contains(t.asString(), substr.asString())), nil
+		case slicableExprValue:
+			for i := 0; i < t.len(); i++ {
+				v, err := t.valueAt(i)
+				if err != nil {
+					return nil, err
+				}
+				if v != nil && attrutils.Equals(v.asAttributeValue(), args[1].asAttributeValue()) {
+					return boolExprValue(true), nil
+				}
+			}
+			return boolExprValue(false), nil
+		}
+		return nil, InvalidArgumentTypeError{Name: "contains", ArgIndex: 0, Expected: "S or L"}
+	},
+
+	"begins_with": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 2 {
+			return nil, InvalidArgumentNumberError{Name: "begins_with", Expected: 2, Actual: len(args)}
+		}
+		if isUndefined(args[0]) {
+			return boolExprValue(false), nil
+		}
+
+		str, err := stringArg("begins_with", args, 0)
+		if err != nil {
+			return nil, err
+		}
+		prefix, err := stringArg("begins_with", args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return boolExprValue(strings.HasPrefix(str, prefix)), nil
+	},
+
+	"attribute_type": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 2 {
+			return nil, InvalidArgumentNumberError{Name: "attribute_type", Expected: 2, Actual: len(args)}
+		}
+
+		typeName, err := stringArg("attribute_type", args, 1)
+		if err != nil {
+			return nil, err
+		}
+		typeInfo, isValidType := validIsTypeNames[strings.ToUpper(typeName)]
+		if !isValidType {
+			return nil, InvalidTypeForIsError{TypeName: typeName}
+		}
+		return boolExprValue(typeInfo.isTypeOf(args[0])), nil
+	},
+
+	"lower": stringFunc("lower", strings.ToLower),
+	"upper": stringFunc("upper", strings.ToUpper),
+	"trim":  stringFunc("trim", strings.TrimSpace),
+
+	"substr": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 2 && len(args) != 3 {
+			return nil, InvalidArgumentNumberError{Name: "substr", Expected: 3, Actual: len(args)}
+		}
+
+		str, err := stringArg("substr", args, 0)
+		if err != nil {
+			return nil, err
+		}
+		runes := []rune(str)
+
+		start, err := intArg("substr", args, 1)
+		if err != nil {
+			return nil, err
+		}
+		start = max(0, min(start, len(runes)))
+
+		end := len(runes)
+		if len(args) == 3 {
+			l, err := intArg("substr", args, 2)
+			if err != nil {
+				return nil, err
+			}
+			end = max(start, min(start+l, len(runes)))
+		}
+		return stringExprValue(runes[start:end]), nil
+	},
+
+	"split": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 2 {
+			return nil, InvalidArgumentNumberError{Name: "split", Expected: 2, Actual: len(args)}
+		}
+
+		str, err := stringArg("split", args, 0)
+		if err != nil {
+			return nil, err
+		}
+		sep, err := stringArg("split", args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return listExprValue(sliceutils.Map(strings.Split(str, sep), func(s string) exprValue {
+			return stringExprValue(s)
+		})), nil
+	},
+
+	"match": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 2 {
+			return nil, InvalidArgumentNumberError{Name: "match", Expected: 2, Actual: len(args)}
+		}
+		if isUndefined(args[0]) {
+			return boolExprValue(false), nil
+		}
+
+		str, err := stringArg("match", args, 0)
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := stringArg("match", args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "match(): invalid pattern '%v'", pattern)
+		}
+		return boolExprValue(re.MatchString(str)), nil
+	},
+
+	"now": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 0 {
+			return nil, InvalidArgumentNumberError{Name: "now", Expected: 0, Actual: len(args)}
+		}
+		return int64ExprValue(timeSourceFromContext(ctx).now().Unix()), nil
+	},
+
+	"parse_time": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 1 && len(args) != 2 {
+			return nil, InvalidArgumentNumberError{Name: "parse_time", Expected: 2, Actual: len(args)}
+		}
+
+		str, err := stringArg("parse_time", args, 0)
+		if err != nil {
+			return nil, err
+		}
+		layout, err := layoutArg("parse_time", args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := time.Parse(layout, str)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse_time(): cannot parse '%v'", str)
+		}
+		return int64ExprValue(t.Unix()), nil
+	},
+
+	"format_time": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 1 && len(args) != 2 {
+			return nil, InvalidArgumentNumberError{Name: "format_time", Expected: 2, Actual: len(args)}
+		}
+
+		secs, err := intArg("format_time", args, 0)
+		if err != nil {
+			return nil, err
+		}
+		layout, err := layoutArg("format_time", args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return stringExprValue(time.Unix(int64(secs), 0).UTC().Format(layout)), nil
+	},
+
+	"unix": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 1 {
+			return nil, InvalidArgumentNumberError{Name: "unix", Expected: 1, Actual: len(args)}
+		}
+
+		switch t := args[0].(type) {
+		case numberableExprValue:
+			return int64ExprValue(t.asInt()), nil
+		case stringableExprValue:
+			tm, err := time.Parse(time.RFC3339, t.asString())
+			if err != nil {
+				return nil, errors.Wrapf(err, "unix(): cannot parse '%v'", t.asString())
+			}
+			return int64ExprValue(tm.Unix()), nil
+		}
+		return nil, InvalidArgumentTypeError{Name: "unix", ArgIndex: 0, Expected: "S or N"}
+	},
+
+	"uuid": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 0 {
+			return nil, InvalidArgumentNumberError{Name: "uuid", Expected: 0, Actual: len(args)}
+		}
+
+		var u [16]byte
+		if _, err := rand.Read(u[:]); err != nil {
+			return nil, err
+		}
+		u[6] = (u[6] & 0x0f) | 0x40
+		u[8] = (u[8] & 0x3f) | 0x80
+		return stringExprValue(fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])), nil
+	},
+
+	"coalesce": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		for _, arg := range args {
+			if !isUndefined(arg) {
+				if _, isNull := arg.(nullExprValue); !isNull {
+					return arg, nil
+				}
+			}
+		}
+		return undefinedExprValue{}, nil
+	},
+
+	"keys": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 1 {
+			return nil, InvalidArgumentNumberError{Name: "keys", Expected: 1, Actual: len(args)}
+		}
+
+		m, isMap := args[0].(mappableExprValue)
+		if !isMap {
+			return nil, InvalidArgumentTypeError{Name: "keys", ArgIndex: 0, Expected: "M"}
+		}
+
+		keys := m.keys()
+		sort.Strings(keys)
+		return listExprValue(sliceutils.Map(keys, func(k string) exprValue {
+			return stringExprValue(k)
+		})), nil
+	},
+
+	"values": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 1 {
+			return nil, InvalidArgumentNumberError{Name: "values", Expected: 1, Actual: len(args)}
+		}
+
+		m, isMap := args[0].(mappableExprValue)
+		if !isMap {
+			return nil, InvalidArgumentTypeError{Name: "values", ArgIndex: 0, Expected: "M"}
+		}
+
+		keys := m.keys()
+		sort.Strings(keys)
+		vals, err := sliceutils.MapWithError(keys, func(k string) (exprValue, error) {
+			return m.valueOf(k)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return listExprValue(vals), nil
+	},
+
+	"json_decode": func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 1 {
+			return nil, InvalidArgumentNumberError{Name: "json_decode", Expected: 1, Actual: len(args)}
+		}
+
+		str, err := stringArg("json_decode", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		var v any
+		dec := json.NewDecoder(strings.NewReader(str))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return nil, errors.Wrap(err, "json_decode(): invalid JSON")
+		}
+
+		av, err := attrcodec.FromPlainJSON(v)
+		if err != nil {
+			return nil, err
+		}
+		return newExprValueFromAttributeValue(av)
+	},
+
 	"_x_now": func(ctx context.Context, args []exprValue) (exprValue, error) {
 		now := timeSourceFromContext(ctx).now().Unix()
 		return int64ExprValue(now), nil
@@ -106,7 +404,7 @@ var nativeFuncs = map[string]nativeFunc{
 			return nil, InvalidArgumentTypeError{Name: "_x_add", ArgIndex: 1, Expected: "N"}
 		}
 
-		return bigNumExprValue{num: xVal.asBigFloat().Add(xVal.asBigFloat(), yVal.asBigFloat())}, nil
+		return bigNumExprValue{num: new(big.Rat).Add(xVal.asBigRat(), yVal.asBigRat())}, nil
 	},
 
 	"_x_concat": func(ctx context.Context, args []exprValue) (exprValue, error) {
@@ -126,3 +424,45 @@ var nativeFuncs = map[string]nativeFunc{
 		return stringExprValue(xVal.asString() + yVal.asString()), nil
 	},
 }
+
+func stringFunc(name string, fn func(string) string) nativeFunc {
+	return func(ctx context.Context, args []exprValue) (exprValue, error) {
+		if len(args) != 1 {
+			return nil, InvalidArgumentNumberError{Name: name, Expected: 1, Actual: len(args)}
+		}
+
+		str, err := stringArg(name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return stringExprValue(fn(str)), nil
+	}
+}
+
+func stringArg(name string, args []exprValue, idx int) (string, error) {
+	str, isStr := args[idx].(stringableExprValue)
+	if !isStr {
+		return "", InvalidArgumentTypeError{Name: name, ArgIndex: idx, Expected: "S"}
+	}
+	return str.asString(), nil
+}
+
+func intArg(name string, args []exprValue, idx int) (int, error) {
+	num, isNum := args[idx].(numberableExprValue)
+	if !isNum {
+		return 0, InvalidArgumentTypeError{Name: name, ArgIndex: idx, Expected: "N"}
+	}
+	return int(num.asInt()), nil
+}
+
+// layoutArg returns the time layout at the given arg index, or RFC3339 if the arg was not supplied
+func layoutArg(name string, args []exprValue, idx int) (string, error) {
+	if idx >= len(args) {
+		return time.RFC3339, nil
+	}
+	return stringArg(name, args, idx)
+}
+
+func isUndefined(v exprValue) bool {
+	return v == nil || v == undefinedExprValue{}
+}
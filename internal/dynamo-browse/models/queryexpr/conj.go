@@ -3,7 +3,6 @@ package queryexpr
 import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
-	"math/big"
 	"strings"
 )
 
@@ -111,8 +110,9 @@ func (i *irDualConjunction) canBeExecutedAsQuery(qci *queryCalcInfo) bool {
 
 	// Might be that the right is the partition key, so test again with them swapped
 	rightCanExecuteAsQuery := canExecuteAsQuery(i.right, qciCopy)
-	if rightCanExecuteAsQuery {
-		return canExecuteAsQuery(i.left, qciCopy)
+	if rightCanExecuteAsQuery && canExecuteAsQuery(i.left, qciCopy) {
+		*qci = *qciCopy
+		return true
 	}
 
 	return false
@@ -170,14 +170,14 @@ func (d *irMultiConjunction) calcQueryForScan(info *models.TableInfo) (expressio
 
 func isAttributeTrue(attr exprValue) bool {
 	switch val := attr.(type) {
-	case nullExprValue:
+	case nil, nullExprValue, undefinedExprValue:
 		return false
 	case boolExprValue:
 		return bool(val)
 	case stringableExprValue:
 		return val.asString() != ""
 	case numberableExprValue:
-		return val.asBigFloat().Cmp(&big.Float{}) != 0
+		return val.asBigRat().Sign() != 0
 	}
 	return true
 }
@@ -82,6 +82,15 @@ func (n NodeCannotBeConvertedToQueryError) Error() string {
 	return "node cannot be converted to query"
 }
 
+// ConditionNotSupportedError indicates that a term cannot be expressed as a DynamoDB condition
+type ConditionNotSupportedError struct {
+	Expr string
+}
+
+func (n ConditionNotSupportedError) Error() string {
+	return fmt.Sprintf("'%v' cannot be used as a DynamoDB condition", n.Expr)
+}
+
 type ValueMustBeLiteralError struct{}
 
 func (n ValueMustBeLiteralError) Error() string {
@@ -176,3 +185,29 @@ type NoPlausiblePlanWithIndexError struct {
 func (e NoPlausiblePlanWithIndexError) Error() string {
 	return fmt.Sprintf("no plan with index '%v' found: possible indices are %v", e.PreferredIndex, e.PossibleIndices)
 }
+
+// OperatorNotApplicableError is returned if an arithmetic operator cannot be applied to the types of its operands
+type OperatorNotApplicableError struct {
+	Op          string
+	Left, Right string
+}
+
+func (e OperatorNotApplicableError) Error() string {
+	return fmt.Sprintf("operator '%v' cannot be applied to values of type %v and %v", e.Op, e.Left, e.Right)
+}
+
+// OperatorNotSupportedInExpressionError is returned if an arithmetic operator over attributes cannot be
+// expressed in a DynamoDB expression
+type OperatorNotSupportedInExpressionError struct {
+	Op string
+}
+
+func (e OperatorNotSupportedInExpressionError) Error() string {
+	return fmt.Sprintf("operator '%v' over attributes cannot be used in a DynamoDB expression", e.Op)
+}
+
+type DivideByZeroError struct{}
+
+func (e DivideByZeroError) Error() string {
+	return "divide by zero"
+}
@@ -5,8 +5,11 @@ import (
 	"encoding/gob"
 	"hash/fnv"
 	"io"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrcodec"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
@@ -212,6 +215,23 @@ func (md *QueryExpr) EvalItem(item models.Item) (types.AttributeValue, error) {
 	return val.asAttributeValue(), nil
 }
 
+// UpdateValue returns the expression as a value usable in the SET action of an update expression.  The
+// expression can be a name, a value, or the sum or difference of two names or values, as in "a + 1".
+func (md *QueryExpr) UpdateValue(tableInfo *models.TableInfo) (expression.OperandBuilder, error) {
+	ir, err := md.ast.evalToIR(md.evalContext(), tableInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := ir.(type) {
+	case irArith:
+		return t.calcSetValue(tableInfo)
+	case oprIRAtom:
+		return t.calcOperand(tableInfo), nil
+	}
+	return nil, OperandNotAnOperandError{}
+}
+
 func (md *QueryExpr) DeleteAttribute(item models.Item) error {
 	return md.ast.deleteAttribute(md.evalContext(), item)
 }
@@ -241,7 +261,10 @@ func (md *QueryExpr) String() string {
 }
 
 func (a *astExpr) String() string {
-	return a.Root.String()
+	if len(a.Options) == 0 {
+		return a.Root.String()
+	}
+	return a.Root.String() + " using " + strings.Join(sliceutils.Map(a.Options, (*astOption).String), ", ")
 }
 
 type queryCalcInfo struct {
@@ -262,6 +285,14 @@ func (qc *queryCalcInfo) hasSeenPrimaryKey() bool {
 	return hasKey
 }
 
+func (qc *queryCalcInfo) hasSeenSortKey() bool {
+	if qc.keysUnderTest.SortKey == "" {
+		return false
+	}
+	_, hasKey := qc.seenKeys[qc.keysUnderTest.SortKey]
+	return hasKey
+}
+
 func (qc *queryCalcInfo) addKey(key string) bool {
 	if qc.keysUnderTest.PartitionKey != key && qc.keysUnderTest.SortKey != key {
 		return false
@@ -3,10 +3,12 @@ package queryexpr_test
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/queryexpr"
 
@@ -44,6 +46,15 @@ func TestModExpr_Query(t *testing.T) {
 				},
 			},
 		},
+		LSIs: []models.TableLSI{
+			{
+				Name: "with-rating",
+				Keys: models.KeyAttribute{
+					PartitionKey: "pk",
+					SortKey:      "rating",
+				},
+			},
+		},
 	}
 
 	t.Run("as queries", func(t *testing.T) {
@@ -102,6 +113,12 @@ func TestModExpr_Query(t *testing.T) {
 				exprNameIsString(0, 0, "pk", "prefix"),
 				exprNameIsString(1, 1, "sk", "another"),
 			),
+			scanCase("when request pk is equals and sk begins_with function",
+				`pk="prefix" and begins_with(sk, "another")`,
+				`(#0 = :0) AND (begins_with (#1, :1))`,
+				exprNameIsString(0, 0, "pk", "prefix"),
+				exprNameIsString(1, 1, "sk", "another"),
+			),
 			scanCase("when request pk is equals and sk is less than",
 				`pk="prefix" and sk < 100`,
 				`(#0 = :0) AND (#1 < :1)`,
@@ -171,6 +188,13 @@ func TestModExpr_Query(t *testing.T) {
 				exprNameIsString(1, 1, "shade", "dark"),
 			),
 
+			// Arithmetic
+			scanCase("use the value of string concatenation in query",
+				`pk = "Hello " + "world"`,
+				`#0 = :0`,
+				exprNameIsString(0, 0, "pk", "Hello world"),
+			),
+
 			// Function calls
 			scanCase("use the value of fn call in query",
 				`pk = _x_concat("Hello ", "world")`,
@@ -202,6 +226,106 @@ func TestModExpr_Query(t *testing.T) {
 		}
 	})
 
+	t.Run("as queries with filters", func(t *testing.T) {
+		scenarios := []struct {
+			description         string
+			expression          string
+			expectedIndex       string
+			expectedKeyCond     string
+			expectedFilter      string
+			expectedKeyTerms    []string
+			expectedFilterTerms []string
+			expectedNames       map[string]string
+			expectedValues      map[string]types.AttributeValue
+		}{
+			{
+				description:         "pk with a non-key term",
+				expression:          `pk="prefix" and status="open"`,
+				expectedKeyCond:     `#1 = :1`,
+				expectedFilter:      `#0 = :0`,
+				expectedKeyTerms:    []string{`pk="prefix"`},
+				expectedFilterTerms: []string{`status="open"`},
+			},
+			{
+				description:         "non-key term before the pk",
+				expression:          `status="open" and pk="prefix"`,
+				expectedKeyCond:     `#1 = :1`,
+				expectedFilter:      `#0 = :0`,
+				expectedKeyTerms:    []string{`pk="prefix"`},
+				expectedFilterTerms: []string{`status="open"`},
+			},
+			{
+				description:         "pk and sk with non-key terms",
+				expression:          `num > 3 and sk ^= "abc" and pk="prefix" and status="open"`,
+				expectedKeyCond:     `(#2 = :2) AND (begins_with (#3, :3))`,
+				expectedFilter:      `(#0 > :0) AND (#1 = :1)`,
+				expectedKeyTerms:    []string{`pk="prefix"`, `sk^="abc"`},
+				expectedFilterTerms: []string{`num>3`, `status="open"`},
+			},
+			{
+				description:         "with repeated pk as key condition and filter",
+				expression:          `pk="prefix" and pk="another"`,
+				expectedKeyCond:     `#0 = :1`,
+				expectedFilter:      `#0 = :0`,
+				expectedKeyTerms:    []string{`pk="prefix"`},
+				expectedFilterTerms: []string{`pk="another"`},
+				expectedNames:       map[string]string{"#0": "pk"},
+				expectedValues: map[string]types.AttributeValue{
+					":0": &types.AttributeValueMemberS{Value: "another"},
+					":1": &types.AttributeValueMemberS{Value: "prefix"},
+				},
+			},
+			{
+				description:         "pk and sk within parens with non-key terms",
+				expression:          `(pk="prefix" and sk="abc") and status="open"`,
+				expectedKeyCond:     `(#1 = :1) AND (#2 = :2)`,
+				expectedFilter:      `#0 = :0`,
+				expectedKeyTerms:    []string{`(pk="prefix" and sk="abc")`},
+				expectedFilterTerms: []string{`status="open"`},
+			},
+			{
+				description:         "index pk with table pk",
+				expression:          `color="blue" and pk="prefix"`,
+				expectedKeyCond:     `#1 = :1`,
+				expectedFilter:      `#0 = :0`,
+				expectedKeyTerms:    []string{`pk="prefix"`},
+				expectedFilterTerms: []string{`color="blue"`},
+			},
+			{
+				description:         "index pk and sk with table pk",
+				expression:          `color="blue" and pk="prefix" and shade="dark"`,
+				expectedIndex:       "with-color",
+				expectedKeyCond:     `(#1 = :1) AND (#2 = :2)`,
+				expectedFilter:      `#0 = :0`,
+				expectedKeyTerms:    []string{`color="blue"`, `shade="dark"`},
+				expectedFilterTerms: []string{`pk="prefix"`},
+			},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.description, func(t *testing.T) {
+				modExpr, err := queryexpr.Parse(scenario.expression)
+				assert.NoError(t, err)
+
+				plan, err := modExpr.Plan(tableInfo)
+				assert.NoError(t, err)
+
+				assert.True(t, plan.CanQuery)
+				assert.Equal(t, scenario.expectedIndex, plan.IndexName)
+				assert.Equal(t, scenario.expectedKeyCond, aws.ToString(plan.Expression.KeyCondition()))
+				assert.Equal(t, scenario.expectedFilter, aws.ToString(plan.Expression.Filter()))
+				assert.Equal(t, scenario.expectedKeyTerms, plan.KeyTerms)
+				assert.Equal(t, scenario.expectedFilterTerms, plan.FilterTerms)
+				for k, v := range scenario.expectedNames {
+					assert.Equal(t, v, plan.Expression.Names()[k])
+				}
+				for k, v := range scenario.expectedValues {
+					assert.Equal(t, v, plan.Expression.Values()[k])
+				}
+			})
+		}
+	})
+
 	t.Run("as scans", func(t *testing.T) {
 		scenarios := []scanScenario{
 			scanCase("when request pk prefix", `pk^="prefix"`, `begins_with (#0, :0)`,
@@ -213,6 +337,21 @@ func TestModExpr_Query(t *testing.T) {
 			scanCase("when request sk starts with something", `sk^="something"`, `begins_with (#0, :0)`,
 				exprNameIsString(0, 0, "sk", "something"),
 			),
+			scanCase("with contains function", `contains(tags, "red")`, `contains (#0, :0)`,
+				exprNameIsString(0, 0, "tags", "red"),
+			),
+			scanCase("with begins_with function", `begins_with(sk, "something")`, `begins_with (#0, :0)`,
+				exprNameIsString(0, 0, "sk", "something"),
+			),
+			scanCase("with attribute_type function", `attribute_type(num, "N")`, `attribute_type (#0, :0)`,
+				exprNameIsString(0, 0, "num", "N"),
+			),
+			scanCase("with not contains function", `not contains(tags, "red")`, `NOT (contains (#0, :0))`,
+				exprNameIsString(0, 0, "tags", "red"),
+			),
+			scanCase("with function over literal values", `alpha = upper("prefix")`, `#0 = :0`,
+				exprNameIsString(0, 0, "alpha", "PREFIX"),
+			),
 			scanCase("with not equal", `sk != "something"`, `#0 <> :0`,
 				exprNameIsString(0, 0, "sk", "something"),
 			),
@@ -256,11 +395,10 @@ func TestModExpr_Query(t *testing.T) {
 				exprNameIsNumber(1, 1, "num", "123"),
 				exprNameIsNumber(2, 2, "negnum", "-131"),
 			),
-			scanCase("with disjunctions if pk is present twice in expression",
-				`pk="prefix" and pk="another"`,
-				`(#0 = :0) AND (#0 = :1)`,
-				exprNameIsString(0, 0, "pk", "prefix"),
-				exprNameIsString(0, 1, "pk", "another"),
+			scanCase("use the value of arithmetic over literals in scan",
+				`num = 60 * 60 + 1`,
+				`#0 = :0`,
+				exprNameIsNumber(0, 0, "num", "3601"),
 			),
 			scanCase("with not", `not pk="prefix"`, `NOT (#0 = :0)`,
 				exprNameIsString(0, 0, "pk", "prefix"),
@@ -456,6 +594,206 @@ func TestModExpr_Query(t *testing.T) {
 			assert.Error(t, err)
 		})
 	})
+
+	t.Run("with terms evaluated against the items", func(t *testing.T) {
+		item := models.Item{
+			"pk":     &types.AttributeValueMemberS{Value: "abc"},
+			"alpha":  &types.AttributeValueMemberS{Value: "alpha"},
+			"prefix": &types.AttributeValueMemberS{Value: "al"},
+			"nums":   &types.AttributeValueMemberNS{Value: []string{"3", "5"}},
+			"tags": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+				&types.AttributeValueMemberN{Value: "5"},
+			}},
+		}
+
+		scenarios := []struct {
+			description         string
+			expression          string
+			expectedCanQuery    bool
+			expectedFilter      string
+			expectedFilterTerms []string
+			expectedItemTerms   []string
+			expectedMatch       bool
+		}{
+			{
+				description:       "contains with number value",
+				expression:        `contains(nums, 5)`,
+				expectedItemTerms: []string{`contains(nums, 5)`},
+				expectedMatch:     true,
+			},
+			{
+				description:       "contains with missing number value",
+				expression:        `contains(nums, 4)`,
+				expectedItemTerms: []string{`contains(nums, 4)`},
+				expectedMatch:     false,
+			},
+			{
+				description:       "contains with attribute value",
+				expression:        `contains(alpha, prefix)`,
+				expectedItemTerms: []string{`contains(alpha, prefix)`},
+				expectedMatch:     true,
+			},
+			{
+				description:       "begins_with with attribute value",
+				expression:        `begins_with(alpha, prefix)`,
+				expectedItemTerms: []string{`begins_with(alpha, prefix)`},
+				expectedMatch:     true,
+			},
+			{
+				description:         "with other terms as filter",
+				expression:          `alpha="alpha" and contains(tags, 4)`,
+				expectedFilter:      `#0 = :0`,
+				expectedFilterTerms: []string{`alpha="alpha"`},
+				expectedItemTerms:   []string{`contains(tags, 4)`},
+				expectedMatch:       false,
+			},
+			{
+				description:       "with other terms as key condition",
+				expression:        `pk="abc" and contains(tags, 5)`,
+				expectedCanQuery:  true,
+				expectedItemTerms: []string{`contains(tags, 5)`},
+				expectedMatch:     true,
+			},
+			{
+				description:       "within a disjunction",
+				expression:        `alpha="bravo" or contains(tags, 5)`,
+				expectedItemTerms: []string{`alpha="bravo" or contains(tags, 5)`},
+				expectedMatch:     true,
+			},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.description, func(t *testing.T) {
+				modExpr, err := queryexpr.Parse(scenario.expression)
+				assert.NoError(t, err)
+
+				plan, err := modExpr.Plan(tableInfo)
+				assert.NoError(t, err)
+
+				assert.Equal(t, scenario.expectedCanQuery, plan.CanQuery)
+				assert.Equal(t, scenario.expectedFilter, aws.ToString(plan.Expression.Filter()))
+				assert.ElementsMatch(t, scenario.expectedFilterTerms, plan.FilterTerms)
+				assert.ElementsMatch(t, scenario.expectedItemTerms, plan.ItemFilterTerms)
+
+				isMatch, err := plan.ItemFilter(item)
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.expectedMatch, isMatch)
+			})
+		}
+
+		t.Run("should return error if attributes are selected", func(t *testing.T) {
+			modExpr, err := queryexpr.Parse(`contains(nums, 5) using select("alpha")`)
+			assert.NoError(t, err)
+
+			_, err = modExpr.Plan(tableInfo)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("with local secondary index", func(t *testing.T) {
+		t.Run("should query the table if the LSI sort key is not constrained", func(t *testing.T) {
+			modExpr, err := queryexpr.Parse(`pk="abc" and sk^="1"`)
+			assert.NoError(t, err)
+
+			plan, err := modExpr.Plan(tableInfo)
+			assert.NoError(t, err)
+			assert.True(t, plan.CanQuery)
+			assert.Equal(t, "", plan.IndexName)
+		})
+
+		t.Run("should query the LSI if the LSI sort key is constrained", func(t *testing.T) {
+			scenarios := []string{
+				`pk="abc" and rating=5`,
+				`rating>3 and pk="abc"`,
+				`pk="abc" and rating between 1 and 3`,
+			}
+
+			for _, scenario := range scenarios {
+				t.Run(scenario, func(t *testing.T) {
+					modExpr, err := queryexpr.Parse(scenario)
+					assert.NoError(t, err)
+
+					plan, err := modExpr.Plan(tableInfo)
+					assert.NoError(t, err)
+					assert.True(t, plan.CanQuery)
+					assert.Equal(t, "with-rating", plan.IndexName)
+				})
+			}
+		})
+
+		t.Run("should query the LSI if chosen explicitly", func(t *testing.T) {
+			modExpr, err := queryexpr.Parse(`pk="abc" using index("with-rating")`)
+			assert.NoError(t, err)
+
+			plan, err := modExpr.Plan(tableInfo)
+			assert.NoError(t, err)
+			assert.True(t, plan.CanQuery)
+			assert.Equal(t, "with-rating", plan.IndexName)
+		})
+	})
+
+	t.Run("with query options", func(t *testing.T) {
+		t.Run("should set the read options and limit of the plan", func(t *testing.T) {
+			modExpr, err := queryexpr.Parse(`pk="abc" using desc, limit(5), consistent`)
+			assert.NoError(t, err)
+
+			plan, err := modExpr.Plan(tableInfo)
+			assert.NoError(t, err)
+			assert.True(t, plan.CanQuery)
+			assert.Equal(t, models.ReadOptions{Descending: true, ConsistentRead: true}, plan.ReadOptions)
+			assert.Equal(t, 5, plan.Limit)
+		})
+
+		t.Run("should combine options with scan and index", func(t *testing.T) {
+			modExpr, err := queryexpr.Parse(`apples="this" using index("with-apples"), limit(10)`)
+			assert.NoError(t, err)
+
+			plan, err := modExpr.Plan(tableInfo)
+			assert.NoError(t, err)
+			assert.True(t, plan.CanQuery)
+			assert.Equal(t, "with-apples", plan.IndexName)
+			assert.Equal(t, 10, plan.Limit)
+
+			modExpr, err = queryexpr.Parse(`pk="abc" using scan, consistent`)
+			assert.NoError(t, err)
+
+			plan, err = modExpr.Plan(tableInfo)
+			assert.NoError(t, err)
+			assert.False(t, plan.CanQuery)
+			assert.True(t, plan.ReadOptions.ConsistentRead)
+		})
+
+		t.Run("should project the selected attributes and the table keys", func(t *testing.T) {
+			modExpr, err := queryexpr.Parse(`pk="abc" using select(alpha, charlie.door, pk)`)
+			assert.NoError(t, err)
+
+			plan, err := modExpr.Plan(tableInfo)
+			assert.NoError(t, err)
+			assert.True(t, plan.CanQuery)
+
+			projectedNames := make([]string, 0)
+			for _, n := range strings.Split(aws.ToString(plan.Expression.Projection()), ", ") {
+				projectedNames = append(projectedNames, plan.Expression.Names()[n])
+			}
+			assert.Equal(t, []string{"alpha", "charlie.door", "pk", "sk"}, projectedNames)
+		})
+
+		t.Run("should keep options when converted to a string", func(t *testing.T) {
+			exprStr := `pk="abc" using index("with-rating"), desc, limit(5), consistent, select(alpha, charlie.door)`
+
+			modExpr, err := queryexpr.Parse(exprStr)
+			assert.NoError(t, err)
+			assert.Equal(t, exprStr, modExpr.String())
+		})
+
+		t.Run("should return error if limit is not greater than zero", func(t *testing.T) {
+			modExpr, err := queryexpr.Parse(`pk="abc" using limit(0)`)
+			assert.NoError(t, err)
+
+			_, err = modExpr.Plan(tableInfo)
+			assert.Error(t, err)
+		})
+	})
 }
 
 func TestQueryExpr_EvalItem(t *testing.T) {
@@ -480,6 +818,9 @@ func TestQueryExpr_EvalItem(t *testing.T) {
 			"one":   &types.AttributeValueMemberN{Value: "1"},
 			"three": &types.AttributeValueMemberN{Value: "3"},
 			"five":  &types.AttributeValueMemberN{Value: "5"},
+			"price": &types.AttributeValueMemberN{Value: "10"},
+			"dec":   &types.AttributeValueMemberN{Value: "12345678.9012"},
+			"big":   &types.AttributeValueMemberN{Value: "12345678901234567890123"},
 		}
 	)
 
@@ -593,6 +934,32 @@ func TestQueryExpr_EvalItem(t *testing.T) {
 			// Order of operation
 			{expr: `alpha="alpha" and bravo=123 or charlie.door="green"`, expected: &types.AttributeValueMemberBOOL{Value: true}},
 			{expr: `alpha="bravo" or bravo=321 and charlie.door="green"`, expected: &types.AttributeValueMemberBOOL{Value: false}},
+
+			// Arithmetic
+			{expr: `bravo + 1`, expected: &types.AttributeValueMemberN{Value: "124"}},
+			{expr: `bravo - one - three`, expected: &types.AttributeValueMemberN{Value: "119"}},
+			{expr: `three * five`, expected: &types.AttributeValueMemberN{Value: "15"}},
+			{expr: `five / 2`, expected: &types.AttributeValueMemberN{Value: "2.5"}},
+			{expr: `1.5 * 2`, expected: &types.AttributeValueMemberN{Value: "3"}},
+			{expr: `one + three * five`, expected: &types.AttributeValueMemberN{Value: "16"}},
+			{expr: `(one + three) * five`, expected: &types.AttributeValueMemberN{Value: "20"}},
+			{expr: `-bravo`, expected: &types.AttributeValueMemberN{Value: "-123"}},
+			{expr: `prime[1] * prime[2]`, expected: &types.AttributeValueMemberN{Value: "15"}},
+			{expr: `alpha + "-" + charlie.door`, expected: &types.AttributeValueMemberS{Value: "alpha-red"}},
+			{expr: `bravo + 1 = 124`, expected: &types.AttributeValueMemberBOOL{Value: true}},
+			{expr: `three * 2 > five`, expected: &types.AttributeValueMemberBOOL{Value: true}},
+
+			// Arithmetic precision
+			{expr: `12345678.9012 + 0`, expected: &types.AttributeValueMemberN{Value: "12345678.9012"}},
+			{expr: `dec + 0`, expected: &types.AttributeValueMemberN{Value: "12345678.9012"}},
+			{expr: `0.1 + 0.2`, expected: &types.AttributeValueMemberN{Value: "0.3"}},
+			{expr: `price / 3`, expected: &types.AttributeValueMemberN{Value: "3.3333333333333333333333333333333333333"}},
+			{expr: `2 / 3`, expected: &types.AttributeValueMemberN{Value: "0.66666666666666666666666666666666666667"}},
+			{expr: `1 / 3000000`, expected: &types.AttributeValueMemberN{Value: "0.00000033333333333333333333333333333333333333"}},
+			{expr: `big + 1`, expected: &types.AttributeValueMemberN{Value: "12345678901234567890124"}},
+			{expr: `big * big`, expected: &types.AttributeValueMemberN{Value: "152415787532388367504942236884722755800000000"}},
+			{expr: `-dec`, expected: &types.AttributeValueMemberN{Value: "-12345678.9012"}},
+			{expr: `big + 1 > big`, expected: &types.AttributeValueMemberBOOL{Value: true}},
 		}
 		for _, scenario := range scenarios {
 			t.Run(scenario.expr, func(t *testing.T) {
@@ -634,6 +1001,53 @@ func TestQueryExpr_EvalItem(t *testing.T) {
 			}}},
 			{expr: `one in marked("num")`, expected: &types.AttributeValueMemberBOOL{Value: true}},
 			{expr: `three in marked("num")`, expected: &types.AttributeValueMemberBOOL{Value: false}},
+
+			// String functions
+			{expr: `contains(alpha, "lph")`, expected: &types.AttributeValueMemberBOOL{Value: true}},
+			{expr: `contains(alpha, "bravo")`, expected: &types.AttributeValueMemberBOOL{Value: false}},
+			{expr: `contains(prime, 5)`, expected: &types.AttributeValueMemberBOOL{Value: true}},
+			{expr: `contains(prime, 4)`, expected: &types.AttributeValueMemberBOOL{Value: false}},
+			{expr: `contains(missing, "a")`, expected: &types.AttributeValueMemberBOOL{Value: false}},
+			{expr: `begins_with(alpha, "al")`, expected: &types.AttributeValueMemberBOOL{Value: true}},
+			{expr: `begins_with(alpha, "ph")`, expected: &types.AttributeValueMemberBOOL{Value: false}},
+			{expr: `lower("Hello World")`, expected: &types.AttributeValueMemberS{Value: "hello world"}},
+			{expr: `upper(alpha)`, expected: &types.AttributeValueMemberS{Value: "ALPHA"}},
+			{expr: `trim("  spaced  ")`, expected: &types.AttributeValueMemberS{Value: "spaced"}},
+			{expr: `substr(alpha, 1, 3)`, expected: &types.AttributeValueMemberS{Value: "lph"}},
+			{expr: `substr(alpha, 2)`, expected: &types.AttributeValueMemberS{Value: "pha"}},
+			{expr: `substr(alpha, 3, 100)`, expected: &types.AttributeValueMemberS{Value: "ha"}},
+			{expr: `split("a,b,c", ",")`, expected: &types.AttributeValueMemberL{Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "a"},
+				&types.AttributeValueMemberS{Value: "b"},
+				&types.AttributeValueMemberS{Value: "c"},
+			}}},
+			{expr: `match(alpha, "^a.*a$")`, expected: &types.AttributeValueMemberBOOL{Value: true}},
+			{expr: `match(alpha, "^b")`, expected: &types.AttributeValueMemberBOOL{Value: false}},
+			{expr: `attribute_type(alpha, "S")`, expected: &types.AttributeValueMemberBOOL{Value: true}},
+			{expr: `attribute_type(alpha, "N")`, expected: &types.AttributeValueMemberBOOL{Value: false}},
+
+			// Time functions
+			{expr: `now()`, expected: &types.AttributeValueMemberN{Value: fmt.Sprint(timeNow.Unix())}},
+			{expr: `parse_time("2023-04-05T06:07:08Z")`, expected: &types.AttributeValueMemberN{Value: "1680674828"}},
+			{expr: `parse_time("2023-04-05", "2006-01-02")`, expected: &types.AttributeValueMemberN{Value: "1680652800"}},
+			{expr: `format_time(1680674828)`, expected: &types.AttributeValueMemberS{Value: "2023-04-05T06:07:08Z"}},
+			{expr: `format_time(1680674828, "2006-01-02")`, expected: &types.AttributeValueMemberS{Value: "2023-04-05"}},
+			{expr: `unix("2023-04-05T06:07:08Z")`, expected: &types.AttributeValueMemberN{Value: "1680674828"}},
+			{expr: `unix(1680674828)`, expected: &types.AttributeValueMemberN{Value: "1680674828"}},
+
+			// Other functions
+			{expr: `coalesce(missing, alpha)`, expected: &types.AttributeValueMemberS{Value: "alpha"}},
+			{expr: `coalesce(missing, charlie.nothing, 123)`, expected: &types.AttributeValueMemberN{Value: "123"}},
+			{expr: `keys(charlie)`, expected: &types.AttributeValueMemberL{Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "door"},
+				&types.AttributeValueMemberS{Value: "tree"},
+			}}},
+			{expr: `values(charlie)`, expected: &types.AttributeValueMemberL{Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "red"},
+				&types.AttributeValueMemberS{Value: "green"},
+			}}},
+			{expr: `json_decode("{\"a\": [1, \"two\"]}").a[1]`, expected: &types.AttributeValueMemberS{Value: "two"}},
+			{expr: `size(uuid())`, expected: &types.AttributeValueMemberN{Value: "36"}},
 		}
 		for _, scenario := range scenarios {
 			t.Run(scenario.expr, func(t *testing.T) {
@@ -678,6 +1092,10 @@ func TestQueryExpr_EvalItem(t *testing.T) {
 			{expr: `missing="no"`, expectedError: queryexpr.ValuesNotComparable{Right: &types.AttributeValueMemberS{Value: "no"}}},
 			{expr: `missing!="no"`, expectedError: queryexpr.ValuesNotComparable{Right: &types.AttributeValueMemberS{Value: "no"}}},
 			{expr: `missing^="no"`, expectedError: queryexpr.ValueNotConvertableToString{nil}},
+
+			{expr: `alpha - 1`, expectedError: queryexpr.OperatorNotApplicableError{Op: "-", Left: "S", Right: "N"}},
+			{expr: `missing + 1`, expectedError: queryexpr.OperatorNotApplicableError{Op: "+", Left: "UNDEFINED", Right: "N"}},
+			{expr: `bravo / 0`, expectedError: queryexpr.DivideByZeroError{}},
 		}
 
 		for _, scenario := range scenarios {
@@ -723,6 +1141,63 @@ func TestQueryExpr_EvalItem(t *testing.T) {
 	})
 }
 
+func TestQueryExpr_MatchItem(t *testing.T) {
+	var (
+		item = models.Item{
+			"status": &types.AttributeValueMemberS{Value: "open"},
+			"tags": &types.AttributeValueMemberL{
+				Value: []types.AttributeValue{
+					&types.AttributeValueMemberS{Value: "red"},
+					&types.AttributeValueMemberS{Value: "green"},
+					&types.AttributeValueMemberS{Value: "blue"},
+				},
+			},
+		}
+	)
+
+	t.Run("should match predicates against the item", func(t *testing.T) {
+		scenarios := []struct {
+			expr     string
+			expected bool
+		}{
+			{expr: `status = "open"`, expected: true},
+			{expr: `status = "closed"`, expected: false},
+			{expr: `status = "open" and size(tags) > 2`, expected: true},
+			{expr: `status = "open" and size(tags) > 3`, expected: false},
+			{expr: `not (status = "open")`, expected: false},
+			{expr: `contains(tags, "green")`, expected: true},
+			{expr: `missing is "S"`, expected: false},
+			{expr: `missing and status = "open"`, expected: false},
+			{expr: `status or size(tags) > 3`, expected: true},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.expr, func(t *testing.T) {
+				modExpr, err := queryexpr.Parse(scenario.expr)
+				assert.NoError(t, err)
+				assert.True(t, modExpr.IsPredicate())
+
+				res, err := modExpr.MatchItem(item)
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.expected, res)
+			})
+		}
+	})
+
+	t.Run("should not treat names, values or arithmetic as predicates", func(t *testing.T) {
+		scenarios := []string{`open`, `"open"`, `123`, `2023-01`, `status.thing`, `(status)`, `tags[0]`,
+			`john and jane`, `open or closed`, `not open`, `(john and jane) or bob`}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario, func(t *testing.T) {
+				modExpr, err := queryexpr.Parse(scenario)
+				assert.NoError(t, err)
+				assert.False(t, modExpr.IsPredicate())
+			})
+		}
+	})
+}
+
 func TestQueryExpr_SetEvalItem(t *testing.T) {
 	var templateItem = func() models.Item {
 		return models.Item{
@@ -869,6 +1344,67 @@ func TestQueryExpr_DeleteAttribute(t *testing.T) {
 	//})
 }
 
+func TestQueryExpr_UpdateValue(t *testing.T) {
+	tableInfo := &models.TableInfo{
+		Name: "test",
+		Keys: models.KeyAttribute{
+			PartitionKey: "pk",
+			SortKey:      "sk",
+		},
+	}
+
+	t.Run("should return operand usable in a set action", func(t *testing.T) {
+		scenarios := []struct {
+			expr           string
+			expectedUpdate string
+			expectedValue  types.AttributeValue
+		}{
+			{expr: `123`, expectedUpdate: "SET #0 = :0", expectedValue: &types.AttributeValueMemberN{Value: "123"}},
+			{expr: `60 * 60`, expectedUpdate: "SET #0 = :0", expectedValue: &types.AttributeValueMemberN{Value: "3600"}},
+			{expr: `"a" + "b"`, expectedUpdate: "SET #0 = :0", expectedValue: &types.AttributeValueMemberS{Value: "ab"}},
+			{expr: `counter + 1`, expectedUpdate: "SET #0 = #1 + :0", expectedValue: &types.AttributeValueMemberN{Value: "1"}},
+			{expr: `counter - 60 * 2`, expectedUpdate: "SET #0 = #1 - :0", expectedValue: &types.AttributeValueMemberN{Value: "120"}},
+			{expr: `12345678.9012 + 0`, expectedUpdate: "SET #0 = :0", expectedValue: &types.AttributeValueMemberN{Value: "12345678.9012"}},
+			{expr: `10 / 3`, expectedUpdate: "SET #0 = :0", expectedValue: &types.AttributeValueMemberN{Value: "3.3333333333333333333333333333333333333"}},
+			{expr: `counter + 0.1`, expectedUpdate: "SET #0 = #1 + :0", expectedValue: &types.AttributeValueMemberN{Value: "0.1"}},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.expr, func(t *testing.T) {
+				modExpr, err := queryexpr.Parse(scenario.expr)
+				assert.NoError(t, err)
+
+				opr, err := modExpr.UpdateValue(tableInfo)
+				assert.NoError(t, err)
+
+				expr, err := expression.NewBuilder().WithUpdate(expression.Set(expression.Name("target"), opr)).Build()
+				assert.NoError(t, err)
+
+				assert.Equal(t, scenario.expectedUpdate, strings.TrimSpace(aws.ToString(expr.Update())))
+				assert.Equal(t, scenario.expectedValue, expr.Values()[":0"])
+			})
+		}
+	})
+
+	t.Run("should return error if the expression cannot be used in a set action", func(t *testing.T) {
+		scenarios := []string{
+			`counter * 2`,
+			`counter + "suffix"`,
+			`alpha = "value"`,
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario, func(t *testing.T) {
+				modExpr, err := queryexpr.Parse(scenario)
+				assert.NoError(t, err)
+
+				_, err = modExpr.UpdateValue(tableInfo)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
 func TestQueryExpr_SerializeTo(t *testing.T) {
 	t.Run("should be able to serialized and deseralize the parsed expression", func(t *testing.T) {
 		exprStr := `something = $value and :placeholder = "something else" and thirdThing in (1,2,3)`
@@ -922,6 +1458,22 @@ func TestQueryExpr_SerializeTo(t *testing.T) {
 		assert.Equal(t, "banana", newExpr.ValueParamOrNil("dict").(*types.AttributeValueMemberM).Value["bravo"].(*types.AttributeValueMemberS).Value)
 		assert.Equal(t, "cherry", newExpr.ValueParamOrNil("dict").(*types.AttributeValueMemberM).Value["charlie"].(*types.AttributeValueMemberS).Value)
 	})
+
+	t.Run("should keep the query options of the expression", func(t *testing.T) {
+		modExpr, err := queryexpr.Parse(`pk="abc" using desc, limit(20)`)
+		assert.NoError(t, err)
+
+		bts := new(bytes.Buffer)
+		assert.NoError(t, modExpr.SerializeTo(bts))
+
+		newExpr, err := queryexpr.DeserializeFrom(bts)
+		assert.NoError(t, err)
+
+		plan, err := newExpr.Plan(&models.TableInfo{Keys: models.KeyAttribute{PartitionKey: "pk", SortKey: "sk"}})
+		assert.NoError(t, err)
+		assert.True(t, plan.ReadOptions.Descending)
+		assert.Equal(t, 20, plan.Limit)
+	})
 }
 
 func TestQueryExpr_Equals(t *testing.T) {
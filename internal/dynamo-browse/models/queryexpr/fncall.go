@@ -40,6 +40,11 @@ func (a *astFunctionCall) evalToIR(ctx *evalContext, info *models.TableInfo) (ir
 			return nil, OperandNotANameError(a.Args[0].String())
 		}
 		return irSizeFn{name}, nil
+	case "contains", "begins_with", "attribute_type":
+		// These have native DynamoDB equivalents when called over an attribute
+		if ir, err := nativeConditionFnIR(a.String(), nameIr.keyName(), irNodes); ir != nil || err != nil {
+			return ir, err
+		}
 	}
 
 	builtinFn, hasBuiltin := nativeFuncs[nameIr.keyName()]
@@ -134,6 +139,71 @@ func (a *astFunctionCall) String() string {
 	return sb.String()
 }
 
+// nativeConditionFnIR returns the IR node of a function which has an equivalent DynamoDB condition function.  If the
+// first argument is not an attribute name, nil is returned and the function is to be evaluated as a regular function.
+// DynamoDB conditions can only check whether an attribute contains, or begins with, a string literal.  For any other
+// operand, the returned node is evaluated against the items once they're read.
+func nativeConditionFnIR(text string, fnName string, irNodes []irAtom) (irAtom, error) {
+	if len(irNodes) != 2 {
+		return nil, InvalidArgumentNumberError{Name: fnName, Expected: 2, Actual: len(irNodes)}
+	}
+
+	name, isName := irNodes[0].(nameIRAtom)
+	if !isName {
+		return nil, nil
+	}
+
+	var strValue stringableExprValue
+	value, isValue := irNodes[1].(irValue)
+	if isValue {
+		strValue, _ = value.exprValue().(stringableExprValue)
+	}
+
+	switch fnName {
+	case "contains":
+		if strValue == nil {
+			return irItemCondition{text: text}, nil
+		}
+		return irContainsFn{name: name, value: strValue.asString()}, nil
+	case "begins_with":
+		if strValue == nil {
+			return irItemCondition{text: text}, nil
+		}
+		return irFieldBeginsWith{name: name, value: value}, nil
+	case "attribute_type":
+		if !isValue {
+			return nil, ValueMustBeLiteralError{}
+		} else if strValue == nil {
+			return nil, ValueMustBeStringError{}
+		}
+		typeInfo, isValidType := validIsTypeNames[strings.ToUpper(strValue.asString())]
+		if !isValidType {
+			return nil, InvalidTypeForIsError{TypeName: strValue.asString()}
+		}
+		return irIs{name: name, typeInfo: typeInfo}, nil
+	}
+	return nil, UnrecognisedFunctionError{Name: fnName}
+}
+
+type irContainsFn struct {
+	name  nameIRAtom
+	value string
+}
+
+func (i irContainsFn) calcQueryForScan(info *models.TableInfo) (expression.ConditionBuilder, error) {
+	return i.name.calcName(info).Contains(i.value), nil
+}
+
+// irItemCondition is a condition which cannot be expressed as a DynamoDB condition.  Terms with this condition are
+// evaluated against the items once they're read.
+type irItemCondition struct {
+	text string
+}
+
+func (i irItemCondition) calcQueryForScan(info *models.TableInfo) (expression.ConditionBuilder, error) {
+	return expression.ConditionBuilder{}, ConditionNotSupportedError{Expr: i.text}
+}
+
 type irSizeFn struct {
 	arg nameIRAtom
 }
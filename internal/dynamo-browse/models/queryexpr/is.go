@@ -57,6 +57,23 @@ var validIsTypeNames = map[string]isTypeInfo{
 	},
 }
 
+// isTypeOf returns true if the value is of the type described by this type info.
+func (ti isTypeInfo) isTypeOf(val exprValue) bool {
+	if val == nil {
+		return false
+	} else if ti.isAny {
+		return val != undefinedExprValue{}
+	}
+
+	valType := reflect.TypeOf(val)
+	for _, t := range ti.goTypes {
+		if t.AssignableTo(valType) {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *astIsOp) evalToIR(ctx *evalContext, info *models.TableInfo) (irAtom, error) {
 	leftIR, err := a.Ref.evalToIR(ctx, info)
 	if err != nil {
@@ -125,19 +142,7 @@ func (a *astIsOp) evalItem(ctx *evalContext, item models.Item) (exprValue, error
 		return nil, InvalidTypeForIsError{TypeName: str.asString()}
 	}
 
-	var resultOfIs bool
-	if typeInfo.isAny {
-		resultOfIs = ref != undefinedExprValue{}
-	} else {
-		refType := reflect.TypeOf(ref)
-
-		for _, t := range typeInfo.goTypes {
-			if t.AssignableTo(refType) {
-				resultOfIs = true
-				break
-			}
-		}
-	}
+	resultOfIs := typeInfo.isTypeOf(ref)
 	if a.HasNot {
 		resultOfIs = !resultOfIs
 	}
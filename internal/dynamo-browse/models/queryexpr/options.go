@@ -0,0 +1,102 @@
+package queryexpr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/pkg/errors"
+)
+
+// queryOptions are the options set in the 'using' clause of the expression
+type queryOptions struct {
+	scan        bool
+	index       string
+	limit       int
+	readOptions models.ReadOptions
+	projection  *expression.ProjectionBuilder
+}
+
+func (a *astExpr) queryOptions(ctx *evalContext, info *models.TableInfo) (queryOptions, error) {
+	var opts queryOptions
+
+	for _, opt := range a.Options {
+		switch {
+		case opt.Scan:
+			opts.scan = true
+		case opt.Index != "":
+			index, err := strconv.Unquote(opt.Index)
+			if err != nil {
+				return queryOptions{}, err
+			}
+			opts.index = index
+		case opt.Desc:
+			opts.readOptions.Descending = true
+		case opt.Consistent:
+			opts.readOptions.ConsistentRead = true
+		case opt.Limit != nil:
+			if *opt.Limit <= 0 {
+				return queryOptions{}, errors.New("limit must be greater than zero")
+			}
+			opts.limit = int(*opt.Limit)
+		case len(opt.Select) > 0:
+			proj, err := opt.calcProjection(ctx, info)
+			if err != nil {
+				return queryOptions{}, err
+			}
+			opts.projection = &proj
+		}
+	}
+
+	return opts, nil
+}
+
+// calcProjection returns the projection of the selected attributes.  The key attributes of the table are always
+// included, so that the items read remain modifiable.
+func (opt *astOption) calcProjection(ctx *evalContext, info *models.TableInfo) (expression.ProjectionBuilder, error) {
+	var (
+		names     = make([]expression.NameBuilder, 0, len(opt.Select)+2)
+		seenNames = make(map[string]bool)
+	)
+	for _, sel := range opt.Select {
+		ir, err := sel.evalToIR(ctx, info)
+		if err != nil {
+			return expression.ProjectionBuilder{}, err
+		}
+
+		nameIR, isNameIR := ir.(nameIRAtom)
+		if !isNameIR {
+			return expression.ProjectionBuilder{}, OperandNotANameError(sel.String())
+		}
+		names = append(names, nameIR.calcName(info))
+		seenNames[nameIR.keyName()] = true
+	}
+
+	for _, key := range []string{info.Keys.PartitionKey, info.Keys.SortKey} {
+		if key != "" && !seenNames[key] {
+			names = append(names, expression.Name(key))
+		}
+	}
+
+	return expression.NamesList(names[0], names[1:]...), nil
+}
+
+func (opt *astOption) String() string {
+	switch {
+	case opt.Scan:
+		return "scan"
+	case opt.Index != "":
+		return "index(" + opt.Index + ")"
+	case opt.Desc:
+		return "desc"
+	case opt.Consistent:
+		return "consistent"
+	case opt.Limit != nil:
+		return "limit(" + strconv.FormatInt(*opt.Limit, 10) + ")"
+	case len(opt.Select) > 0:
+		return "select(" + strings.Join(sliceutils.Map(opt.Select, (*astSubRef).String), ", ") + ")"
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package queryexpr
+
+import "github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+
+// IsPredicate returns true if the expression is a predicate over an item, which is an expression containing a
+// comparison or a function call.  Expressions of names and values, like "open" or "john and jane", are not
+// predicates.
+func (md *QueryExpr) IsPredicate() bool {
+	return md.ast.Root.isPredicate()
+}
+
+// MatchItem returns true if the expression evaluates to a truthy value against the item.
+func (md *QueryExpr) MatchItem(item models.Item) (bool, error) {
+	return md.ast.matchItem(md.evalContext(), item)
+}
+
+func (a *astDisjunction) isPredicate() bool {
+	for _, op := range a.Operands {
+		if op.isPredicate() {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *astConjunction) isPredicate() bool {
+	for _, op := range a.Operands {
+		if op.isPredicate() {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *astBooleanNot) isPredicate() bool {
+	return a.Operand.isPredicate()
+}
+
+func (a *astIn) isPredicate() bool {
+	return len(a.Operand) > 0 || a.SingleOperand != nil || a.Ref.isPredicate()
+}
+
+func (a *astComparisonOp) isPredicate() bool {
+	return a.Op != "" || a.Ref.isPredicate()
+}
+
+func (a *astBetweenOp) isPredicate() bool {
+	return a.From != nil || a.Ref.isPredicate()
+}
+
+func (a *astEqualityOp) isPredicate() bool {
+	return a.Op != "" || a.Ref.isPredicate()
+}
+
+func (a *astIsOp) isPredicate() bool {
+	if a.Value != nil {
+		return true
+	}
+
+	// Arithmetic operators do not produce a boolean, so anything below them is not a predicate
+	if len(a.Ref.Operands) > 0 || len(a.Ref.Ref.Operands) > 0 || a.Ref.Ref.Ref.Op != "" {
+		return false
+	}
+
+	subRef := a.Ref.Ref.Ref.Value
+	if len(subRef.SubRefs) > 0 {
+		return false
+	} else if subRef.Ref.IsCall {
+		return true
+	} else if subRef.Ref.Caller.Paren != nil {
+		return subRef.Ref.Caller.Paren.Root.isPredicate()
+	}
+	return false
+}
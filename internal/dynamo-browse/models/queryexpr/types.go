@@ -1,11 +1,14 @@
 package queryexpr
 
 import (
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/lmika/dynamo-browse/internal/common/maputils"
 	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
 	"math/big"
 	"strconv"
 )
@@ -23,7 +26,7 @@ type stringableExprValue interface {
 
 type numberableExprValue interface {
 	exprValue
-	asBigFloat() *big.Float
+	asBigRat() *big.Rat
 	asInt() int64
 }
 
@@ -40,6 +43,7 @@ type modifiableSliceExprValue interface {
 
 type mappableExprValue interface {
 	len() int
+	keys() []string
 	hasKey(name string) bool
 	valueOf(name string) (exprValue, error)
 }
@@ -62,7 +66,7 @@ func newExprValueFromAttributeValue(ev types.AttributeValue) (exprValue, error)
 	case *types.AttributeValueMemberS:
 		return stringExprValue(xVal.Value), nil
 	case *types.AttributeValueMemberN:
-		xNumVal, _, err := big.ParseFloat(xVal.Value, 10, 63, big.ToNearestEven)
+		xNumVal, err := attrutils.ParseNumber(xVal.Value)
 		if err != nil {
 			return nil, err
 		}
@@ -129,34 +133,33 @@ func (i int64ExprValue) asInt() int64 {
 	return int64(i)
 }
 
-func (i int64ExprValue) asBigFloat() *big.Float {
-	var f big.Float
-	f.SetInt64(int64(i))
-	return &f
+func (i int64ExprValue) asBigRat() *big.Rat {
+	return new(big.Rat).SetInt64(int64(i))
 }
 
 func (s int64ExprValue) typeName() string {
 	return "N"
 }
 
+// bigNumExprValue is a number which is not an int64.  The number is held exactly, and is only rounded to the
+// precision of a DynamoDB number when converted to an attribute value.
 type bigNumExprValue struct {
-	num *big.Float
+	num *big.Rat
 }
 
 func (i bigNumExprValue) asGoValue() any {
-	return i.num
+	return attributevalue.Number(attrutils.FormatNumber(i.num))
 }
 
 func (i bigNumExprValue) asAttributeValue() types.AttributeValue {
-	return &types.AttributeValueMemberN{Value: i.num.String()}
+	return &types.AttributeValueMemberN{Value: attrutils.FormatNumber(i.num)}
 }
 
 func (i bigNumExprValue) asInt() int64 {
-	x, _ := i.num.Int64()
-	return x
+	return new(big.Int).Quo(i.num.Num(), i.num.Denom()).Int64()
 }
 
-func (i bigNumExprValue) asBigFloat() *big.Float {
+func (i bigNumExprValue) asBigRat() *big.Rat {
 	return i.num
 }
 
@@ -236,6 +239,10 @@ func (bs mapExprValue) len() int {
 	return len(bs)
 }
 
+func (bs mapExprValue) keys() []string {
+	return maps.Keys(bs)
+}
+
 func (bs mapExprValue) hasKey(name string) bool {
 	_, ok := bs[name]
 	return ok
@@ -315,6 +322,10 @@ func (bs mapProxyValue) len() int {
 	return len(bs.mapValue.Value)
 }
 
+func (bs mapProxyValue) keys() []string {
+	return maps.Keys(bs.mapValue.Value)
+}
+
 func (bs mapProxyValue) hasKey(name string) bool {
 	_, ok := bs.mapValue.Value[name]
 	return ok
@@ -389,7 +400,7 @@ func (bs numberSetProxyValue) len() int {
 }
 
 func (bs numberSetProxyValue) valueAt(i int) (exprValue, error) {
-	fs, _, err := big.ParseFloat(bs.numberSet.Value[i], 10, 63, big.ToNearestEven)
+	fs, err := attrutils.ParseNumber(bs.numberSet.Value[i])
 	if err != nil {
 		return nil, err
 	}
@@ -399,7 +410,7 @@ func (bs numberSetProxyValue) valueAt(i int) (exprValue, error) {
 
 func (bs numberSetProxyValue) setValueAt(i int, newVal exprValue) {
 	if str, isStr := newVal.(numberableExprValue); isStr {
-		bs.numberSet.Value[i] = str.asBigFloat().String()
+		bs.numberSet.Value[i] = attrutils.FormatNumber(str.asBigRat())
 	}
 }
 
@@ -3,6 +3,7 @@ package queryexpr
 import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -24,6 +25,12 @@ func (a *astLiteralValue) exprValue() (exprValue, error) {
 			return nil, errors.Wrap(err, "cannot unquote string")
 		}
 		return stringExprValue(s), nil
+	case a.NumberVal != nil:
+		n, err := attrutils.ParseNumber(*a.NumberVal)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse number")
+		}
+		return bigNumExprValue{num: n}, nil
 	case a.IntVal != nil:
 		return int64ExprValue(*a.IntVal), nil
 	case a.TrueBoolValue:
@@ -42,6 +49,8 @@ func (a *astLiteralValue) String() string {
 	switch {
 	case a.StringVal != nil:
 		return *a.StringVal
+	case a.NumberVal != nil:
+		return *a.NumberVal
 	case a.IntVal != nil:
 		return strconv.FormatInt(*a.IntVal, 10)
 	case a.TrueBoolValue:
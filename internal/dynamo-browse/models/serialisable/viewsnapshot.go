@@ -15,15 +15,26 @@ type ViewSnapshot struct {
 }
 
 type ViewSnapshotDetails struct {
+	Connection        ViewSnapshotConnection
 	TableName         string
 	Query             []byte
 	QueryHash         uint64
+	PartiQL           string
 	Filter            string
 	ExclusiveStartKey []byte
 }
 
+// ViewSnapshotConnection records the connection a view was taken with.  An empty connection is treated as
+// the current connection, which is the case for snapshots taken before connections were recorded.
+type ViewSnapshotConnection struct {
+	Profile  string
+	Region   string
+	Endpoint string
+}
+
 func (d ViewSnapshotDetails) Equals(other ViewSnapshotDetails, compareHashesOnly bool) bool {
-	return d.TableName == other.TableName &&
+	return d.Connection == other.Connection &&
+		d.TableName == other.TableName &&
 		d.Filter == other.Filter &&
 		bytes.Equal(d.ExclusiveStartKey, d.ExclusiveStartKey) &&
 		d.compareQueries(other, compareHashesOnly)
@@ -36,6 +47,9 @@ func (d ViewSnapshotDetails) compareQueries(other ViewSnapshotDetails, compareHa
 	if compareHashesOnly {
 		return true
 	}
+	if d.PartiQL != "" || other.PartiQL != "" {
+		return d.PartiQL == other.PartiQL
+	}
 
 	expr1, err := queryexpr.DeserializeFrom(bytes.NewReader(d.Query))
 	if err != nil {
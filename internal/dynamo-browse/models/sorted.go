@@ -10,6 +10,7 @@ import (
 type sortedItems struct {
 	criteria SortCriteria
 	items    []Item
+	onSwap   func(i, j int)
 }
 
 type SortField struct {
@@ -72,7 +73,7 @@ func (si *sortedItems) Len() int {
 func (si *sortedItems) Less(i, j int) bool {
 	for _, field := range si.criteria.Fields {
 		// Compare primary keys
-		pv1, pv2 := field.Field.EvaluateForItem(si.items[i]), field.Field.EvaluateForItem(si.items[j])
+		pv1, pv2 := EvaluateForItemSync(field.Field, si.items[i]), EvaluateForItemSync(field.Field, si.items[j])
 		pc, ok := attrutils.CompareScalarAttributes(pv1, pv2)
 		if !ok {
 			return i < j
@@ -95,4 +96,7 @@ func (si *sortedItems) Less(i, j int) bool {
 
 func (si *sortedItems) Swap(i, j int) {
 	si.items[j], si.items[i] = si.items[i], si.items[j]
+	if si.onSwap != nil {
+		si.onSwap(i, j)
+	}
 }
@@ -1,15 +1,66 @@
 package models
 
+import "time"
+
 type TableInfo struct {
 	Name              string
 	Keys              KeyAttribute
 	DefinedAttributes []string
 	GSIs              []TableGSI
+	LSIs              []TableLSI
+
+	// AttributeTypes maps each defined attribute to its scalar type: "S", "N" or "B"
+	AttributeTypes map[string]string
+
+	ARN           string
+	Status        string
+	Created       time.Time
+	BillingMode   string
+	ReadCapacity  int64
+	WriteCapacity int64
+
+	// ItemCount and SizeBytes are only updated by DynamoDB approximately every six hours
+	ItemCount int64
+	SizeBytes int64
+
+	Stream TableStream
+
+	// TTL and PITRStatus are only set when the table is described in full
+	TTL        TableTTL
+	PITRStatus string
 }
 
 type TableGSI struct {
-	Name string
-	Keys KeyAttribute
+	Name       string
+	Keys       KeyAttribute
+	Status     string
+	Projection TableProjection
+	ItemCount  int64
+	SizeBytes  int64
+}
+
+type TableLSI struct {
+	Name       string
+	Keys       KeyAttribute
+	Projection TableProjection
+	ItemCount  int64
+	SizeBytes  int64
+}
+
+type TableProjection struct {
+	Type             string
+	NonKeyAttributes []string
+}
+
+type TableTTL struct {
+	Status        string
+	AttributeName string
+}
+
+type TableStream struct {
+	Enabled  bool
+	ViewType string
+	ARN      string
 }
 
 func (ti *TableInfo) Equal(other *TableInfo) bool {
@@ -0,0 +1,98 @@
+package dynamo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/jobs"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxKeysPerBatchGet = 100
+
+	// maxBatchGetRetries is the number of times unprocessed keys are retried before giving up
+	maxBatchGetRetries = 8
+)
+
+// BatchGetItems returns the items with the given keys.  Keys are fetched in batches of 100, with any unprocessed
+// keys retried with an exponential backoff.  Keys which do not exist in the table are ignored, and duplicate
+// keys are only fetched once.
+func (p *Provider) BatchGetItems(ctx context.Context, tableName string, keys []map[string]types.AttributeValue, readOptions models.ReadOptions) ([]models.Item, error) {
+	var (
+		items      = make([]models.Item, 0)
+		nextUpdate = time.Now().Add(1 * time.Second)
+		uniqueKeys = dedupKeys(keys)
+	)
+
+	for s := 0; s < len(uniqueKeys); s += maxKeysPerBatchGet {
+		f := s + maxKeysPerBatchGet
+		if f > len(uniqueKeys) {
+			f = len(uniqueKeys)
+		}
+
+		requestItems := map[string]types.KeysAndAttributes{
+			tableName: {
+				Keys:           uniqueKeys[s:f],
+				ConsistentRead: aws.Bool(readOptions.ConsistentRead),
+			},
+		}
+		for retries := 0; len(requestItems) > 0; retries++ {
+			if retries > maxBatchGetRetries {
+				return nil, errors.Errorf("unable to get all items from %v: too many unprocessed keys", tableName)
+			} else if retries > 0 {
+				select {
+				case <-time.After(time.Duration(1<<(retries-1)) * 50 * time.Millisecond):
+				case <-ctx.Done():
+					return items, models.NewPartialResultsError(ctx.Err())
+				}
+			}
+
+			out, err := p.dynamoClient().BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: requestItems,
+			})
+			if err != nil {
+				if ctx.Err() != nil {
+					return items, models.NewPartialResultsError(ctx.Err())
+				}
+				return nil, errors.Wrapf(err, "unable to get items from %v", tableName)
+			}
+
+			for _, itm := range out.Responses[tableName] {
+				items = append(items, itm)
+			}
+			requestItems = out.UnprocessedKeys
+		}
+
+		if time.Now().After(nextUpdate) {
+			jobs.PostUpdate(ctx, fmt.Sprintf("found %d items", len(items)))
+			nextUpdate = time.Now().Add(1 * time.Second)
+		}
+	}
+
+	return items, nil
+}
+
+func dedupKeys(keys []map[string]types.AttributeValue) []map[string]types.AttributeValue {
+	seenKeys := make(map[uint64][]map[string]types.AttributeValue)
+	uniqueKeys := make([]map[string]types.AttributeValue, 0, len(keys))
+
+nextKey:
+	for _, key := range keys {
+		h := attrutils.HashCode(&types.AttributeValueMemberM{Value: key})
+		for _, seenKey := range seenKeys[h] {
+			if attrutils.Equals(&types.AttributeValueMemberM{Value: seenKey}, &types.AttributeValueMemberM{Value: key}) {
+				continue nextKey
+			}
+		}
+		seenKeys[h] = append(seenKeys[h], key)
+		uniqueKeys = append(uniqueKeys, key)
+	}
+	return uniqueKeys
+}
@@ -0,0 +1,33 @@
+package dynamo
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/pkg/errors"
+)
+
+// NewClient creates a new DynamoDB client for the connection.  The returned connection has the region set to
+// the region resolved from the AWS config, if it was not set explicitly.
+func NewClient(ctx context.Context, conn models.Connection) (*dynamodb.Client, models.Connection, error) {
+	var opts []func(*config.LoadOptions) error
+	if conn.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(conn.Profile))
+	}
+	if conn.Region != "" {
+		opts = append(opts, config.WithRegion(conn.Region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, models.Connection{}, errors.Wrapf(err, "cannot load AWS config for %v", conn)
+	}
+	conn.Region = cfg.Region
+
+	if conn.Endpoint != "" {
+		return dynamodb.NewFromConfig(cfg, dynamodb.WithEndpointResolver(dynamodb.EndpointResolverFromURL(conn.Endpoint))), conn, nil
+	}
+	return dynamodb.NewFromConfig(cfg), conn, nil
+}
@@ -9,21 +9,82 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/jobs"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	"log"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
+const maxItemsPerPage = 100
+
 type Provider struct {
-	client *dynamodb.Client
+	mutex      *sync.RWMutex
+	client     *dynamodb.Client
+	connection models.Connection
 }
 
 func NewProvider(client *dynamodb.Client) *Provider {
-	return &Provider{client: client}
+	return &Provider{mutex: new(sync.RWMutex), client: client}
+}
+
+// NewProviderForConnection creates a new provider with a client connected using the connection details.
+func NewProviderForConnection(ctx context.Context, conn models.Connection) (*Provider, error) {
+	p := &Provider{mutex: new(sync.RWMutex)}
+	if err := p.Connect(ctx, conn); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Connect replaces the client used by the provider with one connected using the connection details.
+func (p *Provider) Connect(ctx context.Context, conn models.Connection) error {
+	client, resolvedConn, err := NewClient(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.client = client
+	p.connection = resolvedConn
+	return nil
+}
+
+// Connection returns the details of the current connection.
+func (p *Provider) Connection() models.Connection {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.connection
+}
+
+func (p *Provider) dynamoClient() *dynamodb.Client {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.client
 }
 
 func (p *Provider) ListTables(ctx context.Context) ([]string, error) {
-	out, err := p.client.ListTables(ctx, &dynamodb.ListTablesInput{})
+	return listTables(ctx, p.dynamoClient())
+}
+
+// ListTablesOfConnection lists the tables using a client connected using the connection details.  The client used
+// by the provider is left unchanged.
+func (p *Provider) ListTablesOfConnection(ctx context.Context, conn models.Connection) ([]string, error) {
+	client, _, err := NewClient(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	return listTables(ctx, client)
+}
+
+func listTables(ctx context.Context, client *dynamodb.Client) ([]string, error) {
+	out, err := client.ListTables(ctx, &dynamodb.ListTablesInput{})
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot list tables")
 	}
@@ -32,7 +93,7 @@ func (p *Provider) ListTables(ctx context.Context) ([]string, error) {
 }
 
 func (p *Provider) DescribeTable(ctx context.Context, tableName string) (*models.TableInfo, error) {
-	out, err := p.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+	out, err := p.dynamoClient().DescribeTable(ctx, &dynamodb.DescribeTableInput{
 		TableName: aws.String(tableName),
 	})
 	if err != nil {
@@ -42,22 +103,109 @@ func (p *Provider) DescribeTable(ctx context.Context, tableName string) (*models
 	var tableInfo models.TableInfo
 	tableInfo.Name = aws.ToString(out.Table.TableName)
 	tableInfo.Keys = p.keySchemaToKeyAttributes(out.Table.KeySchema)
+	tableInfo.ARN = aws.ToString(out.Table.TableArn)
+	tableInfo.Status = string(out.Table.TableStatus)
+	tableInfo.Created = aws.ToTime(out.Table.CreationDateTime)
+	tableInfo.ItemCount = aws.ToInt64(out.Table.ItemCount)
+	tableInfo.SizeBytes = aws.ToInt64(out.Table.TableSizeBytes)
+
+	tableInfo.BillingMode = string(types.BillingModeProvisioned)
+	if bms := out.Table.BillingModeSummary; bms != nil && bms.BillingMode != "" {
+		tableInfo.BillingMode = string(bms.BillingMode)
+	}
+	if pt := out.Table.ProvisionedThroughput; pt != nil {
+		tableInfo.ReadCapacity = aws.ToInt64(pt.ReadCapacityUnits)
+		tableInfo.WriteCapacity = aws.ToInt64(pt.WriteCapacityUnits)
+	}
+
+	if ss := out.Table.StreamSpecification; ss != nil {
+		tableInfo.Stream = models.TableStream{
+			Enabled:  aws.ToBool(ss.StreamEnabled),
+			ViewType: string(ss.StreamViewType),
+			ARN:      aws.ToString(out.Table.LatestStreamArn),
+		}
+	}
 
 	tableInfo.GSIs = make([]models.TableGSI, len(out.Table.GlobalSecondaryIndexes))
 	for i, gsiIndex := range out.Table.GlobalSecondaryIndexes {
 		tableInfo.GSIs[i] = models.TableGSI{
-			Name: aws.ToString(gsiIndex.IndexName),
-			Keys: p.keySchemaToKeyAttributes(gsiIndex.KeySchema),
+			Name:       aws.ToString(gsiIndex.IndexName),
+			Keys:       p.keySchemaToKeyAttributes(gsiIndex.KeySchema),
+			Status:     string(gsiIndex.IndexStatus),
+			Projection: p.projectionToTableProjection(gsiIndex.Projection),
+			ItemCount:  aws.ToInt64(gsiIndex.ItemCount),
+			SizeBytes:  aws.ToInt64(gsiIndex.IndexSizeBytes),
 		}
 	}
 
+	tableInfo.LSIs = make([]models.TableLSI, len(out.Table.LocalSecondaryIndexes))
+	for i, lsiIndex := range out.Table.LocalSecondaryIndexes {
+		// LSIs always share the partition key of the table
+		lsiKeys := p.keySchemaToKeyAttributes(lsiIndex.KeySchema)
+		lsiKeys.PartitionKey = tableInfo.Keys.PartitionKey
+
+		tableInfo.LSIs[i] = models.TableLSI{
+			Name:       aws.ToString(lsiIndex.IndexName),
+			Keys:       lsiKeys,
+			Projection: p.projectionToTableProjection(lsiIndex.Projection),
+			ItemCount:  aws.ToInt64(lsiIndex.ItemCount),
+			SizeBytes:  aws.ToInt64(lsiIndex.IndexSizeBytes),
+		}
+	}
+
+	tableInfo.AttributeTypes = make(map[string]string)
 	for _, definedAttribute := range out.Table.AttributeDefinitions {
 		tableInfo.DefinedAttributes = append(tableInfo.DefinedAttributes, aws.ToString(definedAttribute.AttributeName))
+		tableInfo.AttributeTypes[aws.ToString(definedAttribute.AttributeName)] = string(definedAttribute.AttributeType)
 	}
 
 	return &tableInfo, nil
 }
 
+// DescribeTableInFull returns the table description along with the TTL and point-in-time recovery status,
+// which are not part of the table description and require separate calls to fetch.
+func (p *Provider) DescribeTableInFull(ctx context.Context, tableName string) (*models.TableInfo, error) {
+	tableInfo, err := p.DescribeTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Not being able to get these is not fatal, as it could simply be that the user does not have permission
+	// to do so.
+	if ttlOut, err := p.dynamoClient().DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(tableName),
+	}); err == nil && ttlOut.TimeToLiveDescription != nil {
+		tableInfo.TTL = models.TableTTL{
+			Status:        string(ttlOut.TimeToLiveDescription.TimeToLiveStatus),
+			AttributeName: aws.ToString(ttlOut.TimeToLiveDescription.AttributeName),
+		}
+	} else if err != nil {
+		log.Printf("warn: cannot describe time to live of %v: %v", tableName, err)
+	}
+
+	if cbOut, err := p.dynamoClient().DescribeContinuousBackups(ctx, &dynamodb.DescribeContinuousBackupsInput{
+		TableName: aws.String(tableName),
+	}); err == nil && cbOut.ContinuousBackupsDescription != nil {
+		if pitr := cbOut.ContinuousBackupsDescription.PointInTimeRecoveryDescription; pitr != nil {
+			tableInfo.PITRStatus = string(pitr.PointInTimeRecoveryStatus)
+		}
+	} else if err != nil {
+		log.Printf("warn: cannot describe continuous backups of %v: %v", tableName, err)
+	}
+
+	return tableInfo, nil
+}
+
+func (p *Provider) projectionToTableProjection(projection *types.Projection) models.TableProjection {
+	if projection == nil {
+		return models.TableProjection{}
+	}
+	return models.TableProjection{
+		Type:             string(projection.ProjectionType),
+		NonKeyAttributes: projection.NonKeyAttributes,
+	}
+}
+
 func (p *Provider) keySchemaToKeyAttributes(keySchemaElements []types.KeySchemaElement) (keyAttribute models.KeyAttribute) {
 	for _, keySchema := range keySchemaElements {
 		if keySchema.KeyType == types.KeyTypeHash {
@@ -70,7 +218,7 @@ func (p *Provider) keySchemaToKeyAttributes(keySchemaElements []types.KeySchemaE
 }
 
 func (p *Provider) PutItem(ctx context.Context, name string, item models.Item) error {
-	_, err := p.client.PutItem(ctx, &dynamodb.PutItemInput{
+	_, err := p.dynamoClient().PutItem(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(name),
 		Item:      item,
 	})
@@ -81,58 +229,309 @@ func (p *Provider) PutItem(ctx context.Context, name string, item models.Item) e
 }
 
 func (p *Provider) PutItems(ctx context.Context, name string, items []models.Item) error {
-	return p.batchPutItems(ctx, name, items)
+	nextUpdate := time.Now().Add(1 * time.Second)
+
+	writeRequests := sliceutils.Map(items, func(item models.Item) types.WriteRequest {
+		return types.WriteRequest{PutRequest: &types.PutRequest{Item: item}}
+	})
+	return p.batchWriteItems(ctx, name, writeRequests, func(written int) {
+		if time.Now().After(nextUpdate) {
+			jobs.PostUpdate(ctx, fmt.Sprintf("updated %d items", written))
+			nextUpdate = time.Now().Add(1 * time.Second)
+		}
+	})
 }
 
-func (p *Provider) batchPutItems(ctx context.Context, name string, items []models.Item) error {
-	nextUpdate := time.Now().Add(1 * time.Second)
+// DeleteItems deletes the items with the given keys in batches.  Keys which do not exist in the table are ignored.
+func (p *Provider) DeleteItems(ctx context.Context, name string, keys []map[string]types.AttributeValue) error {
+	writeRequests := sliceutils.Map(keys, func(key map[string]types.AttributeValue) types.WriteRequest {
+		return types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}}
+	})
+	return p.batchWriteItems(ctx, name, writeRequests, nil)
+}
+
+// maxBatchWriteRetries is the number of times unprocessed items are retried before giving up
+const maxBatchWriteRetries = 8
 
-	reqs := len(items)/25 + 1
-	for rn := 0; rn < reqs; rn++ {
-		s, f := rn*25, (rn+1)*25
-		if f > len(items) {
-			f = len(items)
+// batchWriteItems sends the write requests in batches of 25, retrying any unprocessed items with an exponential
+// backoff.  If set, onBatch is called with the number of requests written after each batch.
+func (p *Provider) batchWriteItems(ctx context.Context, name string, writeRequests []types.WriteRequest, onBatch func(written int)) error {
+	for s := 0; s < len(writeRequests); s += 25 {
+		f := s + 25
+		if f > len(writeRequests) {
+			f = len(writeRequests)
 		}
 
-		itemsInThisRequest := items[s:f]
-		writeRequests := sliceutils.Map(itemsInThisRequest, func(item models.Item) types.WriteRequest {
-			return types.WriteRequest{PutRequest: &types.PutRequest{Item: item}}
-		})
+		requestItems := map[string][]types.WriteRequest{
+			name: writeRequests[s:f],
+		}
+		for retries := 0; len(requestItems) > 0; retries++ {
+			if retries > maxBatchWriteRetries {
+				return errors.Errorf("unable to write page %v of batch writes: too many unprocessed items", s/25)
+			} else if retries > 0 {
+				if err := backoff(ctx, retries); err != nil {
+					return err
+				}
+			}
 
-		_, err := p.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
-			RequestItems: map[string][]types.WriteRequest{
-				name: writeRequests,
-			},
-		})
-		if err != nil {
-			errors.Wrapf(err, "unable to put page %v of back puts", rn)
+			out, err := p.dynamoClient().BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: requestItems,
+			})
+			if err != nil {
+				return errors.Wrapf(err, "unable to write page %v of batch writes", s/25)
+			}
+			requestItems = out.UnprocessedItems
 		}
 
-		if time.Now().After(nextUpdate) {
-			jobs.PostUpdate(ctx, fmt.Sprintf("updated %d items", f))
-			nextUpdate = time.Now().Add(1 * time.Second)
+		if onBatch != nil {
+			onBatch(f)
 		}
 	}
 	return nil
 }
 
+// PutItemsConditionally puts each item one at a time, with a condition that the item in the table matches the
+// original.  Returns the indices of the puts which failed the condition.
+func (p *Provider) PutItemsConditionally(ctx context.Context, tableInfo *models.TableInfo, puts []models.ConditionalPut) ([]int, error) {
+	conds, err := putConditions(tableInfo, puts)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		failed     []int
+		nextUpdate = time.Now().Add(1 * time.Second)
+	)
+
+	for s := 0; s < len(puts); s += maxKeysPerBatchGet {
+		f := s + maxKeysPerBatchGet
+		if f > len(puts) {
+			f = len(puts)
+		}
+
+		changed, err := p.changedSinceRead(ctx, tableInfo, puts[s:f])
+		if err != nil {
+			return failed, err
+		}
+
+		for i := s; i < f; i++ {
+			if changed[i-s] {
+				failed = append(failed, i)
+				continue
+			}
+
+			_, err = p.dynamoClient().PutItem(ctx, &dynamodb.PutItemInput{
+				TableName:                 aws.String(tableInfo.Name),
+				Item:                      puts[i].Item,
+				ConditionExpression:       aws.String(conds[i].expr),
+				ExpressionAttributeNames:  conds[i].names,
+				ExpressionAttributeValues: conds[i].values,
+			})
+			if err != nil {
+				var condFailedErr *types.ConditionalCheckFailedException
+				if !errors.As(err, &condFailedErr) {
+					return failed, errors.Wrapf(err, "cannot execute put on table %v", tableInfo.Name)
+				}
+				failed = append(failed, i)
+			}
+
+			if time.Now().After(nextUpdate) {
+				jobs.PostUpdate(ctx, fmt.Sprintf("updated %d items", i+1))
+				nextUpdate = time.Now().Add(1 * time.Second)
+			}
+		}
+	}
+	return failed, nil
+}
+
+// TransactPutItems puts items in transactions of up to 100 items, with a condition that each item in the table
+// matches the original.  Items which fail the condition are removed from the transaction which is then retried.
+// Returns the indices of the puts which failed the condition.
+func (p *Provider) TransactPutItems(ctx context.Context, tableInfo *models.TableInfo, puts []models.ConditionalPut) ([]int, error) {
+	const maxItemsPerTransaction = 100
+
+	conds, err := putConditions(tableInfo, puts)
+	if err != nil {
+		return nil, err
+	}
+
+	var failed []int
+	for s := 0; s < len(puts); s += maxItemsPerTransaction {
+		f := s + maxItemsPerTransaction
+		if f > len(puts) {
+			f = len(puts)
+		}
+
+		changed, err := p.changedSinceRead(ctx, tableInfo, puts[s:f])
+		if err != nil {
+			return failed, err
+		}
+
+		pending := make([]int, 0, f-s)
+		for i := s; i < f; i++ {
+			if changed[i-s] {
+				failed = append(failed, i)
+				continue
+			}
+			pending = append(pending, i)
+		}
+
+		for len(pending) > 0 {
+			transactItems := sliceutils.Map(pending, func(i int) types.TransactWriteItem {
+				return types.TransactWriteItem{Put: &types.Put{
+					TableName:                 aws.String(tableInfo.Name),
+					Item:                      puts[i].Item,
+					ConditionExpression:       aws.String(conds[i].expr),
+					ExpressionAttributeNames:  conds[i].names,
+					ExpressionAttributeValues: conds[i].values,
+				}}
+			})
+
+			_, err := p.dynamoClient().TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+				TransactItems: transactItems,
+			})
+			if err == nil {
+				break
+			}
+
+			var cancelledErr *types.TransactionCanceledException
+			if !errors.As(err, &cancelledErr) || len(cancelledErr.CancellationReasons) != len(pending) {
+				return failed, errors.Wrapf(err, "cannot execute transaction on table %v", tableInfo.Name)
+			}
+
+			stillPending := make([]int, 0, len(pending))
+			for i, reason := range cancelledErr.CancellationReasons {
+				switch aws.ToString(reason.Code) {
+				case "ConditionalCheckFailed":
+					failed = append(failed, pending[i])
+				case "None", "":
+					stillPending = append(stillPending, pending[i])
+				default:
+					return failed, errors.Wrapf(err, "cannot execute transaction on table %v", tableInfo.Name)
+				}
+			}
+			pending = stillPending
+		}
+
+		jobs.PostUpdate(ctx, fmt.Sprintf("updated %d items", f))
+	}
+	return failed, nil
+}
+
+// changedSinceRead does a consistent batch read of the items in the table and returns, for each put, true if the
+// item no longer matches the original.  This catches attributes added to the item since it was read, which the
+// condition expression of the put cannot check for.
+func (p *Provider) changedSinceRead(ctx context.Context, tableInfo *models.TableInfo, puts []models.ConditionalPut) ([]bool, error) {
+	keyOf := func(put models.ConditionalPut) map[string]types.AttributeValue {
+		if put.Original != nil {
+			return put.Original.KeyValue(tableInfo)
+		}
+		return put.Item.KeyValue(tableInfo)
+	}
+
+	currentItems, err := p.BatchGetItems(ctx, tableInfo.Name, sliceutils.Map(puts, keyOf), models.ReadOptions{ConsistentRead: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get items from table %v", tableInfo.Name)
+	}
+
+	currentByKey := make(map[uint64][]models.Item)
+	for _, item := range currentItems {
+		h := attrutils.HashCode(&types.AttributeValueMemberM{Value: item.KeyValue(tableInfo)})
+		currentByKey[h] = append(currentByKey[h], item)
+	}
+
+	changed := make([]bool, len(puts))
+	for i, put := range puts {
+		key := &types.AttributeValueMemberM{Value: keyOf(put)}
+
+		var current models.Item
+		for _, item := range currentByKey[attrutils.HashCode(key)] {
+			if attrutils.Equals(&types.AttributeValueMemberM{Value: item.KeyValue(tableInfo)}, key) {
+				current = item
+				break
+			}
+		}
+
+		if put.Original == nil {
+			changed[i] = current != nil
+		} else {
+			changed[i] = current == nil || !current.Equals(put.Original)
+		}
+	}
+	return changed, nil
+}
+
+// maxConditionExpressionLength is the maximum length of a condition expression accepted by DynamoDB.
+const maxConditionExpressionLength = 4096
+
+type condition struct {
+	expr   string
+	names  map[string]string
+	values map[string]types.AttributeValue
+}
+
+// putConditions returns the condition expression of each put.  An error is returned if the condition of any put
+// cannot be expressed, so that no items are put without checking that they are unchanged.
+func putConditions(tableInfo *models.TableInfo, puts []models.ConditionalPut) ([]condition, error) {
+	conds := make([]condition, len(puts))
+	for i, put := range puts {
+		cond, err := putCondition(tableInfo, put)
+		if err != nil {
+			return nil, err
+		}
+		conds[i] = cond
+	}
+	return conds, nil
+}
+
+// putCondition returns a condition expression which checks that the item in the table is unchanged
+// from the original.  The condition only checks the attributes of the original, so attributes added to the
+// item after changedSinceRead was called will not fail the condition.  If the original has too many
+// attributes to fit within a condition expression, an error is returned.
+func putCondition(tableInfo *models.TableInfo, put models.ConditionalPut) (condition, error) {
+	if put.Original == nil {
+		return condition{
+			expr:  "attribute_not_exists(#k0)",
+			names: map[string]string{"#k0": tableInfo.Keys.PartitionKey},
+		}, nil
+	}
+
+	attrNames := maps.Keys(put.Original)
+	sort.Strings(attrNames)
+
+	names := make(map[string]string)
+	values := make(map[string]types.AttributeValue)
+	conds := make([]string, len(attrNames))
+	for i, attrName := range attrNames {
+		nameKey, valueKey := fmt.Sprintf("#a%d", i), fmt.Sprintf(":v%d", i)
+		names[nameKey] = attrName
+		values[valueKey] = put.Original[attrName]
+		conds[i] = nameKey + " = " + valueKey
+	}
+
+	expr := strings.Join(conds, " AND ")
+	if len(expr) > maxConditionExpressionLength {
+		return condition{}, errors.Errorf("item has too many attributes to check that it is unchanged; "+
+			"set put-mode to %v to put it without checking", models.PutModeBatch)
+	}
+	return condition{expr: expr, names: names, values: values}, nil
+}
+
 func (p *Provider) ScanItems(
 	ctx context.Context,
 	tableName string,
 	filterExpr *expression.Expression,
 	exclusiveStartKey map[string]types.AttributeValue,
 	maxItems int,
+	readOptions models.ReadOptions,
 ) ([]models.Item, map[string]types.AttributeValue, error) {
-	const maxItemsPerPage = 100
-
 	input := &dynamodb.ScanInput{
-		TableName: aws.String(tableName),
-		//Limit:             aws.Int32(int32(maxItems)),
-		//Limit:             aws.Int32(100),
-		//ExclusiveStartKey: exclusiveStartKey,
+		TableName:      aws.String(tableName),
+		ConsistentRead: aws.Bool(readOptions.ConsistentRead),
 	}
 	if filterExpr != nil {
 		input.FilterExpression = filterExpr.Filter()
+		input.ProjectionExpression = filterExpr.Projection()
 		input.ExpressionAttributeNames = filterExpr.Names()
 		input.ExpressionAttributeValues = filterExpr.Values()
 	}
@@ -152,7 +551,7 @@ func (p *Provider) ScanItems(
 		}
 		input.ExclusiveStartKey = lastEvalKey
 
-		out, err := p.client.Scan(ctx, input)
+		out, err := p.dynamoClient().Scan(ctx, input)
 		if err != nil {
 			if ctx.Err() != nil {
 				return items, nil, models.NewPartialResultsError(ctx.Err())
@@ -186,11 +585,12 @@ func (p *Provider) QueryItems(
 	filterExpr *expression.Expression,
 	exclusiveStartKey map[string]types.AttributeValue,
 	maxItems int,
+	readOptions models.ReadOptions,
 ) ([]models.Item, map[string]types.AttributeValue, error) {
-	const maxItemsPerPage = 100
-
 	input := &dynamodb.QueryInput{
-		TableName: aws.String(tableName),
+		TableName:        aws.String(tableName),
+		ScanIndexForward: aws.Bool(!readOptions.Descending),
+		ConsistentRead:   aws.Bool(readOptions.ConsistentRead),
 	}
 	if indexName != "" {
 		input.IndexName = aws.String(indexName)
@@ -198,6 +598,7 @@ func (p *Provider) QueryItems(
 	if filterExpr != nil {
 		input.KeyConditionExpression = filterExpr.KeyCondition()
 		input.FilterExpression = filterExpr.Filter()
+		input.ProjectionExpression = filterExpr.Projection()
 		input.ExpressionAttributeNames = filterExpr.Names()
 		input.ExpressionAttributeValues = filterExpr.Values()
 	}
@@ -217,7 +618,7 @@ func (p *Provider) QueryItems(
 		}
 		input.ExclusiveStartKey = lastEvalKey
 
-		out, err := p.client.Query(ctx, input)
+		out, err := p.dynamoClient().Query(ctx, input)
 		if err != nil {
 			if ctx.Err() != nil {
 				return items, nil, models.NewPartialResultsError(ctx.Err())
@@ -245,9 +646,56 @@ func (p *Provider) QueryItems(
 }
 
 func (p *Provider) DeleteItem(ctx context.Context, tableName string, key map[string]types.AttributeValue) error {
-	_, err := p.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+	_, err := p.dynamoClient().DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(tableName),
 		Key:       key,
 	})
 	return errors.Wrap(err, "could not delete item")
 }
+
+// maxThrottledRetries is the number of times a throttled request is retried before giving up
+const maxThrottledRetries = 8
+
+// UpdateItem applies the update and condition of updateExpr to the item with the given key.  Requests which are
+// throttled are retried with an exponential backoff.
+func (p *Provider) UpdateItem(ctx context.Context, tableName string, key map[string]types.AttributeValue, updateExpr expression.Expression) error {
+	for retries := 0; ; retries++ {
+		if retries > 0 {
+			if err := backoff(ctx, retries); err != nil {
+				return err
+			}
+		}
+
+		_, err := p.dynamoClient().UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(tableName),
+			Key:                       key,
+			UpdateExpression:          updateExpr.Update(),
+			ConditionExpression:       updateExpr.Condition(),
+			ExpressionAttributeNames:  updateExpr.Names(),
+			ExpressionAttributeValues: updateExpr.Values(),
+		})
+		if err == nil {
+			return nil
+		} else if !isThrottled(err) || retries >= maxThrottledRetries {
+			return errors.Wrap(err, "could not update item")
+		}
+	}
+}
+
+// backoff waits before the given retry, returning early with an error if the context is cancelled.
+func backoff(ctx context.Context, retries int) error {
+	select {
+	case <-time.After(time.Duration(1<<(retries-1)) * 50 * time.Millisecond):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func isThrottled(err error) bool {
+	var (
+		pte *types.ProvisionedThroughputExceededException
+		rle *types.RequestLimitExceeded
+	)
+	return errors.As(err, &pte) || errors.As(err, &rle)
+}
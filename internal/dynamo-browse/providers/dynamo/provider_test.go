@@ -21,7 +21,7 @@ func TestProvider_ScanItems(t *testing.T) {
 	t.Run("should return scanned items from the table", func(t *testing.T) {
 		ctx := context.Background()
 
-		items, lev, err := provider.ScanItems(ctx, tableName, nil, nil, 100)
+		items, lev, err := provider.ScanItems(ctx, tableName, nil, nil, 100, models.ReadOptions{})
 		assert.NoError(t, err)
 		assert.Nil(t, lev)
 		assert.Len(t, items, 3)
@@ -34,7 +34,61 @@ func TestProvider_ScanItems(t *testing.T) {
 	t.Run("should return error if table name does not exist", func(t *testing.T) {
 		ctx := context.Background()
 
-		items, lev, err := provider.ScanItems(ctx, "does-not-exist", nil, nil, 100)
+		items, lev, err := provider.ScanItems(ctx, "does-not-exist", nil, nil, 100, models.ReadOptions{})
+		assert.Error(t, err)
+		assert.Nil(t, lev)
+		assert.Nil(t, items)
+	})
+}
+
+func TestProvider_ScanItemsInSegments(t *testing.T) {
+	tableName := "test-table"
+
+	client := testdynamo.SetupTestTable(t, testData)
+	provider := dynamo.NewProvider(client)
+
+	t.Run("should return scanned items from all segments", func(t *testing.T) {
+		ctx := context.Background()
+
+		items, lev, err := provider.ScanItemsInSegments(ctx, tableName, nil, nil, 100, 4, models.ReadOptions{})
+		assert.NoError(t, err)
+		assert.Nil(t, lev)
+		assert.Len(t, items, 3)
+
+		assert.Contains(t, items, testdynamo.TestRecordAsItem(t, testData[0].Data[0]))
+		assert.Contains(t, items, testdynamo.TestRecordAsItem(t, testData[0].Data[1]))
+		assert.Contains(t, items, testdynamo.TestRecordAsItem(t, testData[0].Data[2]))
+	})
+
+	t.Run("should resume scan from combined resume token", func(t *testing.T) {
+		ctx := context.Background()
+
+		var (
+			allItems []models.Item
+			lev      map[string]types.AttributeValue
+		)
+		for page := 0; page < 10; page++ {
+			items, nextLev, err := provider.ScanItemsInSegments(ctx, tableName, nil, lev, 1, 3, models.ReadOptions{})
+			assert.NoError(t, err)
+
+			allItems = append(allItems, items...)
+			lev = nextLev
+			if lev == nil {
+				break
+			}
+		}
+
+		assert.Nil(t, lev)
+		assert.Len(t, allItems, 3)
+		assert.Contains(t, allItems, testdynamo.TestRecordAsItem(t, testData[0].Data[0]))
+		assert.Contains(t, allItems, testdynamo.TestRecordAsItem(t, testData[0].Data[1]))
+		assert.Contains(t, allItems, testdynamo.TestRecordAsItem(t, testData[0].Data[2]))
+	})
+
+	t.Run("should return error if table name does not exist", func(t *testing.T) {
+		ctx := context.Background()
+
+		items, lev, err := provider.ScanItemsInSegments(ctx, "does-not-exist", nil, nil, 100, 4, models.ReadOptions{})
 		assert.Error(t, err)
 		assert.Nil(t, lev)
 		assert.Nil(t, items)
@@ -81,7 +135,7 @@ func TestProvider_PutItems(t *testing.T) {
 			assert.NoError(t, err)
 
 			// Verify the data
-			readItems, lev, err := provider.ScanItems(ctx, tableName, nil, nil, scenario.maxItems+5)
+			readItems, lev, err := provider.ScanItems(ctx, tableName, nil, nil, scenario.maxItems+5, models.ReadOptions{})
 			assert.NoError(t, err)
 			assert.Nil(t, lev)
 			assert.Len(t, readItems, scenario.maxItems)
@@ -93,6 +147,107 @@ func TestProvider_PutItems(t *testing.T) {
 	}
 }
 
+func TestProvider_ConditionalPuts(t *testing.T) {
+	tableName := "test-table"
+	tableInfo := &models.TableInfo{
+		Name: tableName,
+		Keys: models.KeyAttribute{PartitionKey: "pk", SortKey: "sk"},
+	}
+
+	putFns := []struct {
+		name  string
+		putFn func(p *dynamo.Provider) func(ctx context.Context, tableInfo *models.TableInfo, puts []models.ConditionalPut) ([]int, error)
+	}{
+		{name: "conditional", putFn: func(p *dynamo.Provider) func(ctx context.Context, tableInfo *models.TableInfo, puts []models.ConditionalPut) ([]int, error) {
+			return p.PutItemsConditionally
+		}},
+		{name: "transaction", putFn: func(p *dynamo.Provider) func(ctx context.Context, tableInfo *models.TableInfo, puts []models.ConditionalPut) ([]int, error) {
+			return p.TransactPutItems
+		}},
+	}
+
+	for _, pf := range putFns {
+		t.Run(pf.name, func(t *testing.T) {
+			t.Run("should only put items which are unchanged from the original", func(t *testing.T) {
+				client := testdynamo.SetupTestTable(t, testData)
+				provider := dynamo.NewProvider(client)
+
+				ctx := context.Background()
+
+				unchangedOriginal := testdynamo.TestRecordAsItem(t, testData[0].Data[0])
+				staleOriginal := testdynamo.TestRecordAsItem(t, testData[0].Data[1])
+				staleOriginal["alpha"] = &types.AttributeValueMemberS{Value: "Stale value"}
+
+				newItem1 := unchangedOriginal.Clone()
+				newItem1["alpha"] = &types.AttributeValueMemberS{Value: "Updated 1"}
+				newItem2 := staleOriginal.Clone()
+				newItem2["alpha"] = &types.AttributeValueMemberS{Value: "Updated 2"}
+				newItem3 := models.Item{
+					"pk": &types.AttributeValueMemberS{Value: "new"},
+					"sk": &types.AttributeValueMemberS{Value: "333"},
+				}
+
+				failed, err := pf.putFn(provider)(ctx, tableInfo, []models.ConditionalPut{
+					{Item: newItem1, Original: unchangedOriginal},
+					{Item: newItem2, Original: staleOriginal},
+					{Item: newItem3},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, []int{1}, failed)
+
+				items, _, err := provider.ScanItems(ctx, tableName, nil, nil, 100, models.ReadOptions{})
+				assert.NoError(t, err)
+				assert.Len(t, items, 4)
+				assert.Contains(t, items, newItem1)
+				assert.Contains(t, items, newItem3)
+				assert.Contains(t, items, testdynamo.TestRecordAsItem(t, testData[0].Data[1]))
+			})
+
+			t.Run("should not put new items which already exist", func(t *testing.T) {
+				client := testdynamo.SetupTestTable(t, testData)
+				provider := dynamo.NewProvider(client)
+
+				ctx := context.Background()
+
+				failed, err := pf.putFn(provider)(ctx, tableInfo, []models.ConditionalPut{
+					{Item: models.Item{
+						"pk": &types.AttributeValueMemberS{Value: "abc"},
+						"sk": &types.AttributeValueMemberS{Value: "111"},
+					}},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, []int{0}, failed)
+			})
+
+			t.Run("should return error without putting items if the condition is too long", func(t *testing.T) {
+				client := testdynamo.SetupTestTable(t, testData)
+				provider := dynamo.NewProvider(client)
+
+				ctx := context.Background()
+
+				unchangedOriginal := testdynamo.TestRecordAsItem(t, testData[0].Data[0])
+				newItem1 := unchangedOriginal.Clone()
+				newItem1["alpha"] = &types.AttributeValueMemberS{Value: "Updated 1"}
+
+				largeOriginal := testdynamo.TestRecordAsItem(t, testData[0].Data[1])
+				for i := 0; i < 500; i++ {
+					largeOriginal[fmt.Sprintf("attr%d", i)] = &types.AttributeValueMemberN{Value: fmt.Sprint(i)}
+				}
+
+				_, err := pf.putFn(provider)(ctx, tableInfo, []models.ConditionalPut{
+					{Item: newItem1, Original: unchangedOriginal},
+					{Item: largeOriginal.Clone(), Original: largeOriginal},
+				})
+				assert.Error(t, err)
+
+				items, _, err := provider.ScanItems(ctx, tableName, nil, nil, 100, models.ReadOptions{})
+				assert.NoError(t, err)
+				assert.Contains(t, items, unchangedOriginal)
+			})
+		})
+	}
+}
+
 func TestProvider_DeleteItem(t *testing.T) {
 	tableName := "test-table"
 
@@ -107,7 +262,7 @@ func TestProvider_DeleteItem(t *testing.T) {
 			"sk": &types.AttributeValueMemberS{Value: "222"},
 		})
 
-		items, lev, err := provider.ScanItems(ctx, tableName, nil, nil, 100)
+		items, lev, err := provider.ScanItems(ctx, tableName, nil, nil, 100, models.ReadOptions{})
 		assert.NoError(t, err)
 		assert.Nil(t, lev)
 		assert.Len(t, items, 2)
@@ -129,7 +284,7 @@ func TestProvider_DeleteItem(t *testing.T) {
 			"sk": &types.AttributeValueMemberS{Value: "999"},
 		})
 
-		items, lev, err := provider.ScanItems(ctx, tableName, nil, nil, 100)
+		items, lev, err := provider.ScanItems(ctx, tableName, nil, nil, 100, models.ReadOptions{})
 		assert.NoError(t, err)
 		assert.Nil(t, lev)
 		assert.Len(t, items, 3)
@@ -145,7 +300,7 @@ func TestProvider_DeleteItem(t *testing.T) {
 
 		ctx := context.Background()
 
-		items, lev, err := provider.ScanItems(ctx, "does-not-exist", nil, nil, 100)
+		items, lev, err := provider.ScanItems(ctx, "does-not-exist", nil, nil, 100, models.ReadOptions{})
 		assert.Error(t, err)
 		assert.Nil(t, lev)
 		assert.Nil(t, items)
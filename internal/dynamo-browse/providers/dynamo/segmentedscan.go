@@ -0,0 +1,215 @@
+package dynamo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/jobs"
+	"github.com/pkg/errors"
+)
+
+// segmentedScanKey is the attribute of a combined resume token which holds the last evaluated key of each
+// segment of a parallel scan.  Each element is either a map, which is the key to resume the segment from, or
+// NULL if the segment has been fully scanned.
+const segmentedScanKey = "$dynamo-browse:segments"
+
+type scanSegment struct {
+	startKey map[string]types.AttributeValue
+	done     bool
+}
+
+// ScanItemsInSegments scans the table using a parallel scan across totalSegments segments, merging the results.
+// The returned last evaluated key is a combined resume token of all the segments, which can be passed back in
+// as the exclusive start key to fetch the next page.  Passing in a combined resume token will resume the scan
+// using the number of segments of the token, regardless of totalSegments.  A totalSegments of 1 or less performs
+// a regular sequential scan.
+func (p *Provider) ScanItemsInSegments(
+	ctx context.Context,
+	tableName string,
+	filterExpr *expression.Expression,
+	exclusiveStartKey map[string]types.AttributeValue,
+	maxItems int,
+	totalSegments int,
+	readOptions models.ReadOptions,
+) ([]models.Item, map[string]types.AttributeValue, error) {
+	segments, isSegmented := decodeSegmentedScanKey(exclusiveStartKey)
+	if !isSegmented {
+		if totalSegments <= 1 || exclusiveStartKey != nil {
+			return p.ScanItems(ctx, tableName, filterExpr, exclusiveStartKey, maxItems, readOptions)
+		}
+		segments = make([]scanSegment, totalSegments)
+	}
+
+	scanCtx, cancelScan := context.WithCancel(ctx)
+	defer cancelScan()
+
+	var (
+		wg           sync.WaitGroup
+		mutex        = new(sync.Mutex)
+		items        = make([]models.Item, 0)
+		errs         = make([]error, len(segments))
+		nextUpdate   = time.Now().Add(1 * time.Second)
+		doneSegments = 0
+	)
+	for _, seg := range segments {
+		if seg.done {
+			doneSegments += 1
+		}
+	}
+
+	// remainingItems returns the number of items to fetch in the next page of a segment, or 0 if enough
+	// items have been fetched.  The remaining items are spread across the segments.
+	remainingItems := func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		remaining := maxItems - len(items)
+		if remaining <= 0 {
+			return 0
+		}
+		perSegment := (remaining + len(segments) - 1) / len(segments)
+		if perSegment > maxItemsPerPage {
+			return maxItemsPerPage
+		}
+		return perSegment
+	}
+
+	addItems := func(newItems []models.Item, segmentDone bool) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		items = append(items, newItems...)
+		if segmentDone {
+			doneSegments += 1
+		}
+
+		if time.Now().After(nextUpdate) {
+			jobs.PostUpdate(ctx, fmt.Sprintf("found %d items (%d of %d segments done)", len(items), doneSegments, len(segments)))
+			nextUpdate = time.Now().Add(1 * time.Second)
+		}
+	}
+
+	for i := range segments {
+		if segments[i].done {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			seg := &segments[i]
+			for {
+				pageSize := remainingItems()
+				if pageSize == 0 {
+					return
+				}
+
+				input := &dynamodb.ScanInput{
+					TableName:         aws.String(tableName),
+					Segment:           aws.Int32(int32(i)),
+					TotalSegments:     aws.Int32(int32(len(segments))),
+					Limit:             aws.Int32(int32(pageSize)),
+					ExclusiveStartKey: seg.startKey,
+					ConsistentRead:    aws.Bool(readOptions.ConsistentRead),
+				}
+				if filterExpr != nil {
+					input.FilterExpression = filterExpr.Filter()
+					input.ProjectionExpression = filterExpr.Projection()
+					input.ExpressionAttributeNames = filterExpr.Names()
+					input.ExpressionAttributeValues = filterExpr.Values()
+				}
+
+				out, err := p.dynamoClient().Scan(scanCtx, input)
+				if err != nil {
+					// Only record the error of the segment which failed first, and not those cancelled as a result
+					if scanCtx.Err() == nil {
+						errs[i] = err
+					}
+					cancelScan()
+					return
+				}
+
+				seg.startKey = out.LastEvaluatedKey
+				seg.done = seg.startKey == nil
+
+				newItems := make([]models.Item, len(out.Items))
+				for j, itm := range out.Items {
+					newItems[j] = itm
+				}
+				addItems(newItems, seg.done)
+
+				if seg.done {
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return items, encodeSegmentedScanKey(segments), models.NewPartialResultsError(ctx.Err())
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "cannot execute scan on table %v", tableName)
+		}
+	}
+
+	return items, encodeSegmentedScanKey(segments), nil
+}
+
+// encodeSegmentedScanKey returns a combined resume token for the segments, or nil if all segments are done.
+func encodeSegmentedScanKey(segments []scanSegment) map[string]types.AttributeValue {
+	allDone := true
+	segKeys := make([]types.AttributeValue, len(segments))
+	for i, seg := range segments {
+		if seg.done {
+			segKeys[i] = &types.AttributeValueMemberNULL{Value: true}
+			continue
+		}
+
+		allDone = false
+		if seg.startKey != nil {
+			segKeys[i] = &types.AttributeValueMemberM{Value: seg.startKey}
+		} else {
+			segKeys[i] = &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}}
+		}
+	}
+
+	if allDone {
+		return nil
+	}
+	return map[string]types.AttributeValue{
+		segmentedScanKey: &types.AttributeValueMemberL{Value: segKeys},
+	}
+}
+
+func decodeSegmentedScanKey(key map[string]types.AttributeValue) ([]scanSegment, bool) {
+	segKeys, ok := key[segmentedScanKey].(*types.AttributeValueMemberL)
+	if !ok || len(key) != 1 || len(segKeys.Value) == 0 {
+		return nil, false
+	}
+
+	segments := make([]scanSegment, len(segKeys.Value))
+	for i, segKey := range segKeys.Value {
+		switch sk := segKey.(type) {
+		case *types.AttributeValueMemberM:
+			if len(sk.Value) > 0 {
+				segments[i].startKey = sk.Value
+			}
+		case *types.AttributeValueMemberNULL:
+			segments[i].done = true
+		default:
+			return nil, false
+		}
+	}
+	return segments, true
+}
@@ -0,0 +1,91 @@
+package dynamo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/jobs"
+	"github.com/pkg/errors"
+)
+
+// statementNextTokenKey is the attribute of a resume token which holds the next token of a PartiQL statement.
+const statementNextTokenKey = "$dynamo-browse:next-token"
+
+// ExecuteStatement executes a PartiQL statement, returning at most maxItems items.  The returned last evaluated key
+// is a resume token holding the next token of the statement, which can be passed back in as the exclusive start
+// key to fetch the next page.
+func (p *Provider) ExecuteStatement(
+	ctx context.Context,
+	statement string,
+	exclusiveStartKey map[string]types.AttributeValue,
+	maxItems int,
+	readOptions models.ReadOptions,
+) ([]models.Item, map[string]types.AttributeValue, error) {
+	input := &dynamodb.ExecuteStatementInput{
+		Statement:      aws.String(statement),
+		ConsistentRead: aws.Bool(readOptions.ConsistentRead),
+	}
+
+	var (
+		items      = make([]models.Item, 0)
+		nextUpdate = time.Now().Add(1 * time.Second)
+		nextToken  = decodeStatementNextToken(exclusiveStartKey)
+	)
+
+	for len(items) < maxItems {
+		remainingItemsToFetch := maxItems - len(items)
+		if remainingItemsToFetch > maxItemsPerPage {
+			input.Limit = aws.Int32(maxItemsPerPage)
+		} else {
+			input.Limit = aws.Int32(int32(remainingItemsToFetch))
+		}
+		input.NextToken = nextToken
+
+		out, err := p.dynamoClient().ExecuteStatement(ctx, input)
+		if err != nil {
+			if ctx.Err() != nil {
+				return items, nil, models.NewPartialResultsError(ctx.Err())
+			}
+			return nil, nil, errors.Wrap(err, "cannot execute statement")
+		}
+
+		for _, itm := range out.Items {
+			items = append(items, itm)
+		}
+
+		if time.Now().After(nextUpdate) {
+			jobs.PostUpdate(ctx, fmt.Sprintf("found %d items", len(items)))
+			nextUpdate = time.Now().Add(1 * time.Second)
+		}
+
+		nextToken = out.NextToken
+		if nextToken == nil {
+			// We've reached the last page
+			break
+		}
+	}
+
+	return items, encodeStatementNextToken(nextToken), nil
+}
+
+func encodeStatementNextToken(nextToken *string) map[string]types.AttributeValue {
+	if nextToken == nil {
+		return nil
+	}
+	return map[string]types.AttributeValue{
+		statementNextTokenKey: &types.AttributeValueMemberS{Value: *nextToken},
+	}
+}
+
+func decodeStatementNextToken(key map[string]types.AttributeValue) *string {
+	token, ok := key[statementNextTokenKey].(*types.AttributeValueMemberS)
+	if !ok {
+		return nil
+	}
+	return aws.String(token.Value)
+}
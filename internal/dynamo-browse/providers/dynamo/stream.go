@@ -0,0 +1,144 @@
+package dynamo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/jobs"
+	"github.com/pkg/errors"
+)
+
+// CountItems returns the number of items matching a query or scan, without reading the items themselves.
+func (p *Provider) CountItems(
+	ctx context.Context,
+	tableName string,
+	indexName string,
+	filterExpr *expression.Expression,
+	runAsQuery bool,
+	readOptions models.ReadOptions,
+) (int64, error) {
+	var count int64
+
+	// DynamoDB will not count items if a projection is set, so the items will need to be counted as they're read
+	selectCount := filterExpr == nil || filterExpr.Projection() == nil
+
+	err := p.readAllPages(ctx, tableName, indexName, filterExpr, runAsQuery, selectCount, readOptions, func(items []models.Item, pageCount int32) error {
+		count += int64(pageCount)
+		return nil
+	})
+	return count, err
+}
+
+// StreamItems reads all the items matching a query or scan, calling onPage with the items of each page read.
+// The items are not retained once onPage returns.
+func (p *Provider) StreamItems(
+	ctx context.Context,
+	tableName string,
+	indexName string,
+	filterExpr *expression.Expression,
+	runAsQuery bool,
+	readOptions models.ReadOptions,
+	onPage func(items []models.Item) error,
+) error {
+	return p.readAllPages(ctx, tableName, indexName, filterExpr, runAsQuery, false, readOptions, func(items []models.Item, pageCount int32) error {
+		return onPage(items)
+	})
+}
+
+func (p *Provider) readAllPages(
+	ctx context.Context,
+	tableName string,
+	indexName string,
+	filterExpr *expression.Expression,
+	runAsQuery bool,
+	selectCount bool,
+	readOptions models.ReadOptions,
+	onPage func(items []models.Item, pageCount int32) error,
+) error {
+	var (
+		totalCount  int64
+		nextUpdate  = time.Now().Add(1 * time.Second)
+		lastEvalKey map[string]types.AttributeValue
+	)
+
+	for {
+		var (
+			pageItems []map[string]types.AttributeValue
+			pageCount int32
+		)
+
+		if runAsQuery {
+			input := &dynamodb.QueryInput{
+				TableName:         aws.String(tableName),
+				ScanIndexForward:  aws.Bool(!readOptions.Descending),
+				ConsistentRead:    aws.Bool(readOptions.ConsistentRead),
+				ExclusiveStartKey: lastEvalKey,
+			}
+			if indexName != "" {
+				input.IndexName = aws.String(indexName)
+			}
+			if filterExpr != nil {
+				input.KeyConditionExpression = filterExpr.KeyCondition()
+				input.FilterExpression = filterExpr.Filter()
+				input.ProjectionExpression = filterExpr.Projection()
+				input.ExpressionAttributeNames = filterExpr.Names()
+				input.ExpressionAttributeValues = filterExpr.Values()
+			}
+			if selectCount {
+				input.Select = types.SelectCount
+			}
+
+			out, err := p.dynamoClient().Query(ctx, input)
+			if err != nil {
+				return errors.Wrapf(err, "cannot execute query on table %v", tableName)
+			}
+			pageItems, pageCount, lastEvalKey = out.Items, out.Count, out.LastEvaluatedKey
+		} else {
+			input := &dynamodb.ScanInput{
+				TableName:         aws.String(tableName),
+				ConsistentRead:    aws.Bool(readOptions.ConsistentRead),
+				ExclusiveStartKey: lastEvalKey,
+			}
+			if filterExpr != nil {
+				input.FilterExpression = filterExpr.Filter()
+				input.ProjectionExpression = filterExpr.Projection()
+				input.ExpressionAttributeNames = filterExpr.Names()
+				input.ExpressionAttributeValues = filterExpr.Values()
+			}
+			if selectCount {
+				input.Select = types.SelectCount
+			}
+
+			out, err := p.dynamoClient().Scan(ctx, input)
+			if err != nil {
+				return errors.Wrapf(err, "cannot execute scan on table %v", tableName)
+			}
+			pageItems, pageCount, lastEvalKey = out.Items, out.Count, out.LastEvaluatedKey
+		}
+
+		items := make([]models.Item, len(pageItems))
+		for i, itm := range pageItems {
+			items[i] = itm
+		}
+		if err := onPage(items, pageCount); err != nil {
+			return err
+		}
+
+		totalCount += int64(pageCount)
+		if time.Now().After(nextUpdate) {
+			jobs.PostUpdate(ctx, fmt.Sprintf("read %d items", totalCount))
+			nextUpdate = time.Now().Add(1 * time.Second)
+		}
+
+		if lastEvalKey == nil {
+			// We've reached the last page
+			return nil
+		}
+	}
+}
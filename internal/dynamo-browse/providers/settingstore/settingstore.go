@@ -3,6 +3,7 @@ package settingstore
 import (
 	"github.com/asdine/storm"
 	"github.com/lmika/dynamo-browse/internal/common/workspaces"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	"github.com/pkg/errors"
 	"io/fs"
 	"log"
@@ -17,8 +18,11 @@ const (
 	keyTableReadOnly     = "ro"
 	keyTableDefaultLimit = "default_limit"
 	keyScriptLookupPath  = "script_lookup_path"
+	keyPutMode           = "put_mode"
+	keyScanSegments      = "scan_segments"
 
 	defaultsDefaultLimit     = 1000
+	defaultScanSegments      = 1
 	defaultScriptLookupPaths = "${HOME}/.config/audax/dynamo-browse/scripts"
 )
 
@@ -111,6 +115,34 @@ func (c *SettingStore) SetDefaultLimit(limit int) error {
 	return errors.Wrapf(c.ws.Set(settingBucket, keyTableDefaultLimit, &limit), "cannot set default limit to %v", limit)
 }
 
+func (c *SettingStore) ScanSegments() (segments int) {
+	err := c.ws.Get(settingBucket, keyScanSegments, &segments)
+	if err != nil {
+		if !errors.Is(err, storm.ErrNotFound) {
+			log.Printf("warn: cannot get scan segments from workspace, using default value: %v", err)
+		}
+		return defaultScanSegments
+	}
+	return segments
+}
+
+func (c *SettingStore) SetScanSegments(segments int) error {
+	return errors.Wrapf(c.ws.Set(settingBucket, keyScanSegments, &segments), "cannot set scan segments to %v", segments)
+}
+
+func (c *SettingStore) PutMode() models.PutMode {
+	putMode, err := c.getStringValue(keyPutMode, string(models.PutModeBatch))
+	if err != nil {
+		log.Printf("warn: cannot get put mode from workspace, using default value: %v", err)
+		return models.PutModeBatch
+	}
+	return models.PutMode(putMode)
+}
+
+func (c *SettingStore) SetPutMode(putMode models.PutMode) error {
+	return errors.Wrapf(c.ws.Set(settingBucket, keyPutMode, string(putMode)), "cannot set put mode to %v", putMode)
+}
+
 func (c *SettingStore) getStringValue(key string, def string) (string, error) {
 	var val string
 	if err := c.ws.Get(settingBucket, key, &val); err != nil {
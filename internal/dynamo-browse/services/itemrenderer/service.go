@@ -2,14 +2,23 @@ package itemrenderer
 
 import (
 	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/itemrender"
 	"io"
+	"sync"
 	"text/tabwriter"
 )
 
+// AttributeRenderer returns the string value to display for a top-level attribute, or false if the attribute should
+// be rendered as normal.
+type AttributeRenderer func(name string, value types.AttributeValue) (string, bool)
+
 type Service struct {
 	styles styleRenderer
+
+	mutex        sync.Mutex
+	attrRenderer AttributeRenderer
 }
 
 func NewService(fileTypeStyle StyleRenderer, metaInfoStyle StyleRenderer) *Service {
@@ -21,10 +30,24 @@ func NewService(fileTypeStyle StyleRenderer, metaInfoStyle StyleRenderer) *Servi
 	}
 }
 
+// SetAttributeRenderer sets the renderer used to display top-level attributes of items.  The renderer is not used
+// for plain text.
+func (s *Service) SetAttributeRenderer(attrRenderer AttributeRenderer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.attrRenderer = attrRenderer
+}
+
 func (s *Service) RenderItem(w io.Writer, item models.Item, resultSet *models.ResultSet, plainText bool) {
 	styles := s.styles
+	var attrRenderer AttributeRenderer
 	if plainText {
 		styles = styleRenderer{plainTextStyleRenderer{}, plainTextStyleRenderer{}}
+	} else {
+		s.mutex.Lock()
+		attrRenderer = s.attrRenderer
+		s.mutex.Unlock()
 	}
 
 	tabWriter := tabwriter.NewWriter(w, 0, 1, 1, ' ', 0)
@@ -33,19 +56,50 @@ func (s *Service) RenderItem(w io.Writer, item models.Item, resultSet *models.Re
 	for _, colName := range resultSet.Columns() {
 		seenColumns[colName] = struct{}{}
 		if r := itemrender.ToRenderer(item[colName]); r != nil {
-			s.renderItem(tabWriter, "", colName, r, styles)
+			s.renderItem(tabWriter, "", colName, withAttributeRenderer(attrRenderer, colName, item[colName], r), styles)
 		}
 	}
 	for k, _ := range item {
 		if _, seen := seenColumns[k]; !seen {
 			if r := itemrender.ToRenderer(item[k]); r != nil {
-				s.renderItem(tabWriter, "", k, r, styles)
+				s.renderItem(tabWriter, "", k, withAttributeRenderer(attrRenderer, k, item[k], r), styles)
 			}
 		}
 	}
 	tabWriter.Flush()
 }
 
+// RenderDiff writes the differences between two items.  Added attributes are prefixed with "+", removed attributes
+// are prefixed with "-", and changed attributes are written as a removal followed by an addition.  Maps which
+// changed are written with a "~" and the differences of their attributes.
+func (s *Service) RenderDiff(w io.Writer, diff []itemrender.DiffItem, plainText bool) {
+	styles := s.styles
+	if plainText {
+		styles = styleRenderer{plainTextStyleRenderer{}, plainTextStyleRenderer{}}
+	}
+
+	tabWriter := tabwriter.NewWriter(w, 0, 1, 1, ' ', 0)
+	s.renderDiff(tabWriter, "", diff, styles)
+	tabWriter.Flush()
+}
+
+func (s *Service) renderDiff(w io.Writer, indent string, diff []itemrender.DiffItem, sr styleRenderer) {
+	for _, d := range diff {
+		switch {
+		case d.Type == itemrender.DiffAdded:
+			s.renderItem(w, "+ "+indent, d.Key, d.After, sr)
+		case d.Type == itemrender.DiffRemoved:
+			s.renderItem(w, "- "+indent, d.Key, d.Before, sr)
+		case d.SubItems != nil:
+			fmt.Fprintf(w, "~ %s%v\t%s\t\n", indent, d.Key, sr.fileTypeRenderer.Render(d.After.TypeName()))
+			s.renderDiff(w, indent+"  ", d.SubItems, sr)
+		default:
+			s.renderItem(w, "- "+indent, d.Key, d.Before, sr)
+			s.renderItem(w, "+ "+indent, d.Key, d.After, sr)
+		}
+	}
+}
+
 func (m *Service) renderItem(w io.Writer, prefix string, name string, r itemrender.Renderer, sr styleRenderer) {
 	fmt.Fprintf(w, "%s%v\t%s\t%s%s\n",
 		prefix, name, sr.fileTypeRenderer.Render(r.TypeName()), r.StringValue(), sr.metaInfoRenderer.Render(r.MetaInfo()))
@@ -56,6 +110,34 @@ func (m *Service) renderItem(w io.Writer, prefix string, name string, r itemrend
 	}
 }
 
+func withAttributeRenderer(attrRenderer AttributeRenderer, name string, value types.AttributeValue, r itemrender.Renderer) itemrender.Renderer {
+	if attrRenderer == nil {
+		return r
+	}
+	if str, ok := attrRenderer(name, value); ok {
+		return customRenderer{Renderer: r, value: str}
+	}
+	return r
+}
+
+// customRenderer renders an attribute with a custom string value in place of the value and any sub-items.
+type customRenderer struct {
+	itemrender.Renderer
+	value string
+}
+
+func (cr customRenderer) StringValue() string {
+	return cr.value
+}
+
+func (cr customRenderer) MetaInfo() string {
+	return ""
+}
+
+func (cr customRenderer) SubItems() []itemrender.SubItem {
+	return nil
+}
+
 type styleRenderer struct {
 	fileTypeRenderer StyleRenderer
 	metaInfoRenderer StyleRenderer
@@ -0,0 +1,127 @@
+package scriptmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/risor-io/risor"
+)
+
+// minEveryInterval is the smallest interval allowed between runs of a scheduled function.
+const minEveryInterval = 1 * time.Second
+
+type scriptEvery struct {
+	interval time.Duration
+
+	// tickFn runs the scheduled function, returning false if the function should no longer be scheduled
+	tickFn func(ctx context.Context) (bool, error)
+}
+
+// StartBackgroundScript runs the script as a background job.  Unlike ad-hoc scripts, background scripts do not wait
+// for the script scheduler and do not block other scripts from running.  Any error raised by the script is sent to
+// errChan, which is closed once the script has finished.  Cancelling the job is not reported as an error.
+//
+// Background scripts can schedule functions with ext.every, which are started as separate background jobs once
+// the script has finished.
+func (s *Service) StartBackgroundScript(ctx context.Context, filename string, errChan chan error) error {
+	code, err := s.readScript(filename, true)
+	if err != nil {
+		return errors.Wrapf(err, "cannot load script file %v", filename)
+	}
+
+	baseName := filepath.Base(filename)
+	plugin := &ScriptPlugin{
+		name:          strings.TrimSuffix(baseName, filepath.Ext(baseName)),
+		scriptService: s,
+		vmLock:        make(chan struct{}, 1),
+	}
+
+	s.sched.startBackgroundJob(baseName, func(ctx context.Context, bj *backgroundJob) error {
+		return bj.run(ctx, func(ctx context.Context) error {
+			// The script VM is halted once the context passed to it is cancelled, which would stop the scheduled
+			// functions from running.  So the job context only cancels the script while it is running, and the
+			// script context is kept once the script has finished if functions have been scheduled.
+			evalCtx, cancelEval := context.WithCancel(context.WithoutCancel(ctx))
+			stopCancel := context.AfterFunc(ctx, cancelEval)
+
+			evalCtx = ctxWithScriptEnv(evalCtx, scriptEnv{filename: baseName})
+			_, err := risor.Eval(evalCtx, code,
+				risor.WithGlobals(s.builtins()),
+				risor.WithGlobals(map[string]any{
+					"ext": (&extModule{scriptPlugin: plugin}).registerForBackgroundScript(),
+				}),
+			)
+			if !stopCancel() {
+				return ctx.Err()
+			} else if err != nil {
+				cancelEval()
+				return errors.Wrapf(err, "script %v", filename)
+			} else if len(plugin.everyJobs) == 0 {
+				cancelEval()
+				return nil
+			}
+
+			s.startEveryJobs(plugin)
+			return nil
+		})
+	}, func(err error) {
+		defer close(errChan)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			errChan <- err
+		}
+	})
+	return nil
+}
+
+// BackgroundJobs returns the background jobs which are running, or waiting to run.
+func (s *Service) BackgroundJobs() []BackgroundJob {
+	return s.sched.backgroundJobs()
+}
+
+// CancelBackgroundJob cancels the background job with the given ID.
+func (s *Service) CancelBackgroundJob(id int) error {
+	return s.sched.cancelBackgroundJob(id)
+}
+
+// startEveryJobs starts a background job for each of the functions scheduled by the plugin.  The job will call the
+// function after each interval, until the function returns false, raises an error, or the job is cancelled.  Runs
+// are skipped while another function of the plugin is running.
+func (s *Service) startEveryJobs(plugin *ScriptPlugin) {
+	for _, ej := range plugin.everyJobs {
+		ej := ej
+		jobName := fmt.Sprintf("%v: every %v", plugin.name, ej.interval)
+
+		bj := s.sched.startBackgroundJob(jobName, func(ctx context.Context, bj *backgroundJob) error {
+			for {
+				if err := bj.wait(ctx, ej.interval); err != nil {
+					return err
+				}
+
+				var again bool
+				if err := bj.run(ctx, func(ctx context.Context) (err error) {
+					again, err = ej.tickFn(ctx)
+					return err
+				}); err != nil {
+					return err
+				} else if !again {
+					return nil
+				}
+			}
+		}, func(err error) {
+			if err == nil || errors.Is(err, context.Canceled) {
+				return
+			}
+
+			log.Printf("background job '%v' failed: %v", jobName, err)
+			if s.ifaces.UI != nil {
+				s.ifaces.UI.PrintMessage(context.Background(), fmt.Sprintf("background job '%v' failed: %v", jobName, err))
+			}
+		})
+		plugin.bgJobs = append(plugin.bgJobs, bj)
+	}
+}
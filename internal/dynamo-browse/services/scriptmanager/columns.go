@@ -0,0 +1,349 @@
+package scriptmanager
+
+import (
+	"context"
+	"log"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/attrutils"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/columns"
+)
+
+const (
+	// scriptValueWaitTimeout is the maximum time to wait for the script scheduler to be free before giving up
+	// on evaluating pending values.  The values will be requested again on the next redraw.
+	scriptValueWaitTimeout = 2 * time.Second
+
+	// scriptValueBatchBudget is the time a batch of pending values can run on the script scheduler.  Values not
+	// evaluated within this time are left pending for the next batch, so that other scripts get a chance to run.
+	scriptValueBatchBudget = 250 * time.Millisecond
+
+	// scriptValueSyncTimeout is the maximum time to wait for a value evaluated for a caller which needs the value
+	// right away.  The zero value is returned if the value is not evaluated within this time.
+	scriptValueSyncTimeout = 5 * time.Second
+
+	// maxCachedScriptValues is the number of values cached by an evaluator before the cache is cleared.
+	maxCachedScriptValues = 10000
+)
+
+type scriptColumn struct {
+	name   string
+	table  string
+	evalFn func(ctx context.Context, tableInfo *models.TableInfo, item models.Item) (types.AttributeValue, error)
+}
+
+type scriptRenderer struct {
+	pattern  string
+	renderFn func(ctx context.Context, value types.AttributeValue) (string, bool, error)
+}
+
+// Columns returns the columns defined by scripts for the table.  Column values are evaluated by the scripts in the
+// background, and onUpdate is called once newly evaluated values are available to display.
+func (s *Service) Columns(tableInfo *models.TableInfo, onUpdate func()) []columns.Column {
+	var cols []columns.Column
+	for _, p := range s.plugins {
+		for _, sc := range p.definedColumns {
+			if match, _ := tableMatchesGlob(sc.table, tableInfo.Name); !match {
+				continue
+			}
+
+			evalFn := sc.evalFn
+			cols = append(cols, columns.Column{
+				Name: sc.name,
+				Evaluator: &ColumnEvaluator{
+					values: newScriptValueCache(s.sched, func(ctx context.Context, in types.AttributeValue) (types.AttributeValue, error) {
+						return evalFn(ctx, tableInfo, in.(*types.AttributeValueMemberM).Value)
+					}, onUpdate),
+				},
+			})
+		}
+	}
+	return cols
+}
+
+// AttributeRenderer returns a function which renders attribute values using the renderers defined by scripts.
+// The function returns false if no renderer matches the attribute name, or if the rendered value is not yet
+// available, in which case the value should be rendered as normal.  Values are rendered by the scripts in the
+// background, and onUpdate is called once newly rendered values are available to display.
+func (s *Service) AttributeRenderer(onUpdate func()) func(name string, value types.AttributeValue) (string, bool) {
+	type patternCache struct {
+		pattern string
+		values  *scriptValueCache[renderedValue]
+	}
+
+	var caches []patternCache
+	for _, p := range s.plugins {
+		for _, r := range p.renderers {
+			renderFn := r.renderFn
+			caches = append(caches, patternCache{
+				pattern: r.pattern,
+				values: newScriptValueCache(s.sched, func(ctx context.Context, in types.AttributeValue) (renderedValue, error) {
+					str, ok, err := renderFn(ctx, in)
+					return renderedValue{str: str, ok: ok}, err
+				}, onUpdate),
+			})
+		}
+	}
+
+	return func(name string, value types.AttributeValue) (string, bool) {
+		for _, c := range caches {
+			if match, _ := path.Match(c.pattern, name); !match {
+				continue
+			}
+
+			rv, _ := c.values.get(value)
+			return rv.str, rv.ok
+		}
+		return "", false
+	}
+}
+
+type renderedValue struct {
+	str string
+	ok  bool
+}
+
+// ColumnEvaluator evaluates the value of a column defined by a script.  As scripts can be slow, EvaluateForItem,
+// which is used to render the column, evaluates the value in the background and returns nil until it is available.
+// EvaluateForItemSync is to be used by callers which need the value right away.
+type ColumnEvaluator struct {
+	values *scriptValueCache[types.AttributeValue]
+}
+
+func (ce *ColumnEvaluator) EvaluateForItem(item models.Item) types.AttributeValue {
+	val, _ := ce.values.get(&types.AttributeValueMemberM{Value: item})
+	return val
+}
+
+// EvaluateForItemSync evaluates the value of the column on the script scheduler, waiting for it to be evaluated.
+// Returns nil if the value could not be evaluated within scriptValueSyncTimeout.
+func (ce *ColumnEvaluator) EvaluateForItemSync(item models.Item) types.AttributeValue {
+	return ce.values.getSync(&types.AttributeValueMemberM{Value: item})
+}
+
+// scriptValueCache caches values computed from an attribute value by a script.  Values not in the cache are
+// computed in batches on the script scheduler by a background goroutine.
+type scriptValueCache[T any] struct {
+	sched    *scriptScheduler
+	evalFn   func(ctx context.Context, in types.AttributeValue) (T, error)
+	onUpdate func()
+
+	mutex   sync.Mutex
+	cached  map[uint64][]*cachedScriptValue[T]
+	size    int
+	pending []*cachedScriptValue[T]
+	running bool
+}
+
+type cachedScriptValue[T any] struct {
+	hash    uint64
+	in      types.AttributeValue
+	out     T
+	pending bool
+}
+
+func newScriptValueCache[T any](
+	sched *scriptScheduler,
+	evalFn func(ctx context.Context, in types.AttributeValue) (T, error),
+	onUpdate func(),
+) *scriptValueCache[T] {
+	return &scriptValueCache[T]{
+		sched:    sched,
+		evalFn:   evalFn,
+		onUpdate: onUpdate,
+		cached:   make(map[uint64][]*cachedScriptValue[T]),
+	}
+}
+
+// get returns the value computed from in, and true if the value is available.  If the value is not in the cache,
+// it will be computed in the background.
+func (c *scriptValueCache[T]) get(in types.AttributeValue) (T, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	h := attrutils.HashCode(in)
+	for _, cv := range c.cached[h] {
+		if attrutils.Equals(cv.in, in) {
+			return cv.out, !cv.pending
+		}
+	}
+
+	if c.size >= maxCachedScriptValues {
+		c.cached = make(map[uint64][]*cachedScriptValue[T])
+		c.size = 0
+	}
+
+	// The value is cloned, as the caller is free to modify it once this returns
+	cv := &cachedScriptValue[T]{hash: h, in: attrutils.Clone(in), pending: true}
+	c.cached[h] = append(c.cached[h], cv)
+	c.size++
+	c.pending = append(c.pending, cv)
+
+	if !c.running {
+		c.running = true
+		go c.evalPending()
+	}
+
+	var zero T
+	return zero, false
+}
+
+// getSync returns the value computed from in, computing it on the script scheduler if it is not in the cache.
+// The zero value is returned if the value could not be computed within scriptValueSyncTimeout.
+func (c *scriptValueCache[T]) getSync(in types.AttributeValue) T {
+	var zero T
+
+	h := attrutils.HashCode(in)
+	c.mutex.Lock()
+	for _, cv := range c.cached[h] {
+		if attrutils.Equals(cv.in, in) && !cv.pending {
+			c.mutex.Unlock()
+			return cv.out
+		}
+	}
+	c.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), scriptValueSyncTimeout)
+	defer cancel()
+
+	type evalResult struct {
+		out T
+		err error
+	}
+
+	resChan := make(chan evalResult, 1)
+	if err := c.sched.startJobOnceFree(ctx, func(_ context.Context) {
+		out, err := c.evalFn(ctx, in)
+		resChan <- evalResult{out: out, err: err}
+	}); err != nil {
+		log.Printf("cannot evaluate script value: %v", err)
+		return zero
+	}
+
+	select {
+	case res := <-resChan:
+		if isPluginBusy(res.err) {
+			// The value is not cached, so that it is evaluated again once the script is free
+			log.Printf("cannot evaluate script value: %v", res.err)
+			return zero
+		} else if res.err != nil {
+			log.Printf("script value error: %v", res.err)
+		}
+		c.put(h, in, res.out)
+		return res.out
+	case <-ctx.Done():
+		log.Printf("cannot evaluate script value: %v", ctx.Err())
+		return zero
+	}
+}
+
+// put adds the value computed from in to the cache, replacing any pending value.
+func (c *scriptValueCache[T]) put(h uint64, in types.AttributeValue, out T) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, cv := range c.cached[h] {
+		if attrutils.Equals(cv.in, in) {
+			cv.out = out
+			cv.pending = false
+			return
+		}
+	}
+
+	if c.size >= maxCachedScriptValues {
+		c.cached = make(map[uint64][]*cachedScriptValue[T])
+		c.size = 0
+	}
+	c.cached[h] = append(c.cached[h], &cachedScriptValue[T]{hash: h, in: attrutils.Clone(in), out: out})
+	c.size++
+}
+
+func (c *scriptValueCache[T]) evalPending() {
+	for {
+		c.mutex.Lock()
+		batch := c.pending
+		c.pending = nil
+		if len(batch) == 0 {
+			c.running = false
+			c.mutex.Unlock()
+			return
+		}
+		c.mutex.Unlock()
+
+		evaluated, err := c.evalBatch(batch)
+		if err != nil {
+			// The scheduler or the script is busy, so drop the values not yet evaluated.  The values will be
+			// requested again on the next redraw.
+			log.Printf("cannot evaluate script values: %v", err)
+			c.dropPending(batch[evaluated:])
+		} else if evaluated < len(batch) {
+			c.mutex.Lock()
+			c.pending = append(batch[evaluated:], c.pending...)
+			c.mutex.Unlock()
+		}
+
+		if c.onUpdate != nil {
+			c.onUpdate()
+		}
+	}
+}
+
+// evalBatch evaluates the values of the batch on the script scheduler until the batch budget is spent, returning
+// the number of values evaluated.  If the script is busy, the values evaluated so far are returned with the error.
+func (c *scriptValueCache[T]) evalBatch(batch []*cachedScriptValue[T]) (int, error) {
+	waitCtx, cancel := context.WithTimeout(context.Background(), scriptValueWaitTimeout)
+	defer cancel()
+
+	type batchResult struct {
+		n   int
+		err error
+	}
+
+	doneChan := make(chan batchResult, 1)
+	if err := c.sched.startJobOnceFree(waitCtx, func(ctx context.Context) {
+		ctx = context.WithoutCancel(ctx)
+		deadline := time.Now().Add(scriptValueBatchBudget)
+
+		var n int
+		for n = 0; n < len(batch) && (n == 0 || time.Now().Before(deadline)); n++ {
+			out, err := c.evalFn(ctx, batch[n].in)
+			if isPluginBusy(err) {
+				doneChan <- batchResult{n: n, err: err}
+				return
+			} else if err != nil {
+				log.Printf("script value error: %v", err)
+			}
+
+			c.mutex.Lock()
+			batch[n].out = out
+			batch[n].pending = false
+			c.mutex.Unlock()
+		}
+		doneChan <- batchResult{n: n}
+	}); err != nil {
+		return 0, err
+	}
+
+	res := <-doneChan
+	return res.n, res.err
+}
+
+func (c *scriptValueCache[T]) dropPending(batch []*cachedScriptValue[T]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, cv := range batch {
+		vals := c.cached[cv.hash]
+		for i, v := range vals {
+			if v == cv {
+				c.cached[cv.hash] = append(vals[:i], vals[i+1:]...)
+				c.size--
+				break
+			}
+		}
+	}
+}
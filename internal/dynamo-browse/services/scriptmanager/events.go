@@ -0,0 +1,162 @@
+package scriptmanager
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/pkg/errors"
+	"github.com/risor-io/risor/object"
+)
+
+const (
+	EventResultSet     = "result_set"
+	EventBeforePut     = "before_put"
+	EventTableSelected = "table_selected"
+	EventItemSelected  = "item_selected"
+)
+
+var validEventNames = map[string]bool{
+	EventResultSet:     true,
+	EventBeforePut:     true,
+	EventTableSelected: true,
+	EventItemSelected:  true,
+}
+
+// eventHookTimeout is the maximum time to wait for the event hooks to start.
+const eventHookTimeout = 5 * time.Second
+
+// eventHookDeadline is the maximum time the event hooks can run for.  The context of the hooks is cancelled once
+// this time has passed, and hooks which are still running are abandoned so that they no longer hold up the
+// caller or the script scheduler.
+const eventHookDeadline = 5 * time.Second
+
+var errSchedulerBusy = errors.New("another script is running")
+
+type eventHandler func(ctx context.Context, args []object.Object) (object.Object, error)
+
+// NotifyResultSet runs the "result_set" hooks with the new result set.
+func (s *Service) NotifyResultSet(ctx context.Context, rs *models.ResultSet) error {
+	return s.notify(ctx, EventResultSet, func() []object.Object {
+		return []object.Object{newResultSetProxy(rs)}
+	})
+}
+
+// NotifyTableSelected runs the "table_selected" hooks with the newly selected table.
+func (s *Service) NotifyTableSelected(ctx context.Context, tableInfo *models.TableInfo) error {
+	return s.notify(ctx, EventTableSelected, func() []object.Object {
+		return []object.Object{&tableProxy{table: tableInfo}}
+	})
+}
+
+// NotifyItemSelected runs the "item_selected" hooks with the newly selected item.
+func (s *Service) NotifyItemSelected(ctx context.Context, rs *models.ResultSet, index int) error {
+	return s.notify(ctx, EventItemSelected, func() []object.Object {
+		return []object.Object{newItemProxy(newResultSetProxy(rs), index)}
+	})
+}
+
+// BeforePut runs the "before_put" hooks with the items about to be put.  Hooks can modify the items, and can veto
+// the put by returning false or raising an error, in which case an error is returned.  Unlike the other events,
+// an error is also returned if the hooks could not be run as another script is running.
+func (s *Service) BeforePut(ctx context.Context, rs *models.ResultSet, items []models.ItemIndex) error {
+	rsProxy := newResultSetProxy(rs)
+	return s.fireEvent(ctx, EventBeforePut, func() []object.Object {
+		return []object.Object{object.NewList(sliceutils.Map(items, func(item models.ItemIndex) object.Object {
+			return newItemProxy(rsProxy, item.Index)
+		}))}
+	}, func(pluginName string, res object.Object) error {
+		if b, isBool := res.(*object.Bool); isBool && !b.Value() {
+			return errors.Errorf("put vetoed by script '%v'", pluginName)
+		}
+		return nil
+	})
+}
+
+// notify fires an event for which the result of the hooks are not used.  The event is dropped if another script
+// is running, or if the plugin of a hook is busy.
+func (s *Service) notify(ctx context.Context, name string, args func() []object.Object) error {
+	err := s.fireEvent(ctx, name, args, nil)
+	if errors.Is(err, errSchedulerBusy) || isPluginBusy(err) {
+		log.Printf("dropping '%v' event: %v", name, err)
+		return nil
+	}
+	return err
+}
+
+// fireEvent runs all the hooks of an event on the script scheduler, returning the first error raised.  The hook
+// arguments are only built if there are hooks to run.  If set, checkResult is called with the result of each hook.
+// If called from a running script, the hooks are run immediately on the calling goroutine.
+func (s *Service) fireEvent(
+	ctx context.Context,
+	name string,
+	args func() []object.Object,
+	checkResult func(pluginName string, res object.Object) error,
+) error {
+	type pluginHandler struct {
+		pluginName string
+		handler    eventHandler
+	}
+
+	var handlers []pluginHandler
+	for _, p := range s.plugins {
+		for _, h := range p.eventHandlers[name] {
+			handlers = append(handlers, pluginHandler{pluginName: p.name, handler: h})
+		}
+	}
+	if len(handlers) == 0 {
+		return nil
+	}
+
+	hookArgs := args()
+	runHandlers := func(ctx context.Context) error {
+		for _, h := range handlers {
+			res, err := h.handler(ctx, hookArgs)
+			if err != nil {
+				return err
+			}
+			if checkResult != nil {
+				if err := checkResult(h.pluginName, res); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if isRunningScript(ctx) {
+		return runHandlers(ctx)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, eventHookTimeout)
+	defer cancel()
+
+	errChan := make(chan error, 1)
+	if err := s.sched.startJobOnceFree(waitCtx, func(_ context.Context) {
+		hookCtx, cancelHooks := context.WithTimeout(context.WithoutCancel(ctx), eventHookDeadline)
+		defer cancelHooks()
+
+		hookErrChan := make(chan error, 1)
+		go func() {
+			hookErrChan <- runHandlers(hookCtx)
+		}()
+
+		select {
+		case err := <-hookErrChan:
+			errChan <- err
+		case <-hookCtx.Done():
+			errChan <- errors.Errorf("'%v' event hook timed out", name)
+		}
+	}); err != nil {
+		return errSchedulerBusy
+	}
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/aggregate"
 )
 
 //go:generate mockery --with-expecter --name UIService
@@ -20,11 +21,45 @@ type UIService interface {
 	// Prompt should return a channel which will provide the input from the user.  If the user
 	// provides no input, prompt should close the channel without providing anything.
 	Prompt(ctx context.Context, msg string) chan string
+
+	// Confirm should return a channel which will provide true if the user confirms, or false if they do not.
+	// If the user cancels the confirmation, confirm should close the channel without providing anything.
+	Confirm(ctx context.Context, msg string) chan bool
+
+	// Select should return a channel which will provide the index of the item chosen by the user.  If the user
+	// chooses no item, select should close the channel without providing anything.
+	Select(ctx context.Context, title string, items []string) chan int
+
+	// ShowTable displays the rows in a table with the given column headers.
+	ShowTable(ctx context.Context, columns []string, rows [][]string)
+
+	// ShowText displays a block of text with a title.
+	ShowText(ctx context.Context, title string, body string)
 }
 
 type SessionService interface {
 	Query(ctx context.Context, expr string, queryOptions QueryOptions) (*models.ResultSet, error)
 
+	// BatchGet returns the items with the given keys.  Attributes of the keys which are not key attributes
+	// of the table are ignored.
+	BatchGet(ctx context.Context, keys []models.Item, queryOptions QueryOptions) (*models.ResultSet, error)
+
+	// Aggregate applies the aggregation to the items matching the query, or all items of the table if
+	// the query is empty.
+	Aggregate(ctx context.Context, agg aggregate.Aggregation, query string, queryOptions QueryOptions) (*models.ResultSet, error)
+
+	// PutItems puts the items at the given indices of the result set to its table, using the configured put
+	// mode.  If indices is nil, the dirty items of the result set are put.  The changes are confirmed by the user
+	// before being put.  Items are marked as clean once put.  Returns the number of items put.
+	PutItems(ctx context.Context, resultSet *models.ResultSet, indices []int) (int, error)
+
+	// DeleteItems deletes the items with the given keys, once confirmed by the user.  Attributes of the keys
+	// which are not key attributes of the table are ignored.
+	DeleteItems(ctx context.Context, keys []models.Item, queryOptions QueryOptions) error
+
+	// UpdateItem sets and removes attributes of the item with the given key.  The item must exist.
+	UpdateItem(ctx context.Context, key models.Item, update ItemUpdate, queryOptions QueryOptions) error
+
 	ResultSet(ctx context.Context) *models.ResultSet
 	SelectedItemIndex(ctx context.Context) int
 	SetResultSet(ctx context.Context, newResultSet *models.ResultSet)
@@ -36,3 +71,8 @@ type QueryOptions struct {
 	NamePlaceholders  map[string]string
 	ValuePlaceholders map[string]types.AttributeValue
 }
+
+type ItemUpdate struct {
+	Set    map[string]types.AttributeValue
+	Remove []string
+}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	aggregate "github.com/lmika/dynamo-browse/internal/dynamo-browse/models/aggregate"
+
 	context "context"
 
 	models "github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
@@ -24,6 +26,217 @@ func (_m *SessionService) EXPECT() *SessionService_Expecter {
 	return &SessionService_Expecter{mock: &_m.Mock}
 }
 
+// Aggregate provides a mock function with given fields: ctx, agg, query, queryOptions
+func (_m *SessionService) Aggregate(ctx context.Context, agg aggregate.Aggregation, query string, queryOptions scriptmanager.QueryOptions) (*models.ResultSet, error) {
+	ret := _m.Called(ctx, agg, query, queryOptions)
+
+	var r0 *models.ResultSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, aggregate.Aggregation, string, scriptmanager.QueryOptions) (*models.ResultSet, error)); ok {
+		return rf(ctx, agg, query, queryOptions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, aggregate.Aggregation, string, scriptmanager.QueryOptions) *models.ResultSet); ok {
+		r0 = rf(ctx, agg, query, queryOptions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ResultSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, aggregate.Aggregation, string, scriptmanager.QueryOptions) error); ok {
+		r1 = rf(ctx, agg, query, queryOptions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionService_Aggregate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Aggregate'
+type SessionService_Aggregate_Call struct {
+	*mock.Call
+}
+
+// Aggregate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - agg aggregate.Aggregation
+//   - query string
+//   - queryOptions scriptmanager.QueryOptions
+func (_e *SessionService_Expecter) Aggregate(ctx interface{}, agg interface{}, query interface{}, queryOptions interface{}) *SessionService_Aggregate_Call {
+	return &SessionService_Aggregate_Call{Call: _e.mock.On("Aggregate", ctx, agg, query, queryOptions)}
+}
+
+func (_c *SessionService_Aggregate_Call) Run(run func(ctx context.Context, agg aggregate.Aggregation, query string, queryOptions scriptmanager.QueryOptions)) *SessionService_Aggregate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(aggregate.Aggregation), args[2].(string), args[3].(scriptmanager.QueryOptions))
+	})
+	return _c
+}
+
+func (_c *SessionService_Aggregate_Call) Return(_a0 *models.ResultSet, _a1 error) *SessionService_Aggregate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionService_Aggregate_Call) RunAndReturn(run func(context.Context, aggregate.Aggregation, string, scriptmanager.QueryOptions) (*models.ResultSet, error)) *SessionService_Aggregate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BatchGet provides a mock function with given fields: ctx, keys, queryOptions
+func (_m *SessionService) BatchGet(ctx context.Context, keys []models.Item, queryOptions scriptmanager.QueryOptions) (*models.ResultSet, error) {
+	ret := _m.Called(ctx, keys, queryOptions)
+
+	var r0 *models.ResultSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Item, scriptmanager.QueryOptions) (*models.ResultSet, error)); ok {
+		return rf(ctx, keys, queryOptions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Item, scriptmanager.QueryOptions) *models.ResultSet); ok {
+		r0 = rf(ctx, keys, queryOptions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ResultSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.Item, scriptmanager.QueryOptions) error); ok {
+		r1 = rf(ctx, keys, queryOptions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionService_BatchGet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BatchGet'
+type SessionService_BatchGet_Call struct {
+	*mock.Call
+}
+
+// BatchGet is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys []models.Item
+//   - queryOptions scriptmanager.QueryOptions
+func (_e *SessionService_Expecter) BatchGet(ctx interface{}, keys interface{}, queryOptions interface{}) *SessionService_BatchGet_Call {
+	return &SessionService_BatchGet_Call{Call: _e.mock.On("BatchGet", ctx, keys, queryOptions)}
+}
+
+func (_c *SessionService_BatchGet_Call) Run(run func(ctx context.Context, keys []models.Item, queryOptions scriptmanager.QueryOptions)) *SessionService_BatchGet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.Item), args[2].(scriptmanager.QueryOptions))
+	})
+	return _c
+}
+
+func (_c *SessionService_BatchGet_Call) Return(_a0 *models.ResultSet, _a1 error) *SessionService_BatchGet_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionService_BatchGet_Call) RunAndReturn(run func(context.Context, []models.Item, scriptmanager.QueryOptions) (*models.ResultSet, error)) *SessionService_BatchGet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteItems provides a mock function with given fields: ctx, keys, queryOptions
+func (_m *SessionService) DeleteItems(ctx context.Context, keys []models.Item, queryOptions scriptmanager.QueryOptions) error {
+	ret := _m.Called(ctx, keys, queryOptions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Item, scriptmanager.QueryOptions) error); ok {
+		r0 = rf(ctx, keys, queryOptions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SessionService_DeleteItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteItems'
+type SessionService_DeleteItems_Call struct {
+	*mock.Call
+}
+
+// DeleteItems is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys []models.Item
+//   - queryOptions scriptmanager.QueryOptions
+func (_e *SessionService_Expecter) DeleteItems(ctx interface{}, keys interface{}, queryOptions interface{}) *SessionService_DeleteItems_Call {
+	return &SessionService_DeleteItems_Call{Call: _e.mock.On("DeleteItems", ctx, keys, queryOptions)}
+}
+
+func (_c *SessionService_DeleteItems_Call) Run(run func(ctx context.Context, keys []models.Item, queryOptions scriptmanager.QueryOptions)) *SessionService_DeleteItems_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.Item), args[2].(scriptmanager.QueryOptions))
+	})
+	return _c
+}
+
+func (_c *SessionService_DeleteItems_Call) Return(_a0 error) *SessionService_DeleteItems_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SessionService_DeleteItems_Call) RunAndReturn(run func(context.Context, []models.Item, scriptmanager.QueryOptions) error) *SessionService_DeleteItems_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutItems provides a mock function with given fields: ctx, resultSet, indices
+func (_m *SessionService) PutItems(ctx context.Context, resultSet *models.ResultSet, indices []int) (int, error) {
+	ret := _m.Called(ctx, resultSet, indices)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ResultSet, []int) (int, error)); ok {
+		return rf(ctx, resultSet, indices)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ResultSet, []int) int); ok {
+		r0 = rf(ctx, resultSet, indices)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.ResultSet, []int) error); ok {
+		r1 = rf(ctx, resultSet, indices)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionService_PutItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutItems'
+type SessionService_PutItems_Call struct {
+	*mock.Call
+}
+
+// PutItems is a helper method to define mock.On call
+//   - ctx context.Context
+//   - resultSet *models.ResultSet
+//   - indices []int
+func (_e *SessionService_Expecter) PutItems(ctx interface{}, resultSet interface{}, indices interface{}) *SessionService_PutItems_Call {
+	return &SessionService_PutItems_Call{Call: _e.mock.On("PutItems", ctx, resultSet, indices)}
+}
+
+func (_c *SessionService_PutItems_Call) Run(run func(ctx context.Context, resultSet *models.ResultSet, indices []int)) *SessionService_PutItems_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.ResultSet), args[2].([]int))
+	})
+	return _c
+}
+
+func (_c *SessionService_PutItems_Call) Return(_a0 int, _a1 error) *SessionService_PutItems_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionService_PutItems_Call) RunAndReturn(run func(context.Context, *models.ResultSet, []int) (int, error)) *SessionService_PutItems_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Query provides a mock function with given fields: ctx, expr, queryOptions
 func (_m *SessionService) Query(ctx context.Context, expr string, queryOptions scriptmanager.QueryOptions) (*models.ResultSet, error) {
 	ret := _m.Called(ctx, expr, queryOptions)
@@ -200,6 +413,51 @@ func (_c *SessionService_SetResultSet_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// UpdateItem provides a mock function with given fields: ctx, key, update, queryOptions
+func (_m *SessionService) UpdateItem(ctx context.Context, key models.Item, update scriptmanager.ItemUpdate, queryOptions scriptmanager.QueryOptions) error {
+	ret := _m.Called(ctx, key, update, queryOptions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Item, scriptmanager.ItemUpdate, scriptmanager.QueryOptions) error); ok {
+		r0 = rf(ctx, key, update, queryOptions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SessionService_UpdateItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateItem'
+type SessionService_UpdateItem_Call struct {
+	*mock.Call
+}
+
+// UpdateItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key models.Item
+//   - update scriptmanager.ItemUpdate
+//   - queryOptions scriptmanager.QueryOptions
+func (_e *SessionService_Expecter) UpdateItem(ctx interface{}, key interface{}, update interface{}, queryOptions interface{}) *SessionService_UpdateItem_Call {
+	return &SessionService_UpdateItem_Call{Call: _e.mock.On("UpdateItem", ctx, key, update, queryOptions)}
+}
+
+func (_c *SessionService_UpdateItem_Call) Run(run func(ctx context.Context, key models.Item, update scriptmanager.ItemUpdate, queryOptions scriptmanager.QueryOptions)) *SessionService_UpdateItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Item), args[2].(scriptmanager.ItemUpdate), args[3].(scriptmanager.QueryOptions))
+	})
+	return _c
+}
+
+func (_c *SessionService_UpdateItem_Call) Return(_a0 error) *SessionService_UpdateItem_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SessionService_UpdateItem_Call) RunAndReturn(run func(context.Context, models.Item, scriptmanager.ItemUpdate, scriptmanager.QueryOptions) error) *SessionService_UpdateItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 type mockConstructorTestingTNewSessionService interface {
 	mock.TestingT
 	Cleanup(func())
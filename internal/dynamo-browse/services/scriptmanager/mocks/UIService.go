@@ -21,6 +21,51 @@ func (_m *UIService) EXPECT() *UIService_Expecter {
 	return &UIService_Expecter{mock: &_m.Mock}
 }
 
+// Confirm provides a mock function with given fields: ctx, msg
+func (_m *UIService) Confirm(ctx context.Context, msg string) chan bool {
+	ret := _m.Called(ctx, msg)
+
+	var r0 chan bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) chan bool); ok {
+		r0 = rf(ctx, msg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(chan bool)
+		}
+	}
+
+	return r0
+}
+
+// UIService_Confirm_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Confirm'
+type UIService_Confirm_Call struct {
+	*mock.Call
+}
+
+// Confirm is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msg string
+func (_e *UIService_Expecter) Confirm(ctx interface{}, msg interface{}) *UIService_Confirm_Call {
+	return &UIService_Confirm_Call{Call: _e.mock.On("Confirm", ctx, msg)}
+}
+
+func (_c *UIService_Confirm_Call) Run(run func(ctx context.Context, msg string)) *UIService_Confirm_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UIService_Confirm_Call) Return(_a0 chan bool) *UIService_Confirm_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UIService_Confirm_Call) RunAndReturn(run func(context.Context, string) chan bool) *UIService_Confirm_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PrintMessage provides a mock function with given fields: ctx, msg
 func (_m *UIService) PrintMessage(ctx context.Context, msg string) {
 	_m.Called(ctx, msg)
@@ -100,6 +145,122 @@ func (_c *UIService_Prompt_Call) RunAndReturn(run func(context.Context, string)
 	return _c
 }
 
+// Select provides a mock function with given fields: ctx, title, items
+func (_m *UIService) Select(ctx context.Context, title string, items []string) chan int {
+	ret := _m.Called(ctx, title, items)
+
+	var r0 chan int
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) chan int); ok {
+		r0 = rf(ctx, title, items)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(chan int)
+		}
+	}
+
+	return r0
+}
+
+// UIService_Select_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Select'
+type UIService_Select_Call struct {
+	*mock.Call
+}
+
+// Select is a helper method to define mock.On call
+//   - ctx context.Context
+//   - title string
+//   - items []string
+func (_e *UIService_Expecter) Select(ctx interface{}, title interface{}, items interface{}) *UIService_Select_Call {
+	return &UIService_Select_Call{Call: _e.mock.On("Select", ctx, title, items)}
+}
+
+func (_c *UIService_Select_Call) Run(run func(ctx context.Context, title string, items []string)) *UIService_Select_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *UIService_Select_Call) Return(_a0 chan int) *UIService_Select_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UIService_Select_Call) RunAndReturn(run func(context.Context, string, []string) chan int) *UIService_Select_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ShowTable provides a mock function with given fields: ctx, columns, rows
+func (_m *UIService) ShowTable(ctx context.Context, columns []string, rows [][]string) {
+	_m.Called(ctx, columns, rows)
+}
+
+// UIService_ShowTable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ShowTable'
+type UIService_ShowTable_Call struct {
+	*mock.Call
+}
+
+// ShowTable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - columns []string
+//   - rows [][]string
+func (_e *UIService_Expecter) ShowTable(ctx interface{}, columns interface{}, rows interface{}) *UIService_ShowTable_Call {
+	return &UIService_ShowTable_Call{Call: _e.mock.On("ShowTable", ctx, columns, rows)}
+}
+
+func (_c *UIService_ShowTable_Call) Run(run func(ctx context.Context, columns []string, rows [][]string)) *UIService_ShowTable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].([][]string))
+	})
+	return _c
+}
+
+func (_c *UIService_ShowTable_Call) Return() *UIService_ShowTable_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *UIService_ShowTable_Call) RunAndReturn(run func(context.Context, []string, [][]string)) *UIService_ShowTable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ShowText provides a mock function with given fields: ctx, title, body
+func (_m *UIService) ShowText(ctx context.Context, title string, body string) {
+	_m.Called(ctx, title, body)
+}
+
+// UIService_ShowText_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ShowText'
+type UIService_ShowText_Call struct {
+	*mock.Call
+}
+
+// ShowText is a helper method to define mock.On call
+//   - ctx context.Context
+//   - title string
+//   - body string
+func (_e *UIService_Expecter) ShowText(ctx interface{}, title interface{}, body interface{}) *UIService_ShowText_Call {
+	return &UIService_ShowText_Call{Call: _e.mock.On("ShowText", ctx, title, body)}
+}
+
+func (_c *UIService_ShowText_Call) Run(run func(ctx context.Context, title string, body string)) *UIService_ShowText_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UIService_ShowText_Call) Return() *UIService_ShowText_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *UIService_ShowText_Call) RunAndReturn(run func(context.Context, string, string)) *UIService_ShowText_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 type mockConstructorTestingTNewUIService interface {
 	mock.TestingT
 	Cleanup(func())
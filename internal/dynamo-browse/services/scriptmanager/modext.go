@@ -3,7 +3,10 @@ package scriptmanager
 import (
 	"context"
 	"fmt"
+	"log"
+	"path"
 	"regexp"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
@@ -25,6 +28,17 @@ func (m *extModule) register() *object.Module {
 		"command":       object.NewBuiltin("command", m.command),
 		"key_binding":   object.NewBuiltin("key_binding", m.keyBinding),
 		"related_items": object.NewBuiltin("related_items", m.relatedItem),
+		"on":            object.NewBuiltin("on", m.on),
+		"column":        object.NewBuiltin("column", m.column),
+		"renderer":      object.NewBuiltin("renderer", m.renderer),
+		"every":         object.NewBuiltin("every", m.every),
+	})
+}
+
+// registerForBackgroundScript returns the ext module for background scripts, which can only schedule functions.
+func (m *extModule) registerForBackgroundScript() *object.Module {
+	return object.NewBuiltinsModule("ext", map[string]object.Object{
+		"every": object.NewBuiltin("every", m.every),
 	})
 }
 
@@ -59,7 +73,7 @@ func (m *extModule) command(ctx context.Context, args ...object.Object) object.O
 		newEnv := thisEnv
 		ctx = ctxWithScriptEnv(ctx, newEnv)
 
-		res, err := callFn(ctx, fnRes, objArgs)
+		res, err := m.scriptPlugin.call(ctx, callFn, fnRes, objArgs)
 		if err != nil {
 			return errors.Errorf("command error '%v':%v - %v", m.scriptPlugin.name, cmdName, err)
 		} else if object.IsError(res) {
@@ -76,6 +90,227 @@ func (m *extModule) command(ctx context.Context, args ...object.Object) object.O
 	return nil
 }
 
+func (m *extModule) on(ctx context.Context, args ...object.Object) object.Object {
+	thisEnv := scriptEnvFromCtx(ctx)
+
+	var (
+		eventName string
+		handlerFn *object.Function
+	)
+	if err := bindArgs("ext.on", args, &eventName, &handlerFn); err != nil {
+		return err
+	}
+	if !validEventNames[eventName] {
+		return object.Errorf("value error: unrecognised event '%v'", eventName)
+	}
+
+	callFn, hasCallFn := object.GetCallFunc(ctx)
+	if !hasCallFn {
+		return object.NewError(errors.New("no callFn found in context"))
+	}
+
+	// This handler will be executed by the script scheduler
+	newHandler := func(ctx context.Context, args []object.Object) (object.Object, error) {
+		newEnv := thisEnv
+		ctx = ctxWithScriptEnv(ctx, newEnv)
+
+		res, err := m.scriptPlugin.call(ctx, callFn, handlerFn, args)
+		if isPluginBusy(err) {
+			return nil, err
+		} else if err != nil {
+			return nil, errors.Errorf("event error '%v':%v - %v", m.scriptPlugin.name, eventName, err)
+		} else if object.IsError(res) {
+			errObj := res.(*object.Error)
+			return nil, errors.Errorf("event error '%v':%v - %v", m.scriptPlugin.name, eventName, errObj.Inspect())
+		}
+		return res, nil
+	}
+
+	if m.scriptPlugin.eventHandlers == nil {
+		m.scriptPlugin.eventHandlers = make(map[string][]eventHandler)
+	}
+	m.scriptPlugin.eventHandlers[eventName] = append(m.scriptPlugin.eventHandlers[eventName], newHandler)
+	return nil
+}
+
+func (m *extModule) column(ctx context.Context, args ...object.Object) object.Object {
+	thisEnv := scriptEnvFromCtx(ctx)
+
+	var (
+		colName   string
+		tableName = "*"
+		colFn     *object.Function
+		objErr    *object.Error
+	)
+	switch len(args) {
+	case 2:
+		objErr = bindArgs("ext.column", args, &colName, &colFn)
+	case 3:
+		objErr = bindArgs("ext.column", []object.Object{args[0], args[2]}, &colName, &colFn)
+		if objErr == nil {
+			options, err := object.AsMap(args[1])
+			if err != nil {
+				return err
+			}
+			if strVal, isStrVal := options.Get("table").(*object.String); isStrVal {
+				tableName = strVal.Value()
+			}
+		}
+	default:
+		objErr = object.Errorf("type error: ext.column() takes 2 or 3 arguments (%d given)", len(args))
+	}
+	if objErr != nil {
+		return objErr
+	}
+
+	callFn, hasCallFn := object.GetCallFunc(ctx)
+	if !hasCallFn {
+		return object.NewError(errors.New("no callFn found in context"))
+	}
+
+	// This function will be executed by the script scheduler
+	evalFn := func(ctx context.Context, tableInfo *models.TableInfo, item models.Item) (types.AttributeValue, error) {
+		newEnv := thisEnv
+		ctx = ctxWithScriptEnv(ctx, newEnv)
+
+		rs := &models.ResultSet{TableInfo: tableInfo}
+		rs.SetItems([]models.Item{item})
+
+		res, err := m.scriptPlugin.call(ctx, callFn, colFn, []object.Object{newItemProxy(newResultSetProxy(rs), 0)})
+		if isPluginBusy(err) {
+			return nil, err
+		} else if err != nil {
+			return nil, errors.Errorf("column error '%v':%v - %v", m.scriptPlugin.name, colName, err)
+		} else if object.IsError(res) {
+			errObj := res.(*object.Error)
+			return nil, errors.Errorf("column error '%v':%v - %v", m.scriptPlugin.name, colName, errObj.Inspect())
+		} else if res == object.Nil {
+			return nil, nil
+		}
+
+		val, err := tamarinValueToAttributeValue(res)
+		if err != nil {
+			return nil, errors.Errorf("column error '%v':%v - %v", m.scriptPlugin.name, colName, err)
+		}
+		return val, nil
+	}
+
+	m.scriptPlugin.definedColumns = append(m.scriptPlugin.definedColumns, &scriptColumn{
+		name:   colName,
+		table:  tableName,
+		evalFn: evalFn,
+	})
+	return nil
+}
+
+func (m *extModule) renderer(ctx context.Context, args ...object.Object) object.Object {
+	thisEnv := scriptEnvFromCtx(ctx)
+
+	var (
+		pattern    string
+		rendererFn *object.Function
+	)
+	if err := bindArgs("ext.renderer", args, &pattern, &rendererFn); err != nil {
+		return err
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return object.Errorf("value error: invalid attribute pattern '%v'", pattern)
+	}
+
+	callFn, hasCallFn := object.GetCallFunc(ctx)
+	if !hasCallFn {
+		return object.NewError(errors.New("no callFn found in context"))
+	}
+
+	// This function will be executed by the script scheduler
+	renderFn := func(ctx context.Context, value types.AttributeValue) (string, bool, error) {
+		newEnv := thisEnv
+		ctx = ctxWithScriptEnv(ctx, newEnv)
+
+		val, err := attributeValueToTamarin(value)
+		if err != nil {
+			return "", false, err
+		}
+
+		res, err := m.scriptPlugin.call(ctx, callFn, rendererFn, []object.Object{val})
+		if isPluginBusy(err) {
+			return "", false, err
+		} else if err != nil {
+			return "", false, errors.Errorf("renderer error '%v':%v - %v", m.scriptPlugin.name, pattern, err)
+		} else if object.IsError(res) {
+			errObj := res.(*object.Error)
+			return "", false, errors.Errorf("renderer error '%v':%v - %v", m.scriptPlugin.name, pattern, errObj.Inspect())
+		}
+
+		switch r := res.(type) {
+		case *object.NilType:
+			return "", false, nil
+		case *object.String:
+			return r.Value(), true, nil
+		}
+		return res.Inspect(), true, nil
+	}
+
+	m.scriptPlugin.renderers = append(m.scriptPlugin.renderers, &scriptRenderer{
+		pattern:  pattern,
+		renderFn: renderFn,
+	})
+	return nil
+}
+
+func (m *extModule) every(ctx context.Context, args ...object.Object) object.Object {
+	thisEnv := scriptEnvFromCtx(ctx)
+
+	var (
+		intervalStr string
+		everyFn     *object.Function
+	)
+	if err := bindArgs("ext.every", args, &intervalStr, &everyFn); err != nil {
+		return err
+	}
+
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil {
+		return object.Errorf("value error: invalid interval '%v'", intervalStr)
+	} else if interval < minEveryInterval {
+		return object.Errorf("value error: interval must be at least %v", minEveryInterval)
+	}
+
+	callFn, hasCallFn := object.GetCallFunc(ctx)
+	if !hasCallFn {
+		return object.NewError(errors.New("no callFn found in context"))
+	}
+
+	// This function will be executed by a background job
+	tickFn := func(ctx context.Context) (bool, error) {
+		newEnv := thisEnv
+		ctx = ctxWithScriptEnv(ctx, newEnv)
+
+		res, err := m.scriptPlugin.call(ctx, callFn, everyFn, []object.Object{})
+		if isPluginBusy(err) {
+			// Skip this run, but keep the function scheduled
+			log.Printf("skipping every '%v':%v - %v", m.scriptPlugin.name, intervalStr, err)
+			return true, nil
+		} else if err != nil {
+			return false, errors.Errorf("every error '%v':%v - %v", m.scriptPlugin.name, intervalStr, err)
+		} else if object.IsError(res) {
+			errObj := res.(*object.Error)
+			return false, errors.Errorf("every error '%v':%v - %v", m.scriptPlugin.name, intervalStr, errObj.Inspect())
+		}
+
+		if b, isBool := res.(*object.Bool); isBool && !b.Value() {
+			return false, nil
+		}
+		return true, nil
+	}
+
+	m.scriptPlugin.everyJobs = append(m.scriptPlugin.everyJobs, &scriptEvery{
+		interval: interval,
+		tickFn:   tickFn,
+	})
+	return nil
+}
+
 func (m *extModule) keyBinding(ctx context.Context, args ...object.Object) object.Object {
 	thisEnv := scriptEnvFromCtx(ctx)
 
@@ -120,7 +355,7 @@ func (m *extModule) keyBinding(ctx context.Context, args ...object.Object) objec
 		newEnv := thisEnv
 		ctx = ctxWithScriptEnv(ctx, newEnv)
 
-		res, err := callFn(ctx, fnRes, objArgs)
+		res, err := m.scriptPlugin.call(ctx, callFn, fnRes, objArgs)
 		if err != nil {
 			return errors.Errorf("command error '%v':%v - %v", m.scriptPlugin.name, bindingName, err)
 		} else if object.IsError(res) {
@@ -162,7 +397,7 @@ func (m *extModule) relatedItem(ctx context.Context, args ...object.Object) obje
 		newEnv := thisEnv
 		ctx = ctxWithScriptEnv(ctx, newEnv)
 
-		res, err := callFn(ctx, callbackFn, []object.Object{
+		res, err := m.scriptPlugin.call(ctx, callFn, callbackFn, []object.Object{
 			newItemProxy(newResultSetProxy(rs), index),
 		})
 
@@ -207,7 +442,7 @@ func (m *extModule) relatedItem(ctx context.Context, args ...object.Object) obje
 					thisNewEnv := thisEnv
 					ctx = ctxWithScriptEnv(ctx, thisNewEnv)
 
-					res, err := callFn(ctx, selectFn, []object.Object{})
+					res, err := m.scriptPlugin.call(ctx, callFn, selectFn, []object.Object{})
 					if err != nil {
 						return errors.Errorf("rel error '%v' - %v", m.scriptPlugin.name, err)
 					} else if object.IsError(res) {
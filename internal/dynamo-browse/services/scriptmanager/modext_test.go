@@ -3,11 +3,15 @@ package scriptmanager_test
 import (
 	"context"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/scriptmanager"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/scriptmanager/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestExtModule_RelatedItems(t *testing.T) {
@@ -149,3 +153,346 @@ func TestExtModule_RelatedItems(t *testing.T) {
 		assert.NoError(t, relItems[0].OnSelect())
 	})
 }
+
+func TestExtModule_On(t *testing.T) {
+	rs := &models.ResultSet{
+		TableInfo: &models.TableInfo{
+			Name: "test-table",
+		},
+	}
+	rs.SetItems([]models.Item{
+		{"pk": &types.AttributeValueMemberS{Value: "abc"}},
+		{"pk": &types.AttributeValueMemberS{Value: "1232"}},
+	})
+
+	t.Run("should run hooks of events", func(t *testing.T) {
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "result set: 2")
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "table: test-table")
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "item: 1232")
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testScriptFile(t, "test.tm", `
+			ext.on("result_set", func(rs) {
+				ui.print("result set: " + string(rs.length))
+			})
+			ext.on("table_selected", func(table) {
+				ui.print("table: " + table.name)
+			})
+			ext.on("item_selected", func(item) {
+				ui.print("item: " + item.attr("pk"))
+			})
+		`)))
+		srv.SetIFaces(scriptmanager.Ifaces{UI: mockedUIService})
+
+		ctx := context.Background()
+		_, err := srv.LoadScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		assert.NoError(t, srv.NotifyResultSet(ctx, rs))
+		assert.NoError(t, srv.NotifyTableSelected(ctx, rs.TableInfo))
+		assert.NoError(t, srv.NotifyItemSelected(ctx, rs, 1))
+
+		mockedUIService.AssertExpectations(t)
+	})
+
+	t.Run("should allow before_put hooks to modify items", func(t *testing.T) {
+		srv := scriptmanager.New(scriptmanager.WithFS(testScriptFile(t, "test.tm", `
+			ext.on("before_put", func(items) {
+				for _, item := range items {
+					item.set_attr("audited", true)
+				}
+			})
+		`)))
+
+		ctx := context.Background()
+		_, err := srv.LoadScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		err = srv.BeforePut(ctx, rs, []models.ItemIndex{{Index: 1, Item: rs.Items()[1]}})
+		assert.NoError(t, err)
+
+		assert.Nil(t, rs.Items()[0]["audited"])
+		assert.Equal(t, &types.AttributeValueMemberBOOL{Value: true}, rs.Items()[1]["audited"])
+	})
+
+	t.Run("should veto put if before_put hook returns false", func(t *testing.T) {
+		srv := scriptmanager.New(scriptmanager.WithFS(testScriptFile(t, "test.tm", `
+			ext.on("before_put", func(items) {
+				return false
+			})
+		`)))
+
+		ctx := context.Background()
+		_, err := srv.LoadScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		err = srv.BeforePut(ctx, rs, []models.ItemIndex{{Index: 0, Item: rs.Items()[0]}})
+		assert.Error(t, err)
+	})
+
+	t.Run("should cancel hooks which run past the deadline and free the scheduler", func(t *testing.T) {
+		testFS := fstest.MapFS{
+			"hook.tm": &fstest.MapFile{Data: []byte(`
+				ext.on("result_set", func(rs) {
+					ui.prompt("Never answered")
+				})
+			`)},
+			"other.tm": &fstest.MapFile{Data: []byte(`
+				ui.print("Still running scripts")
+			`)},
+		}
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().Prompt(mock.Anything, "Never answered").Return(make(chan string))
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "Still running scripts")
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{UI: mockedUIService})
+
+		ctx := context.Background()
+		_, err := srv.LoadScript(ctx, "hook.tm")
+		assert.NoError(t, err)
+
+		assert.Error(t, srv.NotifyResultSet(ctx, rs))
+
+		startCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+
+		errChan := make(chan error)
+		assert.NoError(t, srv.StartAdHocScript(startCtx, "other.tm", errChan))
+		assert.NoError(t, waitForErr(t, errChan))
+	})
+
+	t.Run("should return error if event is not recognised", func(t *testing.T) {
+		srv := scriptmanager.New(scriptmanager.WithFS(testScriptFile(t, "test.tm", `
+			ext.on("after_lunch", func() {})
+		`)))
+
+		_, err := srv.LoadScript(context.Background(), "test.tm")
+		assert.Error(t, err)
+	})
+}
+
+func TestExtModule_Column(t *testing.T) {
+	tableInfo := &models.TableInfo{
+		Name: "test-table",
+		Keys: models.KeyAttribute{PartitionKey: "pk"},
+	}
+	item := models.Item{
+		"pk":    &types.AttributeValueMemberS{Value: "abc"},
+		"price": &types.AttributeValueMemberN{Value: "12"},
+	}
+
+	t.Run("should add columns evaluated in the background", func(t *testing.T) {
+		srv := scriptmanager.New(scriptmanager.WithFS(testScriptFile(t, "test.tm", `
+			ext.column("double price", func(item) {
+				return item.attr("price") * 2
+			})
+			ext.column("other table", {"table": "other-*"}, func(item) {
+				return "other"
+			})
+		`)))
+
+		_, err := srv.LoadScript(context.Background(), "test.tm")
+		assert.NoError(t, err)
+
+		updated := make(chan struct{}, 10)
+		cols := srv.Columns(tableInfo, func() { updated <- struct{}{} })
+		assert.Len(t, cols, 1)
+		assert.Equal(t, "double price", cols[0].Name)
+
+		// Value is nil while it's being evaluated
+		assert.Nil(t, cols[0].Evaluator.EvaluateForItem(item))
+
+		select {
+		case <-updated:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for column value")
+		}
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "24"}, cols[0].Evaluator.EvaluateForItem(item))
+	})
+
+	t.Run("should evaluate columns right away when the value is needed", func(t *testing.T) {
+		srv := scriptmanager.New(scriptmanager.WithFS(testScriptFile(t, "test.tm", `
+			ext.column("double price", func(item) {
+				return item.attr("price") * 2
+			})
+		`)))
+
+		_, err := srv.LoadScript(context.Background(), "test.tm")
+		assert.NoError(t, err)
+
+		cols := srv.Columns(tableInfo, nil)
+		assert.Len(t, cols, 1)
+
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "24"}, models.EvaluateForItemSync(cols[0].Evaluator, item))
+
+		// The value is cached for rendering
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "24"}, cols[0].Evaluator.EvaluateForItem(item))
+	})
+
+	t.Run("should return error if the column function is missing", func(t *testing.T) {
+		srv := scriptmanager.New(scriptmanager.WithFS(testScriptFile(t, "test.tm", `
+			ext.column("no function")
+		`)))
+
+		_, err := srv.LoadScript(context.Background(), "test.tm")
+		assert.Error(t, err)
+	})
+}
+
+func TestExtModule_Renderer(t *testing.T) {
+	t.Run("should render matching attributes in the background", func(t *testing.T) {
+		srv := scriptmanager.New(scriptmanager.WithFS(testScriptFile(t, "test.tm", `
+			ext.renderer("*_at", func(value) {
+				return "at " + string(value)
+			})
+		`)))
+
+		_, err := srv.LoadScript(context.Background(), "test.tm")
+		assert.NoError(t, err)
+
+		updated := make(chan struct{}, 10)
+		renderer := srv.AttributeRenderer(func() { updated <- struct{}{} })
+
+		value := &types.AttributeValueMemberN{Value: "1700000000"}
+
+		_, ok := renderer("name", value)
+		assert.False(t, ok)
+
+		// Value is not rendered while it's being evaluated
+		_, ok = renderer("created_at", value)
+		assert.False(t, ok)
+
+		select {
+		case <-updated:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for rendered value")
+		}
+
+		str, ok := renderer("created_at", value)
+		assert.True(t, ok)
+		assert.Equal(t, "at 1700000000", str)
+	})
+}
+
+func TestExtModule_Every(t *testing.T) {
+	t.Run("should call the function in the background until it returns false", func(t *testing.T) {
+		testFS := testScriptFile(t, "test.tm", `
+			var count = 0
+			ext.every("1s", func() {
+				count = count + 1
+				ui.print("tick " + string(count))
+				return count < 2
+			})
+		`)
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "tick 1").Once()
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "tick 2").Once()
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI: mockedUIService,
+		})
+
+		_, err := srv.LoadScript(context.Background(), "test.tm")
+		assert.NoError(t, err)
+
+		jobs := srv.BackgroundJobs()
+		assert.Len(t, jobs, 1)
+		assert.Equal(t, "test: every 1s", jobs[0].Name)
+
+		assert.Eventually(t, func() bool {
+			return len(srv.BackgroundJobs()) == 0
+		}, 5*time.Second, 50*time.Millisecond)
+
+		mockedUIService.AssertExpectations(t)
+	})
+
+	t.Run("should skip runs while the script is busy", func(t *testing.T) {
+		testFS := testScriptFile(t, "test.tm", `
+			ext.command("hold", func() {
+				ui.prompt("Holding")
+			})
+			ext.every("1s", func() {
+				ui.print("tick")
+				return false
+			})
+		`)
+
+		promptChan := make(chan string)
+		ticked := make(chan struct{})
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().Prompt(mock.Anything, "Holding").Return(promptChan)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "tick").Run(func(ctx context.Context, msg string) {
+			close(ticked)
+		}).Once()
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI: mockedUIService,
+		})
+
+		ctx := context.Background()
+		_, err := srv.LoadScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		cmdErrChan := make(chan error, 1)
+		assert.NoError(t, srv.LookupCommand("hold").Invoke(ctx, nil, cmdErrChan))
+
+		// The first run is skipped as the command is still running, but the function remains scheduled
+		assert.Eventually(t, func() bool {
+			jobs := srv.BackgroundJobs()
+			return len(jobs) == 1 && jobs[0].Runs == 1 && jobs[0].State == scriptmanager.BackgroundJobWaiting
+		}, 10*time.Second, 50*time.Millisecond)
+
+		promptChan <- "done"
+		assert.NoError(t, waitForErr(t, cmdErrChan))
+
+		select {
+		case <-ticked:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for scheduled function")
+		}
+	})
+
+	t.Run("should cancel the scheduled functions when the script is reloaded", func(t *testing.T) {
+		testFS := testScriptFile(t, "test.tm", `
+			ext.every("1m", func() {
+				ui.print("tick")
+			})
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+
+		_, err := srv.LoadScript(context.Background(), "test.tm")
+		assert.NoError(t, err)
+		firstJobs := srv.BackgroundJobs()
+		assert.Len(t, firstJobs, 1)
+
+		_, err = srv.LoadScript(context.Background(), "test.tm")
+		assert.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			jobs := srv.BackgroundJobs()
+			return len(jobs) == 1 && jobs[0].ID != firstJobs[0].ID
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("should return error if the interval is invalid", func(t *testing.T) {
+		scenarios := []string{`"soon"`, `"10ms"`}
+		for _, interval := range scenarios {
+			t.Run(interval, func(t *testing.T) {
+				srv := scriptmanager.New(scriptmanager.WithFS(testScriptFile(t, "test.tm", `
+					ext.every(`+interval+`, func() { })
+				`)))
+
+				_, err := srv.LoadScript(context.Background(), "test.tm")
+				assert.Error(t, err)
+				assert.Empty(t, srv.BackgroundJobs())
+			})
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/aggregate"
 	"github.com/pkg/errors"
 	"github.com/risor-io/risor/object"
 )
@@ -30,54 +32,331 @@ func (um *sessionModule) query(ctx context.Context, args ...object.Object) objec
 			return objErr
 		}
 
-		// Table name
-		if val := objMap.Get("table"); val != object.Nil && val.IsTruthy() {
-			switch tv := val.(type) {
-			case *object.String:
-				options.TableName = tv.Value()
-			case *tableProxy:
-				options.TableName = tv.table.Name
-			default:
-				return object.Errorf("type error: query option 'table' must be either a string or table")
+		options, objErr = parseQueryOptions(objMap)
+		if objErr != nil {
+			return objErr
+		}
+	}
+
+	resp, err := um.sessionService.Query(ctx, expr, options)
+
+	if err != nil {
+		return object.NewError(err)
+	}
+	return &resultSetProxy{resultSet: resp}
+}
+
+func (um *sessionModule) aggregate(ctx context.Context, args ...object.Object) object.Object {
+	if len(args) == 0 || len(args) > 2 {
+		return object.Errorf("type error: session.aggregate takes either 1 or 2 arguments (%d given)", len(args))
+	}
+
+	fn, objErr := object.AsString(args[0])
+	if objErr != nil {
+		return objErr
+	}
+
+	var (
+		value, groupBy, query string
+		options               QueryOptions
+	)
+	if len(args) == 2 {
+		objMap, objErr := object.AsMap(args[1])
+		if objErr != nil {
+			return objErr
+		}
+
+		if val, isStr := objMap.Get("value").(*object.String); isStr {
+			value = val.Value()
+		}
+		if val, isStr := objMap.Get("by").(*object.String); isStr {
+			groupBy = val.Value()
+		}
+		if val, isStr := objMap.Get("query").(*object.String); isStr {
+			query = val.Value()
+		}
+
+		options, objErr = parseQueryOptions(objMap)
+		if objErr != nil {
+			return objErr
+		}
+	}
+
+	agg, err := aggregate.New(fn, value, groupBy)
+	if err != nil {
+		return object.NewError(err)
+	}
+
+	resp, err := um.sessionService.Aggregate(ctx, agg, query, options)
+	if err != nil {
+		return object.NewError(err)
+	}
+	return &resultSetProxy{resultSet: resp}
+}
+
+func (um *sessionModule) batchGet(ctx context.Context, args ...object.Object) object.Object {
+	if len(args) == 0 || len(args) > 2 {
+		return object.Errorf("type error: session.batch_get takes either 1 or 2 arguments (%d given)", len(args))
+	}
+
+	keyList, objErr := object.AsList(args[0])
+	if objErr != nil {
+		return objErr
+	}
+
+	keys, objErr := asKeys(keyList.Value())
+	if objErr != nil {
+		return objErr
+	}
+
+	var options QueryOptions
+	if len(args) == 2 {
+		objMap, objErr := object.AsMap(args[1])
+		if objErr != nil {
+			return objErr
+		}
+
+		options, objErr = parseQueryOptions(objMap)
+		if objErr != nil {
+			return objErr
+		}
+	}
+
+	resp, err := um.sessionService.BatchGet(ctx, keys, options)
+	if err != nil {
+		return object.NewError(err)
+	}
+	return &resultSetProxy{resultSet: resp}
+}
+
+func (um *sessionModule) putItems(ctx context.Context, args ...object.Object) object.Object {
+	if err := require("session.put_items", 1, args); err != nil {
+		return err
+	}
+
+	var putCount int
+	switch arg := args[0].(type) {
+	case *resultSetProxy:
+		n, err := um.sessionService.PutItems(ctx, arg.resultSet, nil)
+		if err != nil {
+			return object.NewError(err)
+		}
+		putCount = n
+	case *object.List:
+		// Items are grouped by result set, so that each result set is put once
+		var (
+			resultSets []*models.ResultSet
+			indices    = make(map[*models.ResultSet][]int)
+		)
+		for i, itm := range arg.Value() {
+			ip, isItemProxy := itm.(*itemProxy)
+			if !isItemProxy {
+				return object.Errorf("type error: item %d must be an item (got %v)", i, itm.Type())
+			}
+
+			rs := ip.resultSetProxy.resultSet
+			if _, hasRs := indices[rs]; !hasRs {
+				resultSets = append(resultSets, rs)
 			}
+			indices[rs] = append(indices[rs], ip.itemIndex)
 		}
 
-		// Index name
-		if val, isStr := objMap.Get("index").(*object.String); isStr {
-			options.IndexName = val.Value()
-		}
-
-		// Placeholders
-		if argsVal, isArgsValMap := objMap.Get("args").(*object.Map); isArgsValMap {
-			options.NamePlaceholders = make(map[string]string)
-			options.ValuePlaceholders = make(map[string]types.AttributeValue)
-
-			for k, val := range argsVal.Value() {
-				switch v := val.(type) {
-				case *object.String:
-					options.NamePlaceholders[k] = v.Value()
-					options.ValuePlaceholders[k] = &types.AttributeValueMemberS{Value: v.Value()}
-				case *object.Int:
-					options.ValuePlaceholders[k] = &types.AttributeValueMemberN{Value: fmt.Sprint(v.Value())}
-				case *object.Float:
-					options.ValuePlaceholders[k] = &types.AttributeValueMemberN{Value: fmt.Sprint(v.Value())}
-				case *object.Bool:
-					options.ValuePlaceholders[k] = &types.AttributeValueMemberBOOL{Value: v.Value()}
-				case *object.NilType:
-					options.ValuePlaceholders[k] = &types.AttributeValueMemberNULL{Value: true}
-				default:
-					return object.Errorf("type error: arg '%v' of type '%v' is not supported", k, val.Type())
+		for _, rs := range resultSets {
+			n, err := um.sessionService.PutItems(ctx, rs, indices[rs])
+			putCount += n
+			if err != nil {
+				return object.NewError(err)
+			}
+		}
+	default:
+		return object.Errorf("type error: session.put_items expects a result set or list of items (got %v)", args[0].Type())
+	}
+	return object.NewInt(int64(putCount))
+}
+
+func (um *sessionModule) deleteItems(ctx context.Context, args ...object.Object) object.Object {
+	if len(args) == 0 || len(args) > 2 {
+		return object.Errorf("type error: session.delete_items takes either 1 or 2 arguments (%d given)", len(args))
+	}
+
+	var options QueryOptions
+	if len(args) == 2 {
+		objMap, objErr := object.AsMap(args[1])
+		if objErr != nil {
+			return objErr
+		}
+
+		options, objErr = parseQueryOptions(objMap)
+		if objErr != nil {
+			return objErr
+		}
+	}
+
+	var keys []models.Item
+	switch arg := args[0].(type) {
+	case *resultSetProxy:
+		keys = arg.resultSet.Items()
+		if options.TableName == "" {
+			options.TableName = arg.resultSet.TableInfo.Name
+		}
+	case *object.List:
+		var objErr *object.Error
+		keys, objErr = asKeys(arg.Value())
+		if objErr != nil {
+			return objErr
+		}
+
+		// Items are deleted from the table they were read from, unless the table is given
+		if options.TableName == "" {
+			for _, key := range arg.Value() {
+				if ip, isItemProxy := key.(*itemProxy); isItemProxy {
+					options.TableName = ip.resultSetProxy.resultSet.TableInfo.Name
+					break
 				}
 			}
 		}
+	default:
+		return object.Errorf("type error: session.delete_items expects a result set or list of keys (got %v)", args[0].Type())
 	}
 
-	resp, err := um.sessionService.Query(ctx, expr, options)
+	if len(keys) == 0 {
+		return object.NewInt(0)
+	}
+	if err := um.sessionService.DeleteItems(ctx, keys, options); err != nil {
+		return object.NewError(err)
+	}
+	return object.NewInt(int64(len(keys)))
+}
 
-	if err != nil {
+func (um *sessionModule) updateItem(ctx context.Context, args ...object.Object) object.Object {
+	if err := require("session.update_item", 2, args); err != nil {
+		return err
+	}
+
+	keys, objErr := asKeys([]object.Object{args[0]})
+	if objErr != nil {
+		return objErr
+	}
+
+	objMap, objErr := object.AsMap(args[1])
+	if objErr != nil {
+		return objErr
+	}
+
+	options, objErr := parseQueryOptions(objMap)
+	if objErr != nil {
+		return objErr
+	}
+	if ip, isItemProxy := args[0].(*itemProxy); isItemProxy && options.TableName == "" {
+		options.TableName = ip.resultSetProxy.resultSet.TableInfo.Name
+	}
+
+	var update ItemUpdate
+	if val := objMap.Get("set"); val != object.Nil {
+		setMap, objErr := object.AsMap(val)
+		if objErr != nil {
+			return objErr
+		}
+
+		update.Set = make(map[string]types.AttributeValue)
+		for k, v := range setMap.Value() {
+			av, err := tamarinValueToAttributeValue(v)
+			if err != nil {
+				return object.NewError(errors.Wrapf(err, "cannot set '%v'", k))
+			}
+			update.Set[k] = av
+		}
+	}
+	if val := objMap.Get("remove"); val != object.Nil {
+		removeList, objErr := object.AsList(val)
+		if objErr != nil {
+			return objErr
+		}
+
+		for _, v := range removeList.Value() {
+			name, objErr := object.AsString(v)
+			if objErr != nil {
+				return objErr
+			}
+			update.Remove = append(update.Remove, name)
+		}
+	}
+
+	if len(update.Set) == 0 && len(update.Remove) == 0 {
+		return object.Errorf("session.update_item expects attributes to set or remove")
+	}
+	if err := um.sessionService.UpdateItem(ctx, keys[0], update, options); err != nil {
 		return object.NewError(err)
 	}
-	return &resultSetProxy{resultSet: resp}
+	return object.Nil
+}
+
+// asKeys returns the items of a list of keys, which can either be maps or items
+func asKeys(keyList []object.Object) ([]models.Item, *object.Error) {
+	keys := make([]models.Item, len(keyList))
+	for i, key := range keyList {
+		switch k := key.(type) {
+		case *itemProxy:
+			keys[i] = k.item
+		case *object.Map:
+			av, err := tamarinValueToAttributeValue(k)
+			if err != nil {
+				return nil, object.NewError(err)
+			}
+			keys[i] = av.(*types.AttributeValueMemberM).Value
+		default:
+			return nil, object.Errorf("type error: key %d must be either a map or item (got %v)", i, key.Type())
+		}
+	}
+	return keys, nil
+}
+
+// parseQueryOptions parses the table, index and placeholder args options of a query
+func parseQueryOptions(objMap *object.Map) (QueryOptions, *object.Error) {
+	var options QueryOptions
+
+	// Table name
+	if val := objMap.Get("table"); val != object.Nil && val.IsTruthy() {
+		switch tv := val.(type) {
+		case *object.String:
+			options.TableName = tv.Value()
+		case *tableProxy:
+			options.TableName = tv.table.Name
+		default:
+			return QueryOptions{}, object.Errorf("type error: query option 'table' must be either a string or table")
+		}
+	}
+
+	// Index name
+	if val, isStr := objMap.Get("index").(*object.String); isStr {
+		options.IndexName = val.Value()
+	}
+
+	// Placeholders
+	if argsVal, isArgsValMap := objMap.Get("args").(*object.Map); isArgsValMap {
+		options.NamePlaceholders = make(map[string]string)
+		options.ValuePlaceholders = make(map[string]types.AttributeValue)
+
+		for k, val := range argsVal.Value() {
+			switch v := val.(type) {
+			case *object.String:
+				options.NamePlaceholders[k] = v.Value()
+				options.ValuePlaceholders[k] = &types.AttributeValueMemberS{Value: v.Value()}
+			case *object.Int:
+				options.ValuePlaceholders[k] = &types.AttributeValueMemberN{Value: fmt.Sprint(v.Value())}
+			case *object.Float:
+				options.ValuePlaceholders[k] = &types.AttributeValueMemberN{Value: fmt.Sprint(v.Value())}
+			case *object.Bool:
+				options.ValuePlaceholders[k] = &types.AttributeValueMemberBOOL{Value: v.Value()}
+			case *object.NilType:
+				options.ValuePlaceholders[k] = &types.AttributeValueMemberNULL{Value: true}
+			default:
+				return QueryOptions{}, object.Errorf("type error: arg '%v' of type '%v' is not supported", k, val.Type())
+			}
+		}
+	}
+
+	return options, nil
 }
 
 func (um *sessionModule) resultSet(ctx context.Context, args ...object.Object) object.Object {
@@ -137,6 +416,11 @@ func (um *sessionModule) currentTable(ctx context.Context, args ...object.Object
 func (um *sessionModule) register() *object.Module {
 	return object.NewBuiltinsModule("session", map[string]object.Object{
 		"query":          object.NewBuiltin("query", um.query),
+		"aggregate":      object.NewBuiltin("aggregate", um.aggregate),
+		"batch_get":      object.NewBuiltin("batch_get", um.batchGet),
+		"put_items":      object.NewBuiltin("put_items", um.putItems),
+		"delete_items":   object.NewBuiltin("delete_items", um.deleteItems),
+		"update_item":    object.NewBuiltin("update_item", um.updateItem),
 		"current_table":  object.NewBuiltin("current_table", um.currentTable),
 		"result_set":     object.NewBuiltin("result_set", um.resultSet),
 		"selected_item":  object.NewBuiltin("selected_item", um.selectedItem),
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/aggregate"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/scriptmanager"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/scriptmanager/mocks"
 	"github.com/pkg/errors"
@@ -61,6 +62,82 @@ func TestModSession_Table(t *testing.T) {
 		mockedSessionService.AssertExpectations(t)
 	})
 
+	t.Run("should return the table description and indices", func(t *testing.T) {
+		tableDef := models.TableInfo{
+			Name:          "test_table",
+			Keys:          models.KeyAttribute{PartitionKey: "pk", SortKey: "sk"},
+			Status:        "ACTIVE",
+			BillingMode:   "PROVISIONED",
+			ReadCapacity:  5,
+			WriteCapacity: 10,
+			ItemCount:     123,
+			SizeBytes:     4567,
+			TTL:           models.TableTTL{Status: "ENABLED", AttributeName: "expires"},
+			Stream:        models.TableStream{Enabled: true, ViewType: "NEW_IMAGE"},
+			PITRStatus:    "DISABLED",
+			GSIs: []models.TableGSI{
+				{
+					Name:       "index-1",
+					Keys:       models.KeyAttribute{PartitionKey: "ipk"},
+					Status:     "CREATING",
+					Projection: models.TableProjection{Type: "INCLUDE", NonKeyAttributes: []string{"alpha"}},
+				},
+			},
+			LSIs: []models.TableLSI{
+				{
+					Name:       "local-index",
+					Keys:       models.KeyAttribute{PartitionKey: "pk", SortKey: "lsk"},
+					Projection: models.TableProjection{Type: "KEYS_ONLY"},
+					ItemCount:  12,
+				},
+			},
+		}
+		rs := models.ResultSet{TableInfo: &tableDef}
+
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedSessionService.EXPECT().ResultSet(mock.Anything).Return(&rs)
+
+		testFS := testScriptFile(t, "test.tm", `
+			table := session.current_table()
+
+			assert(table.status == "ACTIVE")
+			assert(table.billing_mode == "PROVISIONED")
+			assert(table.capacity["read"] == 5)
+			assert(table.capacity["write"] == 10)
+			assert(table.item_count == 123)
+			assert(table.size_bytes == 4567)
+			assert(table.ttl["status"] == "ENABLED")
+			assert(table.ttl["attribute"] == "expires")
+			assert(table.stream["enabled"])
+			assert(table.stream["view_type"] == "NEW_IMAGE")
+			assert(table.pitr == "DISABLED")
+
+			assert(table.gsis[0].type == "gsi")
+			assert(table.gsis[0].status == "CREATING")
+			assert(table.gsis[0].projection["type"] == "INCLUDE")
+			assert(table.gsis[0].projection["attributes"] == ["alpha"])
+
+			assert(len(table.lsis) == 1)
+			assert(table.lsis[0].type == "lsi")
+			assert(table.lsis[0].name == "local-index")
+			assert(table.lsis[0].keys["range"] == "lsk")
+			assert(table.lsis[0].projection["type"] == "KEYS_ONLY")
+			assert(table.lsis[0].item_count == 12)
+			assert(table.lsis[0].status == "")
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		mockedSessionService.AssertExpectations(t)
+	})
+
 	t.Run("should return nil if no current result set", func(t *testing.T) {
 		mockedSessionService := mocks.NewSessionService(t)
 		mockedSessionService.EXPECT().ResultSet(mock.Anything).Return(nil)
@@ -328,6 +405,361 @@ func TestModSession_Query(t *testing.T) {
 	})
 }
 
+func TestModSession_Aggregate(t *testing.T) {
+	t.Run("should return aggregate result", func(t *testing.T) {
+		rs := &models.ResultSet{}
+		rs.SetItems([]models.Item{
+			{"status": &types.AttributeValueMemberS{Value: "open"}, "sum": &types.AttributeValueMemberN{Value: "12"}},
+		})
+
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedSessionService.EXPECT().Aggregate(mock.Anything, mock.MatchedBy(func(agg aggregate.Aggregation) bool {
+			return agg.Func == aggregate.Sum && agg.Value.String() == "amount" && agg.GroupBy.String() == "status"
+		}), "pk = $pk", scriptmanager.QueryOptions{
+			TableName:         "some-table",
+			NamePlaceholders:  map[string]string{"pk": "abc"},
+			ValuePlaceholders: map[string]types.AttributeValue{"pk": &types.AttributeValueMemberS{Value: "abc"}},
+		}).Return(rs, nil)
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "open = 12")
+
+		testFS := testScriptFile(t, "test.tm", `
+			res := session.aggregate("sum", {
+				value: "amount",
+				by: "status",
+				query: "pk = $pk",
+				table: "some-table",
+				args: {
+					pk: "abc",
+				},
+			})
+			ui.print(res[0].attr("status"), " = ", res[0].attr("sum"))
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI:      mockedUIService,
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		mockedUIService.AssertExpectations(t)
+		mockedSessionService.AssertExpectations(t)
+	})
+
+	t.Run("should return error if aggregation is invalid", func(t *testing.T) {
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedUIService := mocks.NewUIService(t)
+
+		testFS := testScriptFile(t, "test.tm", `
+			res := session.aggregate("sum")
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI:      mockedUIService,
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.Error(t, err)
+
+		mockedUIService.AssertExpectations(t)
+		mockedSessionService.AssertExpectations(t)
+	})
+}
+
+func TestModSession_BatchGet(t *testing.T) {
+	t.Run("should return items of keys", func(t *testing.T) {
+		rs := &models.ResultSet{}
+		rs.SetItems([]models.Item{
+			{"pk": &types.AttributeValueMemberS{Value: "abc"}, "sk": &types.AttributeValueMemberN{Value: "1"}},
+		})
+
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedSessionService.EXPECT().BatchGet(mock.Anything, []models.Item{
+			{"pk": &types.AttributeValueMemberS{Value: "abc"}, "sk": &types.AttributeValueMemberN{Value: "1"}},
+			{"pk": &types.AttributeValueMemberS{Value: "def"}, "sk": &types.AttributeValueMemberN{Value: "2"}},
+		}, scriptmanager.QueryOptions{
+			TableName: "some-table",
+		}).Return(rs, nil)
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "1")
+
+		testFS := testScriptFile(t, "test.tm", `
+			res := session.batch_get([
+				{pk: "abc", sk: 1},
+				{pk: "def", sk: 2},
+			], {table: "some-table"})
+			ui.print(res.length)
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI:      mockedUIService,
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		mockedUIService.AssertExpectations(t)
+		mockedSessionService.AssertExpectations(t)
+	})
+
+	t.Run("should return error if key is not a map or item", func(t *testing.T) {
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedUIService := mocks.NewUIService(t)
+
+		testFS := testScriptFile(t, "test.tm", `
+			res := session.batch_get(["abc"])
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI:      mockedUIService,
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.Error(t, err)
+
+		mockedUIService.AssertExpectations(t)
+		mockedSessionService.AssertExpectations(t)
+	})
+}
+
+func TestModSession_PutItems(t *testing.T) {
+	t.Run("should put dirty items of result set", func(t *testing.T) {
+		rs := &models.ResultSet{TableInfo: &models.TableInfo{Name: "some-table"}}
+		rs.SetItems([]models.Item{
+			{"pk": &types.AttributeValueMemberS{Value: "abc"}},
+		})
+
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedSessionService.EXPECT().ResultSet(mock.Anything).Return(rs)
+		mockedSessionService.EXPECT().PutItems(mock.Anything, rs, []int(nil)).Return(1, nil)
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "1")
+
+		testFS := testScriptFile(t, "test.tm", `
+			ui.print(session.put_items(session.result_set()))
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI:      mockedUIService,
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		mockedUIService.AssertExpectations(t)
+		mockedSessionService.AssertExpectations(t)
+	})
+
+	t.Run("should put listed items", func(t *testing.T) {
+		rs := &models.ResultSet{TableInfo: &models.TableInfo{Name: "some-table"}}
+		rs.SetItems([]models.Item{
+			{"pk": &types.AttributeValueMemberS{Value: "abc"}},
+			{"pk": &types.AttributeValueMemberS{Value: "def"}},
+			{"pk": &types.AttributeValueMemberS{Value: "ghi"}},
+		})
+
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedSessionService.EXPECT().ResultSet(mock.Anything).Return(rs)
+		mockedSessionService.EXPECT().PutItems(mock.Anything, rs, []int{2, 0}).Return(2, nil)
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "2")
+
+		testFS := testScriptFile(t, "test.tm", `
+			rs := session.result_set()
+			ui.print(session.put_items([rs[2], rs[0]]))
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI:      mockedUIService,
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		mockedUIService.AssertExpectations(t)
+		mockedSessionService.AssertExpectations(t)
+	})
+
+	t.Run("should return error if put fails", func(t *testing.T) {
+		rs := &models.ResultSet{TableInfo: &models.TableInfo{Name: "some-table"}}
+
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedSessionService.EXPECT().ResultSet(mock.Anything).Return(rs)
+		mockedSessionService.EXPECT().PutItems(mock.Anything, rs, []int(nil)).Return(0, models.ErrReadOnly)
+
+		mockedUIService := mocks.NewUIService(t)
+
+		testFS := testScriptFile(t, "test.tm", `
+			session.put_items(session.result_set())
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI:      mockedUIService,
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.Error(t, err)
+
+		mockedUIService.AssertExpectations(t)
+		mockedSessionService.AssertExpectations(t)
+	})
+}
+
+func TestModSession_DeleteItems(t *testing.T) {
+	t.Run("should delete items from the table they were read from", func(t *testing.T) {
+		rs := &models.ResultSet{TableInfo: &models.TableInfo{Name: "some-table"}}
+		rs.SetItems([]models.Item{
+			{"pk": &types.AttributeValueMemberS{Value: "abc"}},
+			{"pk": &types.AttributeValueMemberS{Value: "def"}},
+		})
+
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedSessionService.EXPECT().ResultSet(mock.Anything).Return(rs)
+		mockedSessionService.EXPECT().DeleteItems(mock.Anything, []models.Item{
+			{"pk": &types.AttributeValueMemberS{Value: "def"}},
+		}, scriptmanager.QueryOptions{
+			TableName: "some-table",
+		}).Return(nil)
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "1")
+
+		testFS := testScriptFile(t, "test.tm", `
+			rs := session.result_set()
+			ui.print(session.delete_items([rs[1]]))
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI:      mockedUIService,
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		mockedUIService.AssertExpectations(t)
+		mockedSessionService.AssertExpectations(t)
+	})
+
+	t.Run("should delete keys from the named table", func(t *testing.T) {
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedSessionService.EXPECT().DeleteItems(mock.Anything, []models.Item{
+			{"pk": &types.AttributeValueMemberS{Value: "abc"}, "sk": &types.AttributeValueMemberN{Value: "1"}},
+		}, scriptmanager.QueryOptions{
+			TableName: "other-table",
+		}).Return(nil)
+
+		mockedUIService := mocks.NewUIService(t)
+
+		testFS := testScriptFile(t, "test.tm", `
+			session.delete_items([{pk: "abc", sk: 1}], {table: "other-table"})
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI:      mockedUIService,
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		mockedUIService.AssertExpectations(t)
+		mockedSessionService.AssertExpectations(t)
+	})
+}
+
+func TestModSession_UpdateItem(t *testing.T) {
+	t.Run("should set and remove attributes of item", func(t *testing.T) {
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedSessionService.EXPECT().UpdateItem(mock.Anything,
+			models.Item{"pk": &types.AttributeValueMemberS{Value: "abc"}},
+			scriptmanager.ItemUpdate{
+				Set: map[string]types.AttributeValue{
+					"status": &types.AttributeValueMemberS{Value: "done"},
+					"count":  &types.AttributeValueMemberN{Value: "3"},
+				},
+				Remove: []string{"lock"},
+			},
+			scriptmanager.QueryOptions{TableName: "some-table"},
+		).Return(nil)
+
+		mockedUIService := mocks.NewUIService(t)
+
+		testFS := testScriptFile(t, "test.tm", `
+			session.update_item({pk: "abc"}, {
+				set: {status: "done", count: 3},
+				remove: ["lock"],
+				table: "some-table",
+			})
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI:      mockedUIService,
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.NoError(t, err)
+
+		mockedUIService.AssertExpectations(t)
+		mockedSessionService.AssertExpectations(t)
+	})
+
+	t.Run("should return error if nothing to update", func(t *testing.T) {
+		mockedSessionService := mocks.NewSessionService(t)
+		mockedUIService := mocks.NewUIService(t)
+
+		testFS := testScriptFile(t, "test.tm", `
+			session.update_item({pk: "abc"}, {})
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI:      mockedUIService,
+			Session: mockedSessionService,
+		})
+
+		ctx := context.Background()
+		err := <-srv.RunAdHocScript(ctx, "test.tm")
+		assert.Error(t, err)
+
+		mockedUIService.AssertExpectations(t)
+		mockedSessionService.AssertExpectations(t)
+	})
+}
+
 func TestModSession_SelectedItem(t *testing.T) {
 	t.Run("should return selected item from service implementation", func(t *testing.T) {
 		rs := &models.ResultSet{}
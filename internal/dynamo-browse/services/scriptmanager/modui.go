@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
 	"github.com/risor-io/risor/object"
 )
 
@@ -50,9 +51,120 @@ func (um *uiModule) prompt(ctx context.Context, args ...object.Object) object.Ob
 	}
 }
 
+func (um *uiModule) confirm(ctx context.Context, args ...object.Object) object.Object {
+	var msg string
+	if err := bindArgs("ui.confirm", args, &msg); err != nil {
+		return err
+	}
+
+	respChan := um.uiService.Confirm(ctx, msg)
+
+	select {
+	case resp, hasResp := <-respChan:
+		return object.NewBool(hasResp && resp)
+	case <-ctx.Done():
+		return object.NewError(ctx.Err())
+	}
+}
+
+func (um *uiModule) selectItem(ctx context.Context, args ...object.Object) object.Object {
+	if err := require("ui.select", 2, args); err != nil {
+		return err
+	}
+
+	title, objErr := object.AsString(args[0])
+	if objErr != nil {
+		return objErr
+	}
+	items, objErr := object.AsList(args[1])
+	if objErr != nil {
+		return objErr
+	}
+	if len(items.Value()) == 0 {
+		return object.Nil
+	}
+
+	respChan := um.uiService.Select(ctx, title, sliceutils.Map(items.Value(), displayString))
+
+	select {
+	case resp, hasResp := <-respChan:
+		if !hasResp || resp < 0 || resp >= len(items.Value()) {
+			return object.Nil
+		}
+		return items.Value()[resp]
+	case <-ctx.Done():
+		return object.NewError(ctx.Err())
+	}
+}
+
+// showTable displays a table of rows.  Each row can either be a list of cell values, in the same order as the
+// columns, or a map of cell values keyed by column name.
+func (um *uiModule) showTable(ctx context.Context, args ...object.Object) object.Object {
+	if err := require("ui.show_table", 2, args); err != nil {
+		return err
+	}
+
+	colList, objErr := object.AsList(args[0])
+	if objErr != nil {
+		return objErr
+	}
+	rowList, objErr := object.AsList(args[1])
+	if objErr != nil {
+		return objErr
+	}
+
+	columns := sliceutils.Map(colList.Value(), displayString)
+	rows := make([][]string, len(rowList.Value()))
+	for i, row := range rowList.Value() {
+		switch r := row.(type) {
+		case *object.List:
+			rows[i] = sliceutils.Map(r.Value(), displayString)
+		case *object.Map:
+			rows[i] = sliceutils.Map(columns, func(col string) string {
+				return displayString(r.Get(col))
+			})
+		default:
+			return object.Errorf("type error: expected row %d to be a list or map (got %v)", i, row.Type())
+		}
+	}
+
+	um.uiService.ShowTable(ctx, columns, rows)
+	return object.Nil
+}
+
+func (um *uiModule) showText(ctx context.Context, args ...object.Object) object.Object {
+	if err := require("ui.show_text", 2, args); err != nil {
+		return err
+	}
+
+	title, objErr := object.AsString(args[0])
+	if objErr != nil {
+		return objErr
+	}
+
+	um.uiService.ShowText(ctx, title, displayString(args[1]))
+	return object.Nil
+}
+
 func (um *uiModule) register() *object.Module {
 	return object.NewBuiltinsModule("ui", map[string]object.Object{
-		"print":  object.NewBuiltin("print", um.print),
-		"prompt": object.NewBuiltin("prompt", um.prompt),
+		"print":      object.NewBuiltin("print", um.print),
+		"prompt":     object.NewBuiltin("prompt", um.prompt),
+		"confirm":    object.NewBuiltin("confirm", um.confirm),
+		"select":     object.NewBuiltin("select", um.selectItem),
+		"show_table": object.NewBuiltin("show_table", um.showTable),
+		"show_text":  object.NewBuiltin("show_text", um.showText),
 	})
 }
+
+// displayString returns the string to display for a value.  Strings are displayed without quotes, and nil is
+// displayed as an empty string.
+func displayString(obj object.Object) string {
+	switch o := obj.(type) {
+	case *object.String:
+		return o.Value()
+	case *object.NilType:
+		return ""
+	}
+	return obj.Inspect()
+}
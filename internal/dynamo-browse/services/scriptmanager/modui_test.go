@@ -98,3 +98,145 @@ func TestModUI_Prompt(t *testing.T) {
 		mockedUIService.AssertExpectations(t)
 	})
 }
+
+func TestModUI_Confirm(t *testing.T) {
+	scenarios := []struct {
+		descr    string
+		respond  func(ch chan bool)
+		expected string
+	}{
+		{descr: "should return true if confirmed", respond: func(ch chan bool) { ch <- true }, expected: "confirmed: true"},
+		{descr: "should return false if not confirmed", respond: func(ch chan bool) { ch <- false }, expected: "confirmed: false"},
+		{descr: "should return false if cancelled", respond: func(ch chan bool) { close(ch) }, expected: "confirmed: false"},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.descr, func(t *testing.T) {
+			testFS := testScriptFile(t, "test.tm", `
+				var res = ui.confirm("Delete everything? ")
+				ui.print("confirmed: ", res)
+			`)
+
+			confirmChan := make(chan bool, 1)
+			scenario.respond(confirmChan)
+
+			mockedUIService := mocks.NewUIService(t)
+			mockedUIService.EXPECT().Confirm(mock.Anything, "Delete everything? ").Return(confirmChan)
+			mockedUIService.EXPECT().PrintMessage(mock.Anything, scenario.expected)
+
+			srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+			srv.SetIFaces(scriptmanager.Ifaces{
+				UI: mockedUIService,
+			})
+
+			err := <-srv.RunAdHocScript(context.Background(), "test.tm")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestModUI_Select(t *testing.T) {
+	t.Run("should return the selected item", func(t *testing.T) {
+		testFS := testScriptFile(t, "test.tm", `
+			var res = ui.select("Pick a number", ["one", 2, "three"])
+			ui.print("selected: ", res)
+		`)
+
+		selectChan := make(chan int, 1)
+		selectChan <- 1
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().Select(mock.Anything, "Pick a number", []string{"one", "2", "three"}).Return(selectChan)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "selected: 2")
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI: mockedUIService,
+		})
+
+		err := <-srv.RunAdHocScript(context.Background(), "test.tm")
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return nil if cancelled", func(t *testing.T) {
+		testFS := testScriptFile(t, "test.tm", `
+			var res = ui.select("Pick a number", ["one", "two"])
+			ui.print("selected: ", res)
+		`)
+
+		selectChan := make(chan int)
+		close(selectChan)
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().Select(mock.Anything, "Pick a number", []string{"one", "two"}).Return(selectChan)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "selected: nil")
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI: mockedUIService,
+		})
+
+		err := <-srv.RunAdHocScript(context.Background(), "test.tm")
+		assert.NoError(t, err)
+	})
+}
+
+func TestModUI_ShowTable(t *testing.T) {
+	t.Run("should show rows of lists and maps", func(t *testing.T) {
+		testFS := testScriptFile(t, "test.tm", `
+			ui.show_table(["name", "count"], [
+				["apples", 3],
+				{"name": "pears", "count": 5},
+				{"name": "plums"},
+			])
+		`)
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().ShowTable(mock.Anything, []string{"name", "count"}, [][]string{
+			{"apples", "3"},
+			{"pears", "5"},
+			{"plums", ""},
+		})
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI: mockedUIService,
+		})
+
+		err := <-srv.RunAdHocScript(context.Background(), "test.tm")
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return error if row is not a list or map", func(t *testing.T) {
+		testFS := testScriptFile(t, "test.tm", `
+			ui.show_table(["name"], ["apples"])
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI: mocks.NewUIService(t),
+		})
+
+		err := <-srv.RunAdHocScript(context.Background(), "test.tm")
+		assert.Error(t, err)
+	})
+}
+
+func TestModUI_ShowText(t *testing.T) {
+	t.Run("should show text with a title", func(t *testing.T) {
+		testFS := testScriptFile(t, "test.tm", `
+			ui.show_text("Report", "All good")
+		`)
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().ShowText(mock.Anything, "Report", "All good")
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI: mockedUIService,
+		})
+
+		err := <-srv.RunAdHocScript(context.Background(), "test.tm")
+		assert.NoError(t, err)
+	})
+}
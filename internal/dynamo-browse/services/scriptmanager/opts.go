@@ -19,8 +19,18 @@ func scriptEnvFromCtx(ctx context.Context) scriptEnv {
 	return perms
 }
 
+// isRunningScript returns true if ctx belongs to a running script.
+func isRunningScript(ctx context.Context) bool {
+	_, hasEnv := ctx.Value(scriptEnvKey).(scriptEnv)
+	return hasEnv
+}
+
 func ctxWithScriptEnv(ctx context.Context, perms scriptEnv) context.Context {
 	newCtx := context.WithValue(ctx, scriptEnvKey, perms)
-	newCtx = limits.WithLimits(newCtx, limits.New())
+
+	// Background jobs start with their own limits, which are kept
+	if _, hasLimits := limits.GetLimits(newCtx); !hasLimits {
+		newCtx = limits.WithLimits(newCtx, limits.New())
+	}
 	return newCtx
 }
@@ -190,6 +190,7 @@ func (i *resultSetProxy) merge(ctx context.Context, args ...object.Object) objec
 	newResultSet := &models.ResultSet{
 		Created:   time.Now(),
 		TableInfo: i.resultSet.TableInfo,
+		Projected: i.resultSet.Projected || otherRS.resultSet.Projected,
 	}
 	newResultSet.SetItems(newItems)
 
@@ -306,11 +307,11 @@ func (i *itemProxy) setValue(ctx context.Context, args ...object.Object) object.
 	if err != nil {
 		return object.NewError(err)
 	}
-	if err := path.SetEvalItem(i.item, newValue); err != nil {
+	if err := i.recordEdit(func() error {
+		return path.SetEvalItem(i.item, newValue)
+	}); err != nil {
 		return object.NewError(err)
 	}
-
-	i.resultSetProxy.resultSet.SetDirty(i.itemIndex, true)
 	return nil
 }
 
@@ -328,10 +329,22 @@ func (i *itemProxy) deleteAttr(ctx context.Context, args ...object.Object) objec
 	if err != nil {
 		return object.Errorf("arg error: invalid path expression: %v", err)
 	}
-	if err := modExpr.DeleteAttribute(i.item); err != nil {
+	if err := i.recordEdit(func() error {
+		return modExpr.DeleteAttribute(i.item)
+	}); err != nil {
 		return object.NewError(errors.Errorf("arg error: path expression evaluate error: %v", err))
 	}
+	return nil
+}
 
-	i.resultSetProxy.resultSet.SetDirty(i.itemIndex, true)
+// recordEdit modifies the item, recording the change in the edit history of the result set so that it can
+// be undone.
+func (i *itemProxy) recordEdit(modifyFn func() error) error {
+	edit := i.resultSetProxy.resultSet.RecordEdit()
+	edit.Modify(i.itemIndex)
+	if err := modifyFn(); err != nil {
+		return err
+	}
+	edit.Commit()
 	return nil
 }
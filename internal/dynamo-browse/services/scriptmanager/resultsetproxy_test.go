@@ -276,6 +276,7 @@ func TestResultSetProxy_SetAttr(t *testing.T) {
 			assert.Contains(t, strSet, "c")
 
 			assert.True(t, rs.IsDirty(0))
+			assert.True(t, rs.CanUndo())
 			return true
 		}))
 
@@ -327,6 +328,7 @@ func TestResultSetProxy_DeleteAttr(t *testing.T) {
 			assert.Equal(t, "abc", rs.Items()[0]["pk"].(*types.AttributeValueMemberS).Value)
 			assert.Nil(t, rs.Items()[0]["deleteMe"])
 			assert.True(t, rs.IsDirty(0))
+			assert.True(t, rs.CanUndo())
 			return true
 		}))
 
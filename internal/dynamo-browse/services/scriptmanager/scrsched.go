@@ -2,16 +2,30 @@ package scriptmanager
 
 import (
 	"context"
-	"github.com/pkg/errors"
+	"sync"
 	"time"
+
+	"github.com/pkg/errors"
+	"github.com/risor-io/risor/limits"
 )
 
+// maxRunningBackgroundJobs is the number of background jobs which can run at the same time.  Other background
+// jobs will wait in the queue until one of the running jobs has finished.
+const maxRunningBackgroundJobs = 4
+
 type scriptScheduler struct {
 	jobChan chan scriptJob
+
+	bgMutex     sync.Mutex
+	bgSlots     chan struct{}
+	bgJobs      []*backgroundJob
+	nextBgJobID int
 }
 
 func newScriptScheduler() *scriptScheduler {
-	ss := &scriptScheduler{}
+	ss := &scriptScheduler{
+		bgSlots: make(chan struct{}, maxRunningBackgroundJobs),
+	}
 	ss.start()
 	return ss
 }
@@ -47,7 +61,174 @@ func (ss *scriptScheduler) runNow(ctx context.Context, job func(ctx context.Cont
 	}
 }
 
+// startBackgroundJob starts a job which runs alongside the jobs of the script goroutine.  The job is given its
+// own context, with its own limits, which is cancelled when the job is cancelled.  The job should use run to do
+// any work, so that the number of running background jobs is limited.  Once the job returns, it is removed and
+// onDone is called with the returned error.
+func (ss *scriptScheduler) startBackgroundJob(
+	name string,
+	job func(ctx context.Context, bj *backgroundJob) error,
+	onDone func(err error),
+) *backgroundJob {
+	ctx, cancelFn := context.WithCancel(context.Background())
+	ctx = limits.WithLimits(ctx, limits.New())
+
+	ss.bgMutex.Lock()
+	ss.nextBgJobID++
+	bj := &backgroundJob{
+		id:       ss.nextBgJobID,
+		name:     name,
+		sched:    ss,
+		cancelFn: cancelFn,
+		started:  time.Now(),
+		state:    BackgroundJobQueued,
+	}
+	ss.bgJobs = append(ss.bgJobs, bj)
+	ss.bgMutex.Unlock()
+
+	go func() {
+		err := job(ctx, bj)
+		cancelFn()
+		ss.removeBackgroundJob(bj)
+
+		if onDone != nil {
+			onDone(err)
+		}
+	}()
+	return bj
+}
+
+func (ss *scriptScheduler) removeBackgroundJob(bj *backgroundJob) {
+	ss.bgMutex.Lock()
+	defer ss.bgMutex.Unlock()
+
+	for i, j := range ss.bgJobs {
+		if j == bj {
+			ss.bgJobs = append(ss.bgJobs[:i], ss.bgJobs[i+1:]...)
+			return
+		}
+	}
+}
+
+// backgroundJobs returns the background jobs which have not yet finished, in the order they were started.
+func (ss *scriptScheduler) backgroundJobs() []BackgroundJob {
+	ss.bgMutex.Lock()
+	defer ss.bgMutex.Unlock()
+
+	jobs := make([]BackgroundJob, len(ss.bgJobs))
+	for i, bj := range ss.bgJobs {
+		jobs[i] = bj.info()
+	}
+	return jobs
+}
+
+// cancelBackgroundJob cancels the background job with the given ID.  A job which is running a script function
+// will stop once the function returns.
+func (ss *scriptScheduler) cancelBackgroundJob(id int) error {
+	ss.bgMutex.Lock()
+	defer ss.bgMutex.Unlock()
+
+	for _, bj := range ss.bgJobs {
+		if bj.id == id {
+			bj.cancelFn()
+			return nil
+		}
+	}
+	return errors.Errorf("no background job with ID %d", id)
+}
+
 type scriptJob struct {
 	ctx context.Context
 	job func(ctx context.Context)
 }
+
+type BackgroundJobState string
+
+const (
+	BackgroundJobQueued  BackgroundJobState = "queued"
+	BackgroundJobRunning BackgroundJobState = "running"
+	BackgroundJobWaiting BackgroundJobState = "waiting"
+)
+
+// BackgroundJob describes a background job which has not yet finished.
+type BackgroundJob struct {
+	ID      int
+	Name    string
+	State   BackgroundJobState
+	Started time.Time
+
+	// NextRun is the time the job will next run while the job is waiting
+	NextRun time.Time
+
+	// Runs is the number of times the job has run
+	Runs int
+}
+
+type backgroundJob struct {
+	id       int
+	name     string
+	sched    *scriptScheduler
+	cancelFn context.CancelFunc
+	started  time.Time
+
+	mutex   sync.Mutex
+	state   BackgroundJobState
+	nextRun time.Time
+	runs    int
+}
+
+// run waits until fewer than the maximum number of background jobs are running, then calls fn.
+func (bj *backgroundJob) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	bj.setState(BackgroundJobQueued, time.Time{})
+
+	select {
+	case bj.sched.bgSlots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-bj.sched.bgSlots }()
+
+	bj.mutex.Lock()
+	bj.state = BackgroundJobRunning
+	bj.runs++
+	bj.mutex.Unlock()
+
+	return fn(ctx)
+}
+
+// wait waits for the duration, or until the job is cancelled.
+func (bj *backgroundJob) wait(ctx context.Context, d time.Duration) error {
+	bj.setState(BackgroundJobWaiting, time.Now().Add(d))
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (bj *backgroundJob) setState(state BackgroundJobState, nextRun time.Time) {
+	bj.mutex.Lock()
+	defer bj.mutex.Unlock()
+
+	bj.state = state
+	bj.nextRun = nextRun
+}
+
+func (bj *backgroundJob) info() BackgroundJob {
+	bj.mutex.Lock()
+	defer bj.mutex.Unlock()
+
+	return BackgroundJob{
+		ID:      bj.id,
+		Name:    bj.name,
+		State:   bj.state,
+		Started: bj.started,
+		NextRun: bj.nextRun,
+		Runs:    bj.runs,
+	}
+}
@@ -61,8 +61,11 @@ func (s *Service) LoadScript(ctx context.Context, filename string) (*ScriptPlugi
 	// Look for the previous version.  If one is there, replace it, otherwise add it
 	// TODO: this should probably be protected by a mutex
 	newPlugin := res.scriptPlugin
+	s.startEveryJobs(newPlugin)
+
 	for i, p := range s.plugins {
 		if p.name == newPlugin.name {
+			p.cancelBackgroundJobs()
 			s.plugins[i] = newPlugin
 			return newPlugin, nil
 		}
@@ -120,6 +123,7 @@ func (s *Service) loadScript(ctx context.Context, filename string, resChan chan
 	newPlugin := &ScriptPlugin{
 		name:          strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)),
 		scriptService: s,
+		vmLock:        make(chan struct{}, 1),
 	}
 
 	ctx = ctxWithScriptEnv(ctx, scriptEnv{filename: filepath.Base(filename)})
@@ -137,6 +137,102 @@ func testScriptFile(t *testing.T, filename, code string) fs.FS {
 	return testFs
 }
 
+func TestService_StartBackgroundScript(t *testing.T) {
+	t.Run("should run without waiting for the foreground script", func(t *testing.T) {
+		testFS := fstest.MapFS{
+			"fg.tm": &fstest.MapFile{Data: []byte(`
+				var name = ui.prompt("What is your name? ")
+				ui.print("Hello, " + name)
+			`)},
+			"bg.tm": &fstest.MapFile{Data: []byte(`
+				ui.print("In the background")
+			`)},
+		}
+
+		promptChan := make(chan string)
+		prompted := make(chan struct{})
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().Prompt(mock.Anything, "What is your name? ").Run(func(ctx context.Context, msg string) {
+			close(prompted)
+		}).Return(promptChan)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "In the background")
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "Hello, T. Test")
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI: mockedUIService,
+		})
+
+		ctx := context.Background()
+		fgErrChan := srv.RunAdHocScript(ctx, "fg.tm")
+		<-prompted
+
+		bgErrChan := make(chan error)
+		assert.NoError(t, srv.StartBackgroundScript(ctx, "bg.tm", bgErrChan))
+		assert.NoError(t, waitForErr(t, bgErrChan))
+
+		promptChan <- "T. Test"
+		assert.NoError(t, waitForErr(t, fgErrChan))
+
+		mockedUIService.AssertExpectations(t)
+	})
+
+	t.Run("should start the functions scheduled by the script", func(t *testing.T) {
+		testFS := testScriptFile(t, "test.tm", `
+			ext.every("1s", func() {
+				ui.print("tick")
+				return false
+			})
+		`)
+
+		mockedUIService := mocks.NewUIService(t)
+		mockedUIService.EXPECT().PrintMessage(mock.Anything, "tick").Once()
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+		srv.SetIFaces(scriptmanager.Ifaces{
+			UI: mockedUIService,
+		})
+
+		errChan := make(chan error)
+		assert.NoError(t, srv.StartBackgroundScript(context.Background(), "test.tm", errChan))
+		assert.NoError(t, waitForErr(t, errChan))
+
+		jobs := srv.BackgroundJobs()
+		assert.Len(t, jobs, 1)
+		assert.Equal(t, "test: every 1s", jobs[0].Name)
+
+		assert.Eventually(t, func() bool {
+			return len(srv.BackgroundJobs()) == 0
+		}, 5*time.Second, 50*time.Millisecond)
+		mockedUIService.AssertExpectations(t)
+	})
+
+	t.Run("should list and cancel background scripts", func(t *testing.T) {
+		testFS := testScriptFile(t, "test.tm", `
+			for {
+			}
+		`)
+
+		srv := scriptmanager.New(scriptmanager.WithFS(testFS))
+
+		errChan := make(chan error)
+		assert.NoError(t, srv.StartBackgroundScript(context.Background(), "test.tm", errChan))
+
+		jobs := srv.BackgroundJobs()
+		assert.Len(t, jobs, 1)
+		assert.Equal(t, "test.tm", jobs[0].Name)
+
+		assert.NoError(t, srv.CancelBackgroundJob(jobs[0].ID))
+		assert.NoError(t, waitForErr(t, errChan))
+
+		assert.Eventually(t, func() bool {
+			return len(srv.BackgroundJobs()) == 0
+		}, 5*time.Second, 50*time.Millisecond)
+		assert.Error(t, srv.CancelBackgroundJob(jobs[0].ID))
+	})
+}
+
 func waitForErr(t *testing.T, errChan chan error) error {
 	t.Helper()
 
@@ -69,6 +69,36 @@ func (t *tableProxy) GetAttr(name string) (object.Object, bool) {
 		}), true
 	case "gsis":
 		return object.NewList(sliceutils.Map(t.table.GSIs, newTableIndexProxy)), true
+	case "lsis":
+		return object.NewList(sliceutils.Map(t.table.LSIs, newTableLocalIndexProxy)), true
+	case "arn":
+		return object.NewString(t.table.ARN), true
+	case "status":
+		return object.NewString(t.table.Status), true
+	case "billing_mode":
+		return object.NewString(t.table.BillingMode), true
+	case "capacity":
+		return object.NewMap(map[string]object.Object{
+			"read":  object.NewInt(t.table.ReadCapacity),
+			"write": object.NewInt(t.table.WriteCapacity),
+		}), true
+	case "item_count":
+		return object.NewInt(t.table.ItemCount), true
+	case "size_bytes":
+		return object.NewInt(t.table.SizeBytes), true
+	case "ttl":
+		return object.NewMap(map[string]object.Object{
+			"status":    object.NewString(t.table.TTL.Status),
+			"attribute": object.NewString(t.table.TTL.AttributeName),
+		}), true
+	case "stream":
+		return object.NewMap(map[string]object.Object{
+			"enabled":   object.NewBool(t.table.Stream.Enabled),
+			"view_type": object.NewString(t.table.Stream.ViewType),
+			"arn":       object.NewString(t.table.Stream.ARN),
+		}), true
+	case "pitr":
+		return object.NewString(t.table.PITRStatus), true
 	}
 
 	return nil, false
@@ -79,7 +109,39 @@ func (t *tableProxy) IsTruthy() bool {
 }
 
 type tableIndexProxy struct {
-	gsi models.TableGSI
+	indexType  string
+	name       string
+	keys       models.KeyAttribute
+	status     string
+	projection models.TableProjection
+	itemCount  int64
+	sizeBytes  int64
+	index      any
+}
+
+func newTableIndexProxy(gsi models.TableGSI) object.Object {
+	return tableIndexProxy{
+		indexType:  "gsi",
+		name:       gsi.Name,
+		keys:       gsi.Keys,
+		status:     gsi.Status,
+		projection: gsi.Projection,
+		itemCount:  gsi.ItemCount,
+		sizeBytes:  gsi.SizeBytes,
+		index:      gsi,
+	}
+}
+
+func newTableLocalIndexProxy(lsi models.TableLSI) object.Object {
+	return tableIndexProxy{
+		indexType:  "lsi",
+		name:       lsi.Name,
+		keys:       lsi.Keys,
+		projection: lsi.Projection,
+		itemCount:  lsi.ItemCount,
+		sizeBytes:  lsi.SizeBytes,
+		index:      lsi,
+	}
 }
 
 func (t tableIndexProxy) SetAttr(name string, value object.Object) error {
@@ -94,20 +156,16 @@ func (t tableIndexProxy) Cost() int {
 	return 0
 }
 
-func newTableIndexProxy(gsi models.TableGSI) object.Object {
-	return tableIndexProxy{gsi: gsi}
-}
-
 func (t tableIndexProxy) Type() object.Type {
 	return "table_index"
 }
 
 func (t tableIndexProxy) Inspect() string {
-	return "table_index(gsi," + t.gsi.Name + ")"
+	return "table_index(" + t.indexType + "," + t.name + ")"
 }
 
 func (t tableIndexProxy) Interface() interface{} {
-	return t.gsi
+	return t.index
 }
 
 func (t tableIndexProxy) Equals(other object.Object) object.Object {
@@ -116,18 +174,28 @@ func (t tableIndexProxy) Equals(other object.Object) object.Object {
 		return object.False
 	}
 
-	return object.NewBool(reflect.DeepEqual(t.gsi, otherIP.gsi))
+	return object.NewBool(reflect.DeepEqual(t.index, otherIP.index))
 }
 
 func (t tableIndexProxy) GetAttr(name string) (object.Object, bool) {
 	switch name {
 	case "name":
-		return object.NewString(t.gsi.Name), true
+		return object.NewString(t.name), true
+	case "type":
+		return object.NewString(t.indexType), true
 	case "keys":
 		return object.NewMap(map[string]object.Object{
-			tableProxyPartitionKey: object.NewString(t.gsi.Keys.PartitionKey),
-			tableProxySortKey:      object.NewString(t.gsi.Keys.SortKey),
+			tableProxyPartitionKey: object.NewString(t.keys.PartitionKey),
+			tableProxySortKey:      object.NewString(t.keys.SortKey),
 		}), true
+	case "status":
+		return object.NewString(t.status), true
+	case "projection":
+		return newProjectionObject(t.projection), true
+	case "item_count":
+		return object.NewInt(t.itemCount), true
+	case "size_bytes":
+		return object.NewInt(t.sizeBytes), true
 	}
 
 	return nil, false
@@ -136,3 +204,10 @@ func (t tableIndexProxy) GetAttr(name string) (object.Object, bool) {
 func (t tableIndexProxy) IsTruthy() bool {
 	return true
 }
+
+func newProjectionObject(projection models.TableProjection) object.Object {
+	return object.NewMap(map[string]object.Object{
+		"type":       object.NewString(projection.Type),
+		"attributes": object.NewStringList(projection.NonKeyAttributes),
+	})
+}
@@ -2,8 +2,17 @@ package scriptmanager
 
 import (
 	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/risor-io/risor/object"
 )
 
+// pluginBusyTimeout is the maximum time to wait for a script function of a plugin to finish running, before
+// another function of the same plugin can be called.
+const pluginBusyTimeout = 5 * time.Second
+
 type ScriptPlugin struct {
 	scriptService      *Service
 	name               string
@@ -11,6 +20,62 @@ type ScriptPlugin struct {
 	definedKeyBindings map[string]*Command
 	keyToKeyBinding    map[string]string
 	relatedItems       []*relatedItemBuilder
+	eventHandlers      map[string][]eventHandler
+	definedColumns     []*scriptColumn
+	renderers          []*scriptRenderer
+	everyJobs          []*scriptEvery
+
+	// vmLock is held while a function of the plugin is running, as the plugin VM can only run one function at a time
+	vmLock chan struct{}
+
+	// bgJobs are the background jobs started for the plugin's scheduled functions
+	bgJobs []*backgroundJob
+}
+
+// pluginBusyError is returned when a function of a plugin cannot be called, as another function of the plugin
+// is still running.  Callers which run functions in the background can skip the call and try again later.
+type pluginBusyError struct {
+	pluginName string
+}
+
+func (e pluginBusyError) Error() string {
+	return fmt.Sprintf("script '%v' is busy", e.pluginName)
+}
+
+func isPluginBusy(err error) bool {
+	var busyErr pluginBusyError
+	return errors.As(err, &busyErr)
+}
+
+type heldPluginKeyType struct {
+	plugin *ScriptPlugin
+}
+
+// call calls a function of the plugin, waiting for any other function of the plugin running on another goroutine
+// to finish.  Functions called from a function of the plugin are called immediately.
+func (sp *ScriptPlugin) call(ctx context.Context, callFn object.CallFunc, fn *object.Function, args []object.Object) (object.Object, error) {
+	heldKey := heldPluginKeyType{plugin: sp}
+	if ctx.Value(heldKey) == nil {
+		select {
+		case sp.vmLock <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pluginBusyTimeout):
+			return nil, pluginBusyError{pluginName: sp.name}
+		}
+		defer func() { <-sp.vmLock }()
+
+		ctx = context.WithValue(ctx, heldKey, true)
+	}
+	return callFn(ctx, fn, args)
+}
+
+// cancelBackgroundJobs cancels the background jobs started for the plugin.
+func (sp *ScriptPlugin) cancelBackgroundJobs() {
+	for _, bj := range sp.bgJobs {
+		bj.cancelFn()
+	}
+	sp.bgJobs = nil
 }
 
 func (sp *ScriptPlugin) Name() string {
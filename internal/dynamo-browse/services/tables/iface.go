@@ -8,11 +8,19 @@ import (
 )
 
 type TableProvider interface {
+	Connection() models.Connection
+	Connect(ctx context.Context, conn models.Connection) error
 	ListTables(ctx context.Context) ([]string, error)
+	ListTablesOfConnection(ctx context.Context, conn models.Connection) ([]string, error)
 	DescribeTable(ctx context.Context, tableName string) (*models.TableInfo, error)
+	DescribeTableInFull(ctx context.Context, tableName string) (*models.TableInfo, error)
 	DeleteItem(ctx context.Context, tableName string, key map[string]types.AttributeValue) error
+	DeleteItems(ctx context.Context, name string, keys []map[string]types.AttributeValue) error
+	UpdateItem(ctx context.Context, tableName string, key map[string]types.AttributeValue, updateExpr expression.Expression) error
 	PutItem(ctx context.Context, name string, item models.Item) error
 	PutItems(ctx context.Context, name string, items []models.Item) error
+	PutItemsConditionally(ctx context.Context, tableInfo *models.TableInfo, puts []models.ConditionalPut) ([]int, error)
+	TransactPutItems(ctx context.Context, tableInfo *models.TableInfo, puts []models.ConditionalPut) ([]int, error)
 
 	QueryItems(
 		ctx context.Context,
@@ -21,17 +29,52 @@ type TableProvider interface {
 		filterExpr *expression.Expression,
 		exclusiveStartKey map[string]types.AttributeValue,
 		maxItems int,
+		readOptions models.ReadOptions,
 	) (items []models.Item, lastEvaluatedKey map[string]types.AttributeValue, err error)
-	ScanItems(
+	BatchGetItems(
+		ctx context.Context,
+		tableName string,
+		keys []map[string]types.AttributeValue,
+		readOptions models.ReadOptions,
+	) ([]models.Item, error)
+	CountItems(
+		ctx context.Context,
+		tableName string,
+		indexName string,
+		filterExpr *expression.Expression,
+		runAsQuery bool,
+		readOptions models.ReadOptions,
+	) (int64, error)
+	StreamItems(
+		ctx context.Context,
+		tableName string,
+		indexName string,
+		filterExpr *expression.Expression,
+		runAsQuery bool,
+		readOptions models.ReadOptions,
+		onPage func(items []models.Item) error,
+	) error
+	ExecuteStatement(
+		ctx context.Context,
+		statement string,
+		exclusiveStartKey map[string]types.AttributeValue,
+		maxItems int,
+		readOptions models.ReadOptions,
+	) (items []models.Item, lastEvaluatedKey map[string]types.AttributeValue, err error)
+	ScanItemsInSegments(
 		ctx context.Context,
 		tableName string,
 		filterExpr *expression.Expression,
 		exclusiveStartKey map[string]types.AttributeValue,
 		maxItems int,
+		totalSegments int,
+		readOptions models.ReadOptions,
 	) (item []models.Item, lastEvaluatedKey map[string]types.AttributeValue, err error)
 }
 
 type ConfigProvider interface {
 	IsReadOnly() (bool, error)
 	DefaultLimit() int
+	ScanSegments() int
+	PutMode() models.PutMode
 }
@@ -7,11 +7,15 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/lmika/dynamo-browse/internal/common/sliceutils"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/jobs"
+	"golang.org/x/exp/maps"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/aggregate"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/queryexpr"
 	"github.com/pkg/errors"
 )
 
@@ -27,14 +31,35 @@ func NewService(provider TableProvider, roProvider ConfigProvider) *Service {
 	}
 }
 
+// Connection returns the details of the connection currently in use.
+func (s *Service) Connection() models.Connection {
+	return s.provider.Connection()
+}
+
+// Connect switches to a new connection.
+func (s *Service) Connect(ctx context.Context, conn models.Connection) error {
+	return s.provider.Connect(ctx, conn)
+}
+
 func (s *Service) ListTables(ctx context.Context) ([]string, error) {
 	return s.provider.ListTables(ctx)
 }
 
+// ListTablesOfConnection lists the tables of a connection without switching to it.
+func (s *Service) ListTablesOfConnection(ctx context.Context, conn models.Connection) ([]string, error) {
+	return s.provider.ListTablesOfConnection(ctx, conn)
+}
+
 func (s *Service) Describe(ctx context.Context, table string) (*models.TableInfo, error) {
 	return s.provider.DescribeTable(ctx, table)
 }
 
+// DescribeInFull returns the table description, including details which are too expensive to fetch each
+// time the table is described.
+func (s *Service) DescribeInFull(ctx context.Context, table string) (*models.TableInfo, error) {
+	return s.provider.DescribeTableInFull(ctx, table)
+}
+
 func (s *Service) Scan(ctx context.Context, tableInfo *models.TableInfo) (*models.ResultSet, error) {
 	return s.doScan(ctx, tableInfo, nil, nil, s.configProvider.DefaultLimit())
 }
@@ -47,10 +72,15 @@ func (s *Service) doScan(
 	limit int,
 ) (*models.ResultSet, error) {
 	var (
-		filterExpr *expression.Expression
-		runAsQuery bool
-		index      string
-		err        error
+		filterExpr   *expression.Expression
+		itemFilter   func(item models.Item) (bool, error)
+		projected    bool
+		runAsQuery   bool
+		statement    string
+		index        string
+		readOptions  models.ReadOptions
+		sortCriteria = models.PKSKSortFilter(tableInfo)
+		err          error
 	)
 	if expr != nil {
 		plan, err := expr.Plan(tableInfo)
@@ -59,8 +89,19 @@ func (s *Service) doScan(
 		}
 
 		runAsQuery = plan.CanQuery
+		statement = plan.Statement
 		index = plan.IndexName
 		filterExpr = &plan.Expression
+		itemFilter = plan.ItemFilter
+		projected = plan.Expression.Projection() != nil
+		readOptions = plan.ReadOptions
+		if plan.Limit > 0 {
+			limit = plan.Limit
+		}
+		if plan.ReadOptions.Descending && len(sortCriteria.Fields) > 1 {
+			// Keep the items in the order they were read, with the latest sort keys first
+			sortCriteria.Fields[1].Asc = false
+		}
 
 		log.Printf("Running query over '%v'", tableInfo.Name)
 		plan.Describe(log.Default())
@@ -70,10 +111,19 @@ func (s *Service) doScan(
 
 	var results []models.Item
 	var lastEvalKey map[string]types.AttributeValue
-	if runAsQuery {
-		results, lastEvalKey, err = s.provider.QueryItems(ctx, tableInfo.Name, index, filterExpr, exclusiveStartKey, limit)
+	if statement != "" {
+		results, lastEvalKey, err = s.provider.ExecuteStatement(ctx, statement, exclusiveStartKey, limit, readOptions)
+	} else if runAsQuery {
+		results, lastEvalKey, err = s.provider.QueryItems(ctx, tableInfo.Name, index, filterExpr, exclusiveStartKey, limit, readOptions)
 	} else {
-		results, lastEvalKey, err = s.provider.ScanItems(ctx, tableInfo.Name, filterExpr, exclusiveStartKey, limit)
+		results, lastEvalKey, err = s.provider.ScanItemsInSegments(ctx, tableInfo.Name, filterExpr, exclusiveStartKey, limit, s.configProvider.ScanSegments(), readOptions)
+	}
+
+	if itemFilter != nil {
+		var filterErr error
+		if results, filterErr = filterItems(results, itemFilter); filterErr != nil {
+			return nil, filterErr
+		}
 	}
 
 	if err != nil && len(results) == 0 {
@@ -82,6 +132,7 @@ func (s *Service) doScan(
 			Created:           time.Now(),
 			Query:             expr,
 			ExclusiveStartKey: exclusiveStartKey,
+			Projected:         projected,
 			LastEvaluatedKey:  lastEvalKey,
 		}, errors.Wrapf(err, "unable to scan table %v", tableInfo.Name)
 	}
@@ -91,15 +142,274 @@ func (s *Service) doScan(
 		Created:           time.Now(),
 		Query:             expr,
 		ExclusiveStartKey: exclusiveStartKey,
+		Projected:         projected,
 		LastEvaluatedKey:  lastEvalKey,
 	}
 	resultSet.SetItems(results)
 	resultSet.RefreshColumns()
+	resultSet.Sort(sortCriteria)
+
+	return resultSet, err
+}
+
+// BatchGet returns a result set of the items with the given keys.  Keys of items which do not exist in the
+// table are ignored.
+func (s *Service) BatchGet(ctx context.Context, tableInfo *models.TableInfo, keys []map[string]types.AttributeValue) (*models.ResultSet, error) {
+	log.Printf("Getting %d items from '%v'", len(keys), tableInfo.Name)
+
+	results, err := s.provider.BatchGetItems(ctx, tableInfo.Name, keys, models.ReadOptions{})
+	if err != nil && len(results) == 0 {
+		return nil, errors.Wrapf(err, "unable to get items from table %v", tableInfo.Name)
+	}
+
+	resultSet := &models.ResultSet{
+		TableInfo: tableInfo,
+		Created:   time.Now(),
+	}
+	resultSet.SetItems(results)
+	resultSet.RefreshColumns()
 	resultSet.Sort(models.PKSKSortFilter(tableInfo))
 
 	return resultSet, err
 }
 
+// Aggregate applies the aggregation to all the items matching the query, or all the items of the table if query
+// is nil.  The items are read page by page and are not retained.  The result is returned as a result set with
+// one item per group.
+func (s *Service) Aggregate(ctx context.Context, tableInfo *models.TableInfo, query models.Queryable, agg aggregate.Aggregation) (*models.ResultSet, error) {
+	plan, err := s.planStream(tableInfo, query, "aggregates")
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Running aggregate %v over '%v'", agg.Func, tableInfo.Name)
+
+	acc := agg.NewAccumulator()
+	if !agg.NeedsItems() {
+		count, err := s.countItems(ctx, tableInfo, plan)
+		if err != nil {
+			return nil, err
+		}
+		acc.AddCount(count)
+	} else {
+		if err := s.streamItems(ctx, tableInfo, plan, func(items []models.Item) error {
+			return acc.Add(items...)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return acc.ResultSet(tableInfo), nil
+}
+
+// Count returns the number of items matching the query, or the number of items in the table if query is nil.
+func (s *Service) Count(ctx context.Context, tableInfo *models.TableInfo, query models.Queryable) (int64, error) {
+	plan, err := s.planStream(tableInfo, query, "counts")
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("Counting items of '%v'", tableInfo.Name)
+
+	return s.countItems(ctx, tableInfo, plan)
+}
+
+// BulkDelete deletes all the items matching the query.  The items are read and deleted page by page.  Returns the
+// number of items deleted, which is also set if an error occurs or the context is cancelled part way through.
+func (s *Service) BulkDelete(ctx context.Context, tableInfo *models.TableInfo, query models.Queryable) (int, error) {
+	if err := s.assertReadWrite(); err != nil {
+		return 0, err
+	}
+
+	plan, err := s.planStream(tableInfo, query, "bulk deletes")
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("Running bulk delete over '%v'", tableInfo.Name)
+
+	var (
+		deleted    int
+		nextUpdate = time.Now().Add(1 * time.Second)
+	)
+	err = s.streamItems(ctx, tableInfo, plan, func(items []models.Item) error {
+		keys := sliceutils.Map(items, func(item models.Item) map[string]types.AttributeValue {
+			return item.KeyValue(tableInfo)
+		})
+		if err := s.provider.DeleteItems(ctx, tableInfo.Name, keys); err != nil {
+			return err
+		}
+
+		deleted += len(keys)
+		if time.Now().After(nextUpdate) {
+			jobs.PostUpdate(ctx, fmt.Sprintf("deleted %d items", deleted))
+			nextUpdate = time.Now().Add(1 * time.Second)
+		}
+		return nil
+	})
+	return deleted, bulkError(ctx, err)
+}
+
+// AttributeSet sets an attribute to the value of an expression.
+type AttributeSet struct {
+	Name  string
+	Value *queryexpr.QueryExpr
+}
+
+// BulkUpdate applies the sets to all the items matching the query.  The items are read page by page, with each
+// item updated in place.  Items which are deleted before they're updated are skipped.  Returns the number of items
+// updated, which is also set if an error occurs or the context is cancelled part way through.
+func (s *Service) BulkUpdate(ctx context.Context, tableInfo *models.TableInfo, query models.Queryable, sets []AttributeSet) (int, error) {
+	if err := s.assertReadWrite(); err != nil {
+		return 0, err
+	} else if len(sets) == 0 {
+		return 0, errors.New("no attributes to set")
+	}
+
+	var update expression.UpdateBuilder
+	for _, set := range sets {
+		if set.Name == tableInfo.Keys.PartitionKey || set.Name == tableInfo.Keys.SortKey {
+			return 0, errors.Errorf("cannot update key attribute '%v'", set.Name)
+		}
+
+		val, err := set.Value.UpdateValue(tableInfo)
+		if err != nil {
+			return 0, errors.Wrapf(err, "cannot set '%v'", set.Name)
+		}
+		update = update.Set(expression.Name(set.Name), val)
+	}
+	updateExpr, err := expression.NewBuilder().
+		WithUpdate(update).
+		WithCondition(expression.AttributeExists(expression.Name(tableInfo.Keys.PartitionKey))).
+		Build()
+	if err != nil {
+		return 0, err
+	}
+
+	plan, err := s.planStream(tableInfo, query, "bulk updates")
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("Running bulk update over '%v'", tableInfo.Name)
+
+	var (
+		updated    int
+		nextUpdate = time.Now().Add(1 * time.Second)
+	)
+	err = s.streamItems(ctx, tableInfo, plan, func(items []models.Item) error {
+		for _, item := range items {
+			if err := s.provider.UpdateItem(ctx, tableInfo.Name, item.KeyValue(tableInfo), updateExpr); err != nil {
+				var ccfe *types.ConditionalCheckFailedException
+				if errors.As(err, &ccfe) {
+					continue
+				}
+				return err
+			}
+
+			updated++
+			if time.Now().After(nextUpdate) {
+				jobs.PostUpdate(ctx, fmt.Sprintf("updated %d items", updated))
+				nextUpdate = time.Now().Add(1 * time.Second)
+			}
+		}
+		return nil
+	})
+	return updated, bulkError(ctx, err)
+}
+
+// bulkError returns the context error if the context was cancelled, so that the cancellation can be distinguished
+// from other errors.
+func bulkError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+type streamPlan struct {
+	filterExpr  *expression.Expression
+	itemFilter  func(item models.Item) (bool, error)
+	runAsQuery  bool
+	index       string
+	readOptions models.ReadOptions
+}
+
+// planStream plans a query or scan over all the items matching the query, or all the items of the table if
+// query is nil.  PartiQL statements cannot be streamed, so what describes the operation in the returned error.
+// Queries which limit the items read, or select some of their attributes, are also refused, as every matching
+// item is streamed in full.
+func (s *Service) planStream(tableInfo *models.TableInfo, query models.Queryable, what string) (streamPlan, error) {
+	if query == nil {
+		return streamPlan{}, nil
+	}
+
+	plan, err := query.Plan(tableInfo)
+	if err != nil {
+		return streamPlan{}, err
+	} else if plan.Statement != "" {
+		return streamPlan{}, errors.Errorf("%v cannot be used with PartiQL statements", what)
+	} else if plan.Limit > 0 {
+		return streamPlan{}, errors.New("limit() cannot be used, as all the matching items are read")
+	} else if plan.Expression.Projection() != nil {
+		return streamPlan{}, errors.New("select() cannot be used, as all the attributes of the items are read")
+	}
+	plan.Describe(log.Default())
+
+	sp := streamPlan{
+		filterExpr:  &plan.Expression,
+		itemFilter:  plan.ItemFilter,
+		runAsQuery:  plan.CanQuery,
+		readOptions: plan.ReadOptions,
+	}
+	if plan.CanQuery {
+		sp.index = plan.IndexName
+	}
+	return sp, nil
+}
+
+// streamItems reads the items of the stream plan page by page, calling fn with the items of each page.
+func (s *Service) streamItems(ctx context.Context, tableInfo *models.TableInfo, plan streamPlan, fn func(items []models.Item) error) error {
+	return s.provider.StreamItems(ctx, tableInfo.Name, plan.index, plan.filterExpr, plan.runAsQuery, plan.readOptions, func(items []models.Item) error {
+		if plan.itemFilter == nil {
+			return fn(items)
+		}
+
+		items, err := filterItems(items, plan.itemFilter)
+		if err != nil {
+			return err
+		} else if len(items) == 0 {
+			return nil
+		}
+		return fn(items)
+	})
+}
+
+// countItems returns the number of items of the stream plan.  Items which need to be filtered once they're read
+// are counted by reading them.
+func (s *Service) countItems(ctx context.Context, tableInfo *models.TableInfo, plan streamPlan) (int64, error) {
+	if plan.itemFilter == nil {
+		return s.provider.CountItems(ctx, tableInfo.Name, plan.index, plan.filterExpr, plan.runAsQuery, plan.readOptions)
+	}
+
+	var count int64
+	err := s.streamItems(ctx, tableInfo, plan, func(items []models.Item) error {
+		count += int64(len(items))
+		return nil
+	})
+	return count, err
+}
+
+// filterItems returns the items which match the item filter.
+func filterItems(items []models.Item, itemFilter func(item models.Item) (bool, error)) ([]models.Item, error) {
+	filtered := make([]models.Item, 0, len(items))
+	for _, item := range items {
+		isMatch, err := itemFilter(item)
+		if err != nil {
+			return nil, err
+		} else if isMatch {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
 func (s *Service) Put(ctx context.Context, tableInfo *models.TableInfo, item models.Item) error {
 	if err := s.assertReadWrite(); err != nil {
 		return err
@@ -108,30 +418,101 @@ func (s *Service) Put(ctx context.Context, tableInfo *models.TableInfo, item mod
 	return s.provider.PutItem(ctx, tableInfo.Name, item)
 }
 
+// PutItems puts the items to the table in batches.  Items with the same key as existing items in the table
+// will replace them.
+func (s *Service) PutItems(ctx context.Context, tableInfo *models.TableInfo, items []models.Item) error {
+	if err := s.assertReadWrite(); err != nil {
+		return err
+	}
+
+	return s.provider.PutItems(ctx, tableInfo.Name, items)
+}
+
+// UpdateItem sets and removes attributes of the item with the given key.  Returns an error if the item does
+// not exist.
+func (s *Service) UpdateItem(ctx context.Context, tableInfo *models.TableInfo, key map[string]types.AttributeValue, sets map[string]types.AttributeValue, removes []string) error {
+	if err := s.assertReadWrite(); err != nil {
+		return err
+	}
+
+	setNames := maps.Keys(sets)
+	sort.Strings(setNames)
+
+	var update expression.UpdateBuilder
+	for _, name := range setNames {
+		if name == tableInfo.Keys.PartitionKey || name == tableInfo.Keys.SortKey {
+			return errors.Errorf("cannot update key attribute '%v'", name)
+		}
+		update = update.Set(expression.Name(name), expression.Value(sets[name]))
+	}
+	for _, name := range removes {
+		if name == tableInfo.Keys.PartitionKey || name == tableInfo.Keys.SortKey {
+			return errors.Errorf("cannot remove key attribute '%v'", name)
+		}
+		update = update.Remove(expression.Name(name))
+	}
+
+	updateExpr, err := expression.NewBuilder().
+		WithUpdate(update).
+		WithCondition(expression.AttributeExists(expression.Name(tableInfo.Keys.PartitionKey))).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	if err := s.provider.UpdateItem(ctx, tableInfo.Name, key, updateExpr); err != nil {
+		var ccfe *types.ConditionalCheckFailedException
+		if errors.As(err, &ccfe) {
+			return errors.New("item does not exist")
+		}
+		return err
+	}
+	return nil
+}
+
 func (s *Service) PutItemAt(ctx context.Context, resultSet *models.ResultSet, index int) error {
 	if err := s.assertReadWrite(); err != nil {
 		return err
+	} else if resultSet.Projected {
+		return models.ErrProjectedItems
 	}
 
 	item := resultSet.Items()[index]
+	switch putMode := s.configProvider.PutMode(); putMode {
+	case models.PutModeConditional, models.PutModeTransaction:
+		return s.putSelectedItemsConditionally(ctx, resultSet, []models.ItemIndex{{Index: index, Item: item}}, putMode)
+	}
+
 	if err := s.provider.PutItem(ctx, resultSet.TableInfo.Name, item); err != nil {
 		return err
 	}
 
 	resultSet.SetDirty(index, false)
 	resultSet.SetNew(index, false)
+	resultSet.SetConflict(index, false)
+	resultSet.ResetOriginalItem(index)
+	resultSet.ClearEditHistory()
 	return nil
 }
 
+// PutSelectedItems puts the marked items of the result set.  Items of a result set which was read with only some of
+// their attributes selected cannot be put.
 func (s *Service) PutSelectedItems(ctx context.Context, resultSet *models.ResultSet, markedItems []models.ItemIndex) error {
 	if err := s.assertReadWrite(); err != nil {
 		return err
+	} else if resultSet.Projected {
+		return models.ErrProjectedItems
 	}
 
 	if len(markedItems) == 0 {
 		return nil
 	}
 
+	switch putMode := s.configProvider.PutMode(); putMode {
+	case models.PutModeConditional, models.PutModeTransaction:
+		return s.putSelectedItemsConditionally(ctx, resultSet, markedItems, putMode)
+	}
+
 	if err := s.provider.PutItems(ctx, resultSet.TableInfo.Name, sliceutils.Map(markedItems, func(t models.ItemIndex) models.Item {
 		return t.Item
 	})); err != nil {
@@ -141,6 +522,55 @@ func (s *Service) PutSelectedItems(ctx context.Context, resultSet *models.Result
 	for _, di := range markedItems {
 		resultSet.SetDirty(di.Index, false)
 		resultSet.SetNew(di.Index, false)
+		resultSet.SetConflict(di.Index, false)
+		resultSet.ResetOriginalItem(di.Index)
+	}
+	resultSet.ClearEditHistory()
+	return nil
+}
+
+// putSelectedItemsConditionally puts the items only if the items in the table are unchanged since they were read.
+// Items which have changed are flagged as conflicting and remain dirty.
+func (s *Service) putSelectedItemsConditionally(ctx context.Context, resultSet *models.ResultSet, markedItems []models.ItemIndex, putMode models.PutMode) error {
+	puts := sliceutils.Map(markedItems, func(t models.ItemIndex) models.ConditionalPut {
+		return models.ConditionalPut{Item: t.Item, Original: resultSet.OriginalItem(t.Index)}
+	})
+
+	var (
+		failed []int
+		err    error
+	)
+	if putMode == models.PutModeTransaction {
+		failed, err = s.provider.TransactPutItems(ctx, resultSet.TableInfo, puts)
+	} else {
+		failed, err = s.provider.PutItemsConditionally(ctx, resultSet.TableInfo, puts)
+	}
+	if err != nil {
+		return err
+	}
+
+	failedSet := make(map[int]bool)
+	for _, i := range failed {
+		failedSet[i] = true
+	}
+
+	for i, di := range markedItems {
+		if failedSet[i] {
+			resultSet.SetConflict(di.Index, true)
+			continue
+		}
+
+		resultSet.SetDirty(di.Index, false)
+		resultSet.SetNew(di.Index, false)
+		resultSet.SetConflict(di.Index, false)
+		resultSet.ResetOriginalItem(di.Index)
+	}
+
+	if len(failed) < len(markedItems) {
+		resultSet.ClearEditHistory()
+	}
+	if len(failed) > 0 {
+		return models.NewPutConflictError(len(failed))
 	}
 	return nil
 }
@@ -183,10 +613,26 @@ func (s *Service) assertReadWrite() error {
 	return nil
 }
 
+// Filter hides the items of the result set which do not match the filter.  If the filter is a predicate
+// expression, like "status = "open"", it is evaluated against each item.  Otherwise, items are hidden if
+// none of their attributes contain the filter as a substring.  Items which the predicate cannot be evaluated
+// against are hidden, and are reported in the returned FilterError.
 // TODO: move into a new service
-func (s *Service) Filter(resultSet *models.ResultSet, filter string) *models.ResultSet {
+func (s *Service) Filter(resultSet *models.ResultSet, filter string) (*models.ResultSet, error) {
 	if resultSet == nil {
-		return nil
+		return nil, nil
+	}
+
+	var (
+		evalErr   error
+		evalFails int
+	)
+
+	var filterExpr *queryexpr.QueryExpr
+	if filter != "" {
+		if q, err := queryexpr.Parse(filter); err == nil && q.IsPredicate() {
+			filterExpr = q.WithCurrentResultSet(resultSet)
+		}
 	}
 
 	for i, item := range resultSet.Items() {
@@ -195,6 +641,18 @@ func (s *Service) Filter(resultSet *models.ResultSet, filter string) *models.Res
 			continue
 		}
 
+		if filterExpr != nil {
+			isMatch, err := filterExpr.MatchItem(item)
+			if err != nil {
+				if evalErr == nil {
+					evalErr = err
+				}
+				evalFails++
+			}
+			resultSet.SetHidden(i, err != nil || !isMatch)
+			continue
+		}
+
 		var shouldHide = true
 		for k := range item {
 			str, ok := item.AttributeValueAsString(k)
@@ -211,5 +669,8 @@ func (s *Service) Filter(resultSet *models.ResultSet, filter string) *models.Res
 		resultSet.SetHidden(i, shouldHide)
 	}
 
-	return resultSet
+	if evalErr != nil {
+		return resultSet, models.NewFilterError(evalFails, evalErr)
+	}
+	return resultSet, nil
 }
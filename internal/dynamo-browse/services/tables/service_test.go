@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/providers/dynamo"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/tables"
 	"github.com/lmika/dynamo-browse/test/testdynamo"
@@ -66,6 +68,91 @@ func TestService_Scan(t *testing.T) {
 	})
 }
 
+func TestService_Filter(t *testing.T) {
+	newResultSet := func() *models.ResultSet {
+		rs := &models.ResultSet{TableInfo: &models.TableInfo{
+			Name: "filter-test",
+			Keys: models.KeyAttribute{PartitionKey: "pk", SortKey: "sk"},
+		}}
+		rs.SetItems([]models.Item{
+			{
+				"pk":   &types.AttributeValueMemberS{Value: "abc"},
+				"sk":   &types.AttributeValueMemberS{Value: "111"},
+				"name": &types.AttributeValueMemberS{Value: "john and jane"},
+				"age":  &types.AttributeValueMemberN{Value: "23"},
+			},
+			{
+				"pk":   &types.AttributeValueMemberS{Value: "abc"},
+				"sk":   &types.AttributeValueMemberS{Value: "222"},
+				"name": &types.AttributeValueMemberS{Value: "alice"},
+				"age":  &types.AttributeValueMemberS{Value: "unknown"},
+			},
+		})
+		return rs
+	}
+	visibleSortKeys := func(rs *models.ResultSet) []string {
+		var sks []string
+		for i, item := range rs.Items() {
+			if !rs.Hidden(i) {
+				sks = append(sks, item["sk"].(*types.AttributeValueMemberS).Value)
+			}
+		}
+		return sks
+	}
+
+	service := tables.NewService(nil, mockedConfigProvider{})
+
+	t.Run("should filter items by substring", func(t *testing.T) {
+		scenarios := []struct {
+			filter   string
+			expected []string
+		}{
+			{filter: "", expected: []string{"111", "222"}},
+			{filter: "john and jane", expected: []string{"111"}},
+			{filter: "alice", expected: []string{"222"}},
+			{filter: "not alice", expected: nil},
+			{filter: "222", expected: []string{"222"}},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.filter, func(t *testing.T) {
+				rs, err := service.Filter(newResultSet(), scenario.filter)
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.expected, visibleSortKeys(rs))
+			})
+		}
+	})
+
+	t.Run("should filter items by predicate", func(t *testing.T) {
+		scenarios := []struct {
+			filter   string
+			expected []string
+		}{
+			{filter: `name = "alice"`, expected: []string{"222"}},
+			{filter: `sk = "111" or name = "alice"`, expected: []string{"111", "222"}},
+			{filter: `begins_with(name, "john")`, expected: []string{"111"}},
+			{filter: `pk and not (name = "alice")`, expected: []string{"111"}},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.filter, func(t *testing.T) {
+				rs, err := service.Filter(newResultSet(), scenario.filter)
+				assert.NoError(t, err)
+				assert.Equal(t, scenario.expected, visibleSortKeys(rs))
+			})
+		}
+	})
+
+	t.Run("should hide items the predicate cannot be evaluated against and return an error", func(t *testing.T) {
+		rs, err := service.Filter(newResultSet(), `age + 1 > 20`)
+
+		var filterErr models.FilterError
+		assert.ErrorAs(t, err, &filterErr)
+		assert.Equal(t, 1, filterErr.Count)
+		assert.Equal(t, []string{"111"}, visibleSortKeys(rs))
+	})
+}
+
 var testData = []testdynamo.TestData{
 	{
 		TableName: "service-test-data",
@@ -94,6 +181,8 @@ var testData = []testdynamo.TestData{
 type mockedConfigProvider struct {
 	readOnly     bool
 	defaultLimit int
+	scanSegments int
+	putMode      models.PutMode
 }
 
 func (m mockedConfigProvider) IsReadOnly() (bool, error) {
@@ -106,3 +195,17 @@ func (m mockedConfigProvider) DefaultLimit() int {
 	}
 	return m.defaultLimit
 }
+
+func (m mockedConfigProvider) ScanSegments() int {
+	if m.scanSegments == 0 {
+		return 1
+	}
+	return m.scanSegments
+}
+
+func (m mockedConfigProvider) PutMode() models.PutMode {
+	if m.putMode == "" {
+		return models.PutModeBatch
+	}
+	return m.putMode
+}
@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lmika/dynamo-browse/internal/common/ui/events"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/controllers"
+	"github.com/pkg/errors"
+)
+
+var teaCmdType = reflect.TypeOf(tea.Cmd(nil))
+
+// BatchRunner runs commands and scripts against the model without starting the Bubble Tea program.  Messages
+// returned from the commands are handled in the same way as the program would, with the exception of prompts
+// and status messages.  Confirmation prompts are answered if auto-confirm is set, and all other prompts fail.
+// Status messages are written to the output writer.
+type BatchRunner struct {
+	model       Model
+	out         io.Writer
+	autoConfirm bool
+	msgChan     chan tea.Msg
+	hasQuit     bool
+}
+
+func NewBatchRunner(model Model, out io.Writer, autoConfirm bool) *BatchRunner {
+	return &BatchRunner{
+		model:       model,
+		out:         out,
+		autoConfirm: autoConfirm,
+		msgChan:     make(chan tea.Msg, 64),
+	}
+}
+
+// SendMsg sends a message to the batch runner.  This is to be used as the message sender of the controllers.
+func (br *BatchRunner) SendMsg(msg tea.Msg) {
+	br.msgChan <- msg
+}
+
+// Init runs the init RC file and loads the table.
+func (br *BatchRunner) Init() error {
+	br.model.executeInitRC()
+	return br.process(br.model.tableReadController.Init())
+}
+
+// ExecuteCommands executes each command in order, stopping at the first error or when a command quits.
+func (br *BatchRunner) ExecuteCommands(commands []string) error {
+	for _, cmd := range commands {
+		if br.hasQuit {
+			return nil
+		}
+		if err := br.process(br.model.commandController.Execute(cmd)); err != nil {
+			return errors.Wrapf(err, "%v", cmd)
+		}
+
+		// Commands defined by scripts, along with run-script, run the script in the background
+		if err := br.waitForScripts(); err != nil {
+			return errors.Wrapf(err, "%v", cmd)
+		}
+	}
+	return nil
+}
+
+// waitForScripts waits for any scripts started by a command to finish, handling the messages they send.
+func (br *BatchRunner) waitForScripts() error {
+	doneChan := br.model.scriptController.ScriptsDone()
+	for {
+		select {
+		case msg := <-br.msgChan:
+			if err := br.process(msg); err != nil {
+				return err
+			}
+		case <-doneChan:
+			return br.drainMessages()
+		}
+	}
+}
+
+// RunScript runs the script and waits for it to finish.
+func (br *BatchRunner) RunScript(filename string) error {
+	if br.hasQuit {
+		return nil
+	}
+
+	doneChan := make(chan error)
+	go func() {
+		doneChan <- br.model.scriptController.RunScriptAndWait(filename)
+	}()
+
+	for {
+		select {
+		case msg := <-br.msgChan:
+			if err := br.process(msg); err != nil {
+				return err
+			}
+		case err := <-doneChan:
+			if err != nil {
+				return err
+			}
+			return br.drainMessages()
+		}
+	}
+}
+
+func (br *BatchRunner) drainMessages() error {
+	for {
+		select {
+		case msg := <-br.msgChan:
+			if err := br.process(msg); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func (br *BatchRunner) process(msg tea.Msg) error {
+	queue := []tea.Msg{msg}
+
+	for len(queue) > 0 {
+		msg, queue = queue[0], queue[1:]
+
+		switch m := msg.(type) {
+		case nil:
+			continue
+		case events.ErrorMsg:
+			return m
+		case events.StatusMsg:
+			fmt.Fprintln(br.out, m)
+			continue
+		case events.WrappedStatusMsg:
+			fmt.Fprintln(br.out, m.Message)
+			queue = append(queue, m.Next)
+			continue
+		case events.ForegroundJobUpdate:
+			if m.JobRunning {
+				log.Printf("job: %v", m.JobStatus)
+			}
+			continue
+		case events.PromptForInputMsg:
+			next, err := br.answerPrompt(m)
+			if err != nil {
+				return err
+			}
+			queue = append(queue, next)
+			continue
+		case controllers.ShowDiffOverlay:
+			next, err := br.confirmDiff(m)
+			if err != nil {
+				return err
+			}
+			queue = append(queue, next)
+			continue
+		case controllers.ShowTextOverlay:
+			fmt.Fprint(br.out, m.Text)
+			continue
+		case controllers.ShowRelatedItemsOverlay:
+			if m.OnCancel != nil {
+				m.OnCancel()
+			}
+			return errors.Errorf("selection required: '%v'", m.Title)
+		case controllers.PromptForTableMsg:
+			return errors.New("no table selected")
+		case controllers.NewResultSet:
+			fmt.Fprintln(br.out, m.StatusMessage())
+		}
+
+		if msg == tea.Quit() {
+			br.hasQuit = true
+			return nil
+		} else if cmds, isBatch := batchCmds(msg); isBatch {
+			for _, cmd := range cmds {
+				if cmd != nil {
+					queue = append(queue, cmd())
+				}
+			}
+			continue
+		}
+
+		newModel, cmd := br.model.Update(msg)
+		br.model = newModel.(Model)
+		if cmd != nil {
+			queue = append(queue, cmd())
+		}
+
+		// Pick up any messages sent while handling this one
+		for len(br.msgChan) > 0 {
+			queue = append(queue, <-br.msgChan)
+		}
+	}
+	return nil
+}
+
+func (br *BatchRunner) answerPrompt(prompt events.PromptForInputMsg) (tea.Msg, error) {
+	if prompt.IsConfirmation && br.autoConfirm {
+		fmt.Fprintln(br.out, prompt.Prompt+"y")
+		return prompt.OnDone("y"), nil
+	}
+
+	if prompt.OnCancel != nil {
+		prompt.OnCancel()
+	}
+	if prompt.IsConfirmation {
+		return nil, errors.Errorf("confirmation required: '%v'", prompt.Prompt)
+	}
+	return nil, errors.Errorf("input required: '%v'", prompt.Prompt)
+}
+
+func (br *BatchRunner) confirmDiff(diffOverlay controllers.ShowDiffOverlay) (tea.Msg, error) {
+	for _, item := range diffOverlay.Items {
+		fmt.Fprintln(br.out, item.Description)
+		br.model.itemRendererService.RenderDiff(br.out, item.Diff, true)
+	}
+
+	if br.autoConfirm {
+		fmt.Fprintln(br.out, diffOverlay.Title+"y")
+		return diffOverlay.OnConfirm(), nil
+	}
+
+	if diffOverlay.OnCancel != nil {
+		diffOverlay.OnCancel()
+	}
+	return nil, errors.Errorf("confirmation required: '%v'", diffOverlay.Title)
+}
+
+// batchCmds returns the commands of a message produced by tea.Batch.  The type of this message is not
+// exported so it needs to be detected using reflection.
+func batchCmds(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice || v.Type().Elem() != teaCmdType {
+		return nil, false
+	}
+
+	cmds := make([]tea.Cmd, v.Len())
+	for i := range cmds {
+		cmds[i] = v.Index(i).Interface().(tea.Cmd)
+	}
+	return cmds, true
+}
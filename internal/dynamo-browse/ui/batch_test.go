@@ -0,0 +1,255 @@
+package ui_test
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lmika/dynamo-browse/internal/common/ui/commandctrl"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/controllers"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/providers/dynamo"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/providers/inputhistorystore"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/providers/pasteboardprovider"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/providers/settingstore"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/providers/workspacestore"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/inputhistory"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/itemrenderer"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/jobs"
+	keybindings_service "github.com/lmika/dynamo-browse/internal/dynamo-browse/services/keybindings"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/scriptmanager"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/tables"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/viewsnapshot"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/keybindings"
+	"github.com/lmika/dynamo-browse/test/testdynamo"
+	"github.com/lmika/dynamo-browse/test/testworkspace"
+	bus "github.com/lmika/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchRunner_ExecuteCommands(t *testing.T) {
+	t.Run("should put items if auto-confirm is set", func(t *testing.T) {
+		br := newBatchRunner(t, true, nil)
+
+		assert.NoError(t, br.runner.Init())
+		err := br.runner.ExecuteCommands([]string{`set-attr alpha "new value"`, "put"})
+		assert.NoError(t, err)
+
+		item := br.lookupItem(t, "abc", "111")
+		assert.NotNil(t, item)
+		alpha, _ := item.AttributeValueAsString("alpha")
+		assert.Equal(t, "new value", alpha)
+		assert.Contains(t, br.out.String(), "new value")
+	})
+
+	t.Run("should fail to put items if auto-confirm is not set", func(t *testing.T) {
+		br := newBatchRunner(t, false, nil)
+
+		assert.NoError(t, br.runner.Init())
+		err := br.runner.ExecuteCommands([]string{`set-attr alpha "new value"`, "put"})
+		assert.ErrorContains(t, err, "confirmation required")
+
+		item := br.lookupItem(t, "abc", "111")
+		assert.NotNil(t, item)
+		alpha, _ := item.AttributeValueAsString("alpha")
+		assert.Equal(t, "This is some value", alpha)
+	})
+
+	t.Run("should delete marked items if auto-confirm is set", func(t *testing.T) {
+		br := newBatchRunner(t, true, nil)
+
+		assert.NoError(t, br.runner.Init())
+		err := br.runner.ExecuteCommands([]string{"mark", "delete"})
+		assert.NoError(t, err)
+
+		assert.Nil(t, br.lookupItem(t, "abc", "111"))
+		assert.Nil(t, br.lookupItem(t, "abc", "222"))
+		assert.Nil(t, br.lookupItem(t, "bbb", "131"))
+	})
+
+	t.Run("should fail to delete marked items if auto-confirm is not set", func(t *testing.T) {
+		br := newBatchRunner(t, false, nil)
+
+		assert.NoError(t, br.runner.Init())
+		err := br.runner.ExecuteCommands([]string{"mark", "delete"})
+		assert.ErrorContains(t, err, "confirmation required")
+
+		assert.NotNil(t, br.lookupItem(t, "abc", "111"))
+		assert.NotNil(t, br.lookupItem(t, "abc", "222"))
+		assert.NotNil(t, br.lookupItem(t, "bbb", "131"))
+	})
+
+	t.Run("should fail if a command prompts for a value", func(t *testing.T) {
+		br := newBatchRunner(t, true, nil)
+
+		assert.NoError(t, br.runner.Init())
+		err := br.runner.ExecuteCommands([]string{"set-attr alpha"})
+		assert.ErrorContains(t, err, "input required")
+	})
+
+	t.Run("should stop at the first error", func(t *testing.T) {
+		br := newBatchRunner(t, true, nil)
+
+		assert.NoError(t, br.runner.Init())
+		err := br.runner.ExecuteCommands([]string{"echo first", "not-a-command", "echo second"})
+		assert.Error(t, err)
+
+		assert.Contains(t, br.out.String(), "first")
+		assert.NotContains(t, br.out.String(), "second")
+	})
+
+	t.Run("should stop executing commands after quitting", func(t *testing.T) {
+		br := newBatchRunner(t, true, nil)
+
+		assert.NoError(t, br.runner.Init())
+		err := br.runner.ExecuteCommands([]string{"echo first", "quit", "echo second"})
+		assert.NoError(t, err)
+
+		assert.Contains(t, br.out.String(), "first")
+		assert.NotContains(t, br.out.String(), "second")
+	})
+
+	t.Run("should wait for scripts to finish and return their errors", func(t *testing.T) {
+		br := newBatchRunner(t, true, fstest.MapFS{
+			"good.tm": &fstest.MapFile{Data: []byte(`ui.print("from the script")`)},
+			"bad.tm":  &fstest.MapFile{Data: []byte(`assert(false, "bang")`)},
+		})
+
+		assert.NoError(t, br.runner.Init())
+
+		err := br.runner.ExecuteCommands([]string{"run-script good.tm"})
+		assert.NoError(t, err)
+		assert.Contains(t, br.out.String(), "from the script")
+
+		err = br.runner.ExecuteCommands([]string{"run-script bad.tm", "echo after"})
+		assert.Error(t, err)
+		assert.NotContains(t, br.out.String(), "after")
+	})
+}
+
+type batchRunnerTest struct {
+	runner       *ui.BatchRunner
+	out          *bytes.Buffer
+	tableService *tables.Service
+}
+
+func (br *batchRunnerTest) lookupItem(t *testing.T, pk, sk string) models.Item {
+	t.Helper()
+
+	ctx := context.Background()
+	tableInfo, err := br.tableService.Describe(ctx, "alpha-table")
+	assert.NoError(t, err)
+
+	rs, err := br.tableService.Scan(ctx, tableInfo)
+	assert.NoError(t, err)
+
+	for _, item := range rs.Items() {
+		itemPK, _ := item.AttributeValueAsString("pk")
+		itemSK, _ := item.AttributeValueAsString("sk")
+		if itemPK == pk && itemSK == sk {
+			return item
+		}
+	}
+	return nil
+}
+
+func newBatchRunner(t *testing.T, autoConfirm bool, scriptFS fs.FS) *batchRunnerTest {
+	ws := testworkspace.New(t)
+
+	resultSetSnapshotStore := workspacestore.NewResultSetSnapshotStore(ws)
+	settingStore := settingstore.New(ws)
+	inputHistoryStore := inputhistorystore.NewInputHistoryStore(ws)
+
+	workspaceService := viewsnapshot.NewService(resultSetSnapshotStore)
+	itemRendererService := itemrenderer.NewService(itemrenderer.PlainTextRenderer(), itemrenderer.PlainTextRenderer())
+	scriptService := scriptmanager.New()
+	inputHistoryService := inputhistory.New(inputHistoryStore)
+
+	client := testdynamo.SetupTestTable(t, testData)
+
+	provider := dynamo.NewProvider(client)
+	service := tables.NewService(provider, settingStore)
+	eventBus := bus.New()
+
+	state := controllers.NewState()
+	jobsController := controllers.NewJobsController(jobs.NewService(eventBus), eventBus, true)
+	readController := controllers.NewTableReadController(
+		state,
+		service,
+		workspaceService,
+		itemRendererService,
+		jobsController,
+		inputHistoryService,
+		eventBus,
+		pasteboardprovider.NilProvider{},
+		scriptService,
+		"alpha-table",
+	)
+	writeController := controllers.NewTableWriteController(state, service, jobsController, readController, settingStore)
+	settingsController := controllers.NewSettingsController(settingStore, eventBus)
+	columnsController := controllers.NewColumnsController(readController, eventBus)
+	exportController := controllers.NewExportController(state, service, jobsController, columnsController, pasteboardprovider.NilProvider{})
+	scriptController := controllers.NewScriptController(scriptService, readController, writeController, columnsController, jobsController, settingsController, eventBus)
+
+	keyBindings := keybindings.Default()
+	keyBindingService := keybindings_service.NewService(keyBindings)
+	keyBindingController := controllers.NewKeyBindingController(keyBindingService, scriptController)
+
+	commandController := commandctrl.NewCommandController(inputHistoryService)
+	commandController.AddCommandLookupExtension(scriptController)
+
+	model := ui.NewModel(
+		readController,
+		writeController,
+		columnsController,
+		exportController,
+		settingsController,
+		jobsController,
+		itemRendererService,
+		commandController,
+		scriptController,
+		eventBus,
+		keyBindingController,
+		pasteboardprovider.NilProvider{},
+		keyBindings,
+	)
+
+	out := new(bytes.Buffer)
+	runner := ui.NewBatchRunner(model, out, autoConfirm)
+	jobsController.SetMessageSender(runner.SendMsg)
+	scriptController.Init()
+	scriptController.SetMessageSender(runner.SendMsg)
+
+	// Initting will setup the default script lookup paths, so revert them to the test ones
+	if scriptFS != nil {
+		scriptService.SetLookupPaths([]fs.FS{scriptFS})
+	}
+
+	return &batchRunnerTest{runner: runner, out: out, tableService: service}
+}
+
+var testData = []testdynamo.TestData{
+	{
+		TableName: "alpha-table",
+		Data: []map[string]interface{}{
+			{
+				"pk":    "abc",
+				"sk":    "111",
+				"alpha": "This is some value",
+			},
+			{
+				"pk":    "abc",
+				"sk":    "222",
+				"alpha": "This is another some value",
+			},
+			{
+				"pk":   "bbb",
+				"sk":   "131",
+				"beta": 2468,
+			},
+		},
+	},
+}
@@ -40,6 +40,8 @@ func Default() *KeyBindings {
 			PromptForCommand:     key.NewBinding(key.WithKeys(":"), key.WithHelp(":", "prompt for command")),
 			ShowColumnOverlay:    key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "show column overlay")),
 			ShowRelItemsOverlay:  key.NewBinding(key.WithKeys("O"), key.WithHelp("O", "show related items overlay")),
+			Undo:                 key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "undo item edit")),
+			Redo:                 key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "redo item edit")),
 			CancelRunningJob:     key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "cancel running job or quit")),
 			Quit:                 key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "quit")),
 		},
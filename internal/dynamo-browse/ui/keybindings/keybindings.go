@@ -47,6 +47,8 @@ type ViewKeyBindings struct {
 	PromptForCommand     key.Binding `keymap:"prompt-for-command"`
 	ShowColumnOverlay    key.Binding `keymap:"show-fields-popup"`
 	ShowRelItemsOverlay  key.Binding `keymap:"show-rel-items-popup"`
+	Undo                 key.Binding `keymap:"undo"`
+	Redo                 key.Binding `keymap:"redo"`
 	CancelRunningJob     key.Binding `keymap:"cancel-running-job"`
 	Quit                 key.Binding `keymap:"quit"`
 }
@@ -11,11 +11,13 @@ import (
 	"github.com/lmika/dynamo-browse/internal/common/ui/events"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/controllers"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/models/aggregate"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/itemrenderer"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/keybindings"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/colselector"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/dialogprompt"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/diffview"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/dynamoitemedit"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/dynamoitemview"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/dynamotableview"
@@ -24,6 +26,7 @@ import (
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/statusandprompt"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/styles"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/tableselect"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/textview"
 	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/utils"
 	bus "github.com/lmika/events"
 	"github.com/pkg/errors"
@@ -51,10 +54,13 @@ type Model struct {
 	jobController        *controllers.JobsController
 	colSelector          *colselector.Model
 	relSelector          *relselector.Model
+	diffView             *diffview.Model
+	textView             *textview.Model
 	itemEdit             *dynamoitemedit.Model
 	statusAndPrompt      *statusandprompt.StatusAndPrompt
 	tableSelect          *tableselect.Model
 	eventBus             *bus.Bus
+	itemRendererService  *itemrenderer.Service
 
 	mainViewIndex int
 
@@ -89,7 +95,9 @@ func NewModel(
 
 	colSelector := colselector.New(mainView, defaultKeyMap, columnsController)
 	relSelector := relselector.New(colSelector)
-	itemEdit := dynamoitemedit.NewModel(relSelector)
+	diffView := diffview.New(relSelector, itemRendererService)
+	textView := textview.New(diffView)
+	itemEdit := dynamoitemedit.NewModel(textView)
 	statusAndPrompt := statusandprompt.New(itemEdit, pasteboardProvider, "", uiStyles.StatusAndPrompt)
 	dialogPrompt := dialogprompt.New(statusAndPrompt)
 	tableSelect := tableselect.New(dialogPrompt, uiStyles)
@@ -104,18 +112,75 @@ func NewModel(
 					return rc.ScanTable(args[0])
 				}
 			},
+			"connect": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
+				if len(args) == 0 {
+					return events.StatusMsg("connected to " + rc.Connection().String())
+				}
+
+				conn := models.Connection{Profile: args[0]}
+				if len(args) > 1 {
+					conn.Region = args[1]
+				}
+				if len(args) > 2 {
+					conn.Endpoint = args[2]
+				}
+				return rc.Connect(conn)
+			},
+			"describe": commandctrl.NoArgCommand(rc.DescribeTable),
 			"export": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
 				if len(args) == 0 {
 					return events.Error(errors.New("expected filename"))
 				}
 
 				opts := controllers.ExportOptions{}
-				if len(args) == 2 && args[0] == "-all" {
-					opts.AllResults = true
-					args = args[1:]
+				for len(args) > 1 {
+					switch args[0] {
+					case "-all":
+						opts.AllResults = true
+						args = args[1:]
+					case "-format":
+						format, err := controllers.ParseExportFormat(args[1])
+						if err != nil {
+							return events.Error(err)
+						}
+						opts.Format = format
+						args = args[2:]
+					default:
+						return events.Error(errors.Errorf("unrecognised option: %v", args[0]))
+					}
+				}
+				if len(args) != 1 {
+					return events.Error(errors.New("expected filename"))
+				}
+
+				return exportController.Export(args[0], opts)
+			},
+			"import": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
+				if len(args) == 0 {
+					return events.Error(errors.New("expected filename"))
+				}
+
+				var opts controllers.ImportOptions
+				if len(args) == 3 && args[0] == "-format" {
+					format, err := controllers.ParseExportFormat(args[1])
+					if err != nil {
+						return events.Error(err)
+					}
+					opts.Format = format
+					args = args[2:]
+				} else if len(args) == 1 {
+					opts.Format = controllers.FormatFromFilename(args[0])
+				} else {
+					return events.Error(errors.New("expected: [-format fmt] filename"))
 				}
 
-				return exportController.ExportCSV(args[0], opts)
+				return wc.ImportItems(args[0], opts)
+			},
+			"get-keys": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
+				if len(args) != 1 {
+					return events.Error(errors.New("expected: filename (or - for pasteboard)"))
+				}
+				return rc.GetKeys(args[0])
 			},
 			"mark": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
 				var markOp = controllers.MarkOpMark
@@ -139,6 +204,38 @@ func NewModel(
 
 				return rc.Mark(markOp, whereExpr)
 			},
+			"aggregate": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
+				if len(args) == 0 {
+					return events.Error(errors.New("expected: func [value] [-by expr] [-where expr]"))
+				}
+
+				fn, args := args[0], args[1:]
+				var value, groupBy, whereExpr string
+				if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+					value, args = args[0], args[1:]
+				}
+				for len(args) > 0 {
+					if len(args) < 2 {
+						return events.Error(errors.Errorf("expected value for option: %v", args[0]))
+					}
+
+					switch args[0] {
+					case "-by":
+						groupBy = args[1]
+					case "-where":
+						whereExpr = args[1]
+					default:
+						return events.Error(errors.Errorf("unrecognised option: %v", args[0]))
+					}
+					args = args[2:]
+				}
+
+				agg, err := aggregate.New(fn, value, groupBy)
+				if err != nil {
+					return events.Error(err)
+				}
+				return rc.Aggregate(agg, whereExpr)
+			},
 			"next-page": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
 				return rc.NextPage()
 			},
@@ -155,7 +252,7 @@ func NewModel(
 				}
 
 				var itemType = models.UnsetItemType
-				if len(args) == 2 {
+				if strings.HasPrefix(args[0], "-") {
 					switch strings.ToUpper(args[0]) {
 					case "-S":
 						itemType = models.StringItemType
@@ -172,8 +269,57 @@ func NewModel(
 					}
 					args = args[1:]
 				}
+				if len(args) == 0 || len(args) > 2 {
+					return events.Error(errors.New("expected: [-type] field [value]"))
+				}
 
-				return wc.SetAttributeValue(dtv.SelectedItemIndex(), itemType, args[0])
+				msg := wc.SetAttributeValue(dtv.SelectedItemIndex(), itemType, args[0])
+				if len(args) == 2 {
+					// Value was supplied as an argument, so answer the prompt with it
+					if prompt, isPrompt := msg.(events.PromptForInputMsg); isPrompt {
+						return prompt.OnDone(args[1])
+					}
+				}
+				return msg
+			},
+			"copy-to": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
+				var remap string
+				if len(args) == 3 && args[1] == "-remap" {
+					remap = args[2]
+					args = args[:1]
+				}
+				if len(args) != 1 {
+					return events.Error(errors.New("expected: table [-remap 'attr=expr,...']"))
+				}
+				return wc.CopyItemsTo(dtv.SelectedItemIndex(), args[0], remap)
+			},
+			"bulk-delete": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
+				if len(args) != 2 || args[0] != "-where" {
+					return events.Error(errors.New("expected: -where expr"))
+				}
+				return wc.BulkDelete(args[1])
+			},
+			"bulk-update": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
+				var whereExpr, setExpr string
+				for len(args) > 0 {
+					if len(args) < 2 {
+						return events.Error(errors.Errorf("expected value for option: %v", args[0]))
+					}
+
+					switch args[0] {
+					case "-where":
+						whereExpr = args[1]
+					case "-set":
+						setExpr = args[1]
+					default:
+						return events.Error(errors.Errorf("unrecognised option: %v", args[0]))
+					}
+					args = args[2:]
+				}
+				if whereExpr == "" || setExpr == "" {
+					return events.Error(errors.New("expected: -where expr -set 'attr = expr,...'"))
+				}
+				return wc.BulkUpdate(whereExpr, setExpr)
 			},
 			"del-attr": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
 				if len(args) == 0 {
@@ -182,6 +328,9 @@ func NewModel(
 				return wc.DeleteAttribute(dtv.SelectedItemIndex(), args[0])
 			},
 
+			"undo": commandctrl.NoArgCommand(wc.Undo),
+			"redo": commandctrl.NoArgCommand(wc.Redo),
+
 			"put": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
 				return wc.PutItems()
 			},
@@ -216,11 +365,22 @@ func NewModel(
 			},
 
 			"run-script": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
-				if len(args) != 1 {
-					return events.Error(errors.New("expected: script name"))
+				if len(args) == 2 && args[0] == "-bg" {
+					return scriptController.RunScriptInBackground(args[1])
+				} else if len(args) != 1 {
+					return events.Error(errors.New("expected: [-bg] script name"))
 				}
 				return scriptController.RunScript(args[0])
 			},
+			"jobs": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
+				return scriptController.ListBackgroundJobs()
+			},
+			"cancel-job": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
+				if len(args) != 1 {
+					return events.Error(errors.New("expected: job ID"))
+				}
+				return scriptController.CancelBackgroundJob(args[0])
+			},
 			"load-script": func(ctx commandctrl.ExecContext, args []string) tea.Msg {
 				if len(args) != 1 {
 					return events.Error(errors.New("expected: script name"))
@@ -249,6 +409,9 @@ func NewModel(
 		itemEdit:             itemEdit,
 		colSelector:          colSelector,
 		relSelector:          relSelector,
+		diffView:             diffView,
+		textView:             textView,
+		itemRendererService:  itemRendererService,
 		statusAndPrompt:      statusAndPrompt,
 		tableSelect:          tableSelect,
 		root:                 root,
@@ -272,7 +435,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		)
 	case tea.KeyMsg:
 		// TODO: use modes here
-		if !m.statusAndPrompt.InPrompt() && !m.tableSelect.Visible() && !m.colSelector.ColSelectorVisible() && !m.relSelector.SelectorVisible() {
+		if !m.statusAndPrompt.InPrompt() && !m.tableSelect.Visible() && !m.colSelector.ColSelectorVisible() && !m.relSelector.SelectorVisible() && !m.diffView.Visible() && !m.textView.Visible() {
 			switch {
 			case key.Matches(msg, m.keyMap.Mark):
 				if idx := m.tableView.SelectedItemIndex(); idx >= 0 {
@@ -311,6 +474,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if idx := m.tableView.SelectedItemIndex(); idx >= 0 {
 					return m, events.SetTeaMessage(m.scriptController.LookupRelatedItems(idx))
 				}
+			case key.Matches(msg, m.keyMap.Undo):
+				return m, m.tableWriteController.Undo
+			case key.Matches(msg, m.keyMap.Redo):
+				return m, m.tableWriteController.Redo
 			case key.Matches(msg, m.keyMap.PromptForCommand):
 				return m, m.commandController.Prompt
 			case key.Matches(msg, m.keyMap.PromptForTable):
@@ -333,11 +500,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) Init() tea.Cmd {
-	// TODO: this should probably be moved somewhere else
-	rcFilename := os.ExpandEnv(initRCFilename)
-	if err := m.commandController.ExecuteFile(rcFilename); err != nil {
-		log.Println(err)
-	}
+	m.executeInitRC()
 
 	return tea.Batch(
 		m.tableReadController.Init,
@@ -345,6 +508,14 @@ func (m Model) Init() tea.Cmd {
 	)
 }
 
+func (m Model) executeInitRC() {
+	// TODO: this should probably be moved somewhere else
+	rcFilename := os.ExpandEnv(initRCFilename)
+	if err := m.commandController.ExecuteFile(rcFilename); err != nil {
+		log.Println(err)
+	}
+}
+
 func (m Model) View() string {
 	return m.root.View()
 }
@@ -0,0 +1,125 @@
+package diffview
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lmika/dynamo-browse/internal/common/ui/events"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/controllers"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/services/itemrenderer"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/layout"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/scrolloverlay"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/utils"
+)
+
+var (
+	itemHeaderStyle = lipgloss.NewStyle().Bold(true)
+	addedStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#73C653"))
+	removedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#E06C75"))
+	changedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#E5C07B"))
+
+	keyConfirm = key.NewBinding(key.WithKeys("y"))
+	keyCancel  = key.NewBinding(key.WithKeys("n", tea.KeyEsc.String(), tea.KeyCtrlC.String()))
+)
+
+type Model struct {
+	overlay             *scrolloverlay.Model
+	itemRendererService *itemrenderer.Service
+}
+
+func New(subModel tea.Model, itemRendererService *itemrenderer.Service) *Model {
+	return &Model{
+		overlay:             scrolloverlay.New(subModel),
+		itemRendererService: itemRendererService,
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.overlay.Init()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case controllers.ShowDiffOverlay:
+		m.overlay.Show(strings.TrimSpace(msg.Title)+" (y/n)", m.renderDiff(msg), keyHandler(msg))
+		return m, nil
+	case controllers.HideDiffOverlay:
+		m.overlay.Hide()
+		return m, nil
+	}
+
+	_, cmd := m.overlay.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	return m.overlay.View()
+}
+
+func (m *Model) Resize(w, h int) layout.ResizingModel {
+	m.overlay.Resize(w, h)
+	return m
+}
+
+func (m *Model) Visible() bool {
+	return m.overlay.Visible()
+}
+
+func keyHandler(event controllers.ShowDiffOverlay) scrolloverlay.KeyHandler {
+	return func(msg tea.KeyMsg) (tea.Cmd, bool) {
+		var cc utils.CmdCollector
+
+		switch {
+		case key.Matches(msg, keyConfirm):
+			if onConfirm := event.OnConfirm; onConfirm != nil {
+				cc.Add(func() tea.Msg { return onConfirm() })
+			}
+		case key.Matches(msg, keyCancel):
+			if onCancel := event.OnCancel; onCancel != nil {
+				cc.Add(func() tea.Msg { return onCancel() })
+			}
+		default:
+			return nil, false
+		}
+
+		cc.Add(events.SetTeaMessage(controllers.HideDiffOverlay{}))
+		return cc.Cmd(), true
+	}
+}
+
+func (m *Model) renderDiff(event controllers.ShowDiffOverlay) string {
+	content := new(strings.Builder)
+	for i, item := range event.Items {
+		if i > 0 {
+			content.WriteString("\n")
+		}
+		content.WriteString(itemHeaderStyle.Render(item.Description))
+		content.WriteString("\n")
+
+		diffContent := new(strings.Builder)
+		m.itemRendererService.RenderDiff(diffContent, item.Diff, false)
+		for _, line := range strings.Split(strings.TrimSuffix(diffContent.String(), "\n"), "\n") {
+			content.WriteString(styleDiffMarker(line))
+			content.WriteString("\n")
+		}
+	}
+	return content.String()
+}
+
+func styleDiffMarker(line string) string {
+	if len(line) == 0 {
+		return line
+	}
+
+	switch line[0] {
+	case '+':
+		return addedStyle.Render(line[:1]) + line[1:]
+	case '-':
+		return removedStyle.Render(line[:1]) + line[1:]
+	case '~':
+		return changedStyle.Render(line[:1]) + line[1:]
+	}
+	return line
+}
@@ -81,6 +81,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case controllers.ColumnsUpdated:
 		m.rebuildTable(&m.table)
 		return m, m.postSelectedItemChanged
+	case controllers.ScriptValuesUpdated:
+		m.table.UpdateView()
+		return m, nil
 	case controllers.SettingsUpdated:
 		m.updateTableHeading()
 		return m, nil
@@ -18,6 +18,8 @@ var (
 			Foreground(lipgloss.Color("#e13131"))
 	newRowStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.AdaptiveColor{Light: "#2B800C", Dark: "#73C653"})
+	conflictRowStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.AdaptiveColor{Light: "#C66A00", Dark: "#F0A030"})
 
 	metaInfoStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#888888"))
@@ -34,6 +36,7 @@ func (mtr itemTableRow) Render(w io.Writer, model table.Model, index int) {
 	isMarked := mtr.resultSet.Marked(mtr.itemIndex)
 	isDirty := mtr.resultSet.IsDirty(mtr.itemIndex)
 	isNew := mtr.resultSet.IsNew(mtr.itemIndex)
+	isConflict := mtr.resultSet.IsConflict(mtr.itemIndex)
 
 	var style lipgloss.Style
 
@@ -43,7 +46,9 @@ func (mtr itemTableRow) Render(w io.Writer, model table.Model, index int) {
 	if isMarked {
 		style = style.Copy().Inherit(markedRowStyle)
 	}
-	if isNew {
+	if isConflict {
+		style = style.Copy().Inherit(conflictRowStyle)
+	} else if isNew {
 		style = style.Copy().Inherit(newRowStyle)
 	} else if isDirty {
 		style = style.Copy().Inherit(dirtyRowStyle)
@@ -54,6 +59,8 @@ func (mtr itemTableRow) Render(w io.Writer, model table.Model, index int) {
 
 	// The status column
 	switch {
+	case isConflict:
+		sb.WriteString(style.Render("!\t"))
 	case isNew:
 		sb.WriteString(style.Render("*\t"))
 	case isDirty:
@@ -92,6 +92,9 @@ func (m *listModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, events.SetTeaMessage(controllers.HideRelatedItemsOverlay{})
 		case key.Matches(msg, keyEsc):
+			if onCancel := m.event.OnCancel; onCancel != nil {
+				cc.Add(events.SetTeaMessage(onCancel()))
+			}
 			return m, events.SetTeaMessage(controllers.HideRelatedItemsOverlay{})
 		default:
 			m.list = cc.Collect(m.list.Update(msg)).(list.Model)
@@ -105,7 +108,7 @@ func (m *listModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *listModel) View() string {
 	innerView := lipgloss.JoinVertical(
 		lipgloss.Top,
-		lipgloss.PlaceHorizontal(overlayWidth-2, lipgloss.Center, "Related Items"),
+		lipgloss.PlaceHorizontal(overlayWidth-2, lipgloss.Center, m.event.Title),
 		frameStyle.Render(strings.Repeat(lipgloss.NormalBorder().Top, overlayWidth-2)),
 		m.list.View(),
 	)
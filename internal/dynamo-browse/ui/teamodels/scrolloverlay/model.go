@@ -0,0 +1,87 @@
+package scrolloverlay
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/layout"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/utils"
+)
+
+const (
+	overlayMarginX = 4
+	overlayMarginY = 2
+)
+
+// KeyHandler handles a key pressed while the overlay is visible.  It returns false if the key was not handled,
+// in which case the key is used to scroll the overlay.
+type KeyHandler func(msg tea.KeyMsg) (tea.Cmd, bool)
+
+// Model displays a scrollable panel of text over a sub-model.  While the panel is visible, key messages go to the
+// panel.  All other messages go to the sub-model.
+type Model struct {
+	subModel   tea.Model
+	compositor *layout.Compositor
+	panel      *panel
+	w, h       int
+}
+
+func New(subModel tea.Model) *Model {
+	return &Model{
+		subModel:   subModel,
+		compositor: layout.NewCompositor(subModel),
+		panel:      newPanel(),
+	}
+}
+
+// Show displays the panel with the title and content.  Keys pressed while the panel is visible are passed to
+// onKey before being used to scroll the panel.
+func (m *Model) Show(title string, content string, onKey KeyHandler) {
+	m.panel.setContent(title, content, onKey)
+	m.compositor.SetOverlay(m.panel, overlayMarginX, overlayMarginY, m.overlayWidth(), m.overlayHeight())
+	m.panel.Resize(m.overlayWidth(), m.overlayHeight())
+}
+
+// Hide hides the panel.
+func (m *Model) Hide() {
+	m.compositor.ClearOverlay()
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.compositor.Init()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cc utils.CmdCollector
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.compositor = cc.Collect(m.compositor.Update(msg)).(*layout.Compositor)
+	default:
+		m.subModel = cc.Collect(m.subModel.Update(msg)).(tea.Model)
+	}
+	return m, cc.Cmd()
+}
+
+func (m *Model) View() string {
+	return m.compositor.View()
+}
+
+func (m *Model) Resize(w, h int) layout.ResizingModel {
+	m.w, m.h = w, h
+	m.subModel = layout.Resize(m.subModel, w, h)
+	if m.compositor.HasOverlay() {
+		m.compositor.SetOverlay(m.panel, overlayMarginX, overlayMarginY, m.overlayWidth(), m.overlayHeight())
+		m.panel.Resize(m.overlayWidth(), m.overlayHeight())
+	}
+	return m
+}
+
+func (m *Model) Visible() bool {
+	return m.compositor.HasOverlay()
+}
+
+func (m *Model) overlayWidth() int {
+	return utils.Max(m.w-overlayMarginX*2, 20)
+}
+
+func (m *Model) overlayHeight() int {
+	return utils.Max(m.h-overlayMarginY*2, 8)
+}
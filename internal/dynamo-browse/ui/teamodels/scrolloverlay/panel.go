@@ -0,0 +1,78 @@
+package scrolloverlay
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/layout"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/utils"
+)
+
+var (
+	frameColor = lipgloss.Color("63")
+
+	frameStyle = lipgloss.NewStyle().
+			Foreground(frameColor)
+	style = lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(frameColor)
+)
+
+type panel struct {
+	title    string
+	onKey    KeyHandler
+	viewport viewport.Model
+	w, h     int
+}
+
+func newPanel() *panel {
+	return &panel{
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (m *panel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *panel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cc utils.CmdCollector
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.onKey != nil {
+			if cmd, handled := m.onKey(msg); handled {
+				return m, cmd
+			}
+		}
+		m.viewport = cc.Collect(m.viewport.Update(msg)).(viewport.Model)
+	}
+	return m, cc.Cmd()
+}
+
+func (m *panel) View() string {
+	innerView := lipgloss.JoinVertical(
+		lipgloss.Top,
+		lipgloss.PlaceHorizontal(m.w-2, lipgloss.Center, m.title),
+		frameStyle.Render(strings.Repeat(lipgloss.NormalBorder().Top, m.w-2)),
+		m.viewport.View(),
+	)
+
+	return style.Width(m.w - 2).Height(m.h - 2).Render(innerView)
+}
+
+func (m *panel) Resize(w, h int) layout.ResizingModel {
+	m.w, m.h = w, h
+	m.viewport.Width = utils.Max(w-2, 0)
+	m.viewport.Height = utils.Max(h-4, 0)
+	return m
+}
+
+func (m *panel) setContent(title string, content string, onKey KeyHandler) {
+	m.title = title
+	m.onKey = onKey
+	m.viewport.SetContent(content)
+	m.viewport.GotoTop()
+}
@@ -0,0 +1,62 @@
+package textview
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lmika/dynamo-browse/internal/common/ui/events"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/controllers"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/layout"
+	"github.com/lmika/dynamo-browse/internal/dynamo-browse/ui/teamodels/scrolloverlay"
+)
+
+var (
+	keyClose = key.NewBinding(key.WithKeys("q", tea.KeyEsc.String(), tea.KeyCtrlC.String()))
+)
+
+type Model struct {
+	overlay *scrolloverlay.Model
+}
+
+func New(subModel tea.Model) *Model {
+	return &Model{
+		overlay: scrolloverlay.New(subModel),
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.overlay.Init()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case controllers.ShowTextOverlay:
+		m.overlay.Show(msg.Title, msg.Text, handleKey)
+		return m, nil
+	case controllers.HideTextOverlay:
+		m.overlay.Hide()
+		return m, nil
+	}
+
+	_, cmd := m.overlay.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	return m.overlay.View()
+}
+
+func (m *Model) Resize(w, h int) layout.ResizingModel {
+	m.overlay.Resize(w, h)
+	return m
+}
+
+func (m *Model) Visible() bool {
+	return m.overlay.Visible()
+}
+
+func handleKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if key.Matches(msg, keyClose) {
+		return events.SetTeaMessage(controllers.HideTextOverlay{}), true
+	}
+	return nil, false
+}
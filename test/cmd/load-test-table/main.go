@@ -146,6 +146,14 @@ func (n notROService) DefaultLimit() int {
 	return 1000
 }
 
+func (n notROService) ScanSegments() int {
+	return 1
+}
+
 func (n notROService) IsReadOnly() (bool, error) {
 	return false, nil
 }
+
+func (n notROService) PutMode() models.PutMode {
+	return models.PutModeBatch
+}